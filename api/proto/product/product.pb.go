@@ -9,6 +9,7 @@ package product
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,9 +22,13 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// currency verilirse price ve varyant fiyatları o para biriminde döner
 type GetProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Currency string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Dil etiketi ya da Accept-Language listesi; boşsa katalog varsayılan dili
+	Locale        string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,6 +70,20 @@ func (x *GetProductRequest) GetId() uint32 {
 	return 0
 }
 
+func (x *GetProductRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetProductRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type GetProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -112,6 +131,8 @@ func (x *GetProductResponse) GetProduct() *Product {
 type GetProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ids           []uint32               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Locale        string                 `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -153,9 +174,27 @@ func (x *GetProductsRequest) GetIds() []uint32 {
 	return nil
 }
 
+func (x *GetProductsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetProductsRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 type GetProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// Silinmiş (çöp kutusundaki) ürünler
+	DeletedIds []uint32 `protobuf:"varint,2,rep,packed,name=deleted_ids,json=deletedIds,proto3" json:"deleted_ids,omitempty"`
+	// Hiç var olmamış ya da kalıcı olarak silinmiş ürünler
+	MissingIds    []uint32 `protobuf:"varint,3,rep,packed,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -197,35 +236,42 @@ func (x *GetProductsResponse) GetProducts() []*Product {
 	return nil
 }
 
-type Product struct {
+func (x *GetProductsResponse) GetDeletedIds() []uint32 {
+	if x != nil {
+		return x.DeletedIds
+	}
+	return nil
+}
+
+func (x *GetProductsResponse) GetMissingIds() []uint32 {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+// ids boş ise tüm ürünlerin değişiklikleri yayınlanır
+type WatchProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
-	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
-	ImageUrl      string                 `protobuf:"bytes,7,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Ids           []uint32               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Product) Reset() {
-	*x = Product{}
+func (x *WatchProductsRequest) Reset() {
+	*x = WatchProductsRequest{}
 	mi := &file_api_proto_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Product) String() string {
+func (x *WatchProductsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Product) ProtoMessage() {}
+func (*WatchProductsRequest) ProtoMessage() {}
 
-func (x *Product) ProtoReflect() protoreflect.Message {
+func (x *WatchProductsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -237,103 +283,970 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Product.ProtoReflect.Descriptor instead.
-func (*Product) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchProductsRequest.ProtoReflect.Descriptor instead.
+func (*WatchProductsRequest) Descriptor() ([]byte, []int) {
 	return file_api_proto_product_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Product) GetId() uint32 {
+func (x *WatchProductsRequest) GetIds() []uint32 {
 	if x != nil {
-		return x.Id
+		return x.Ids
+	}
+	return nil
+}
+
+type ProductEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	ProductId     uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	OccurredAt    string                 `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductEvent) Reset() {
+	*x = ProductEvent{}
+	mi := &file_api_proto_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductEvent) ProtoMessage() {}
+
+func (x *ProductEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductEvent.ProtoReflect.Descriptor instead.
+func (*ProductEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProductEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
 	}
 	return 0
 }
 
-func (x *Product) GetName() string {
+func (x *ProductEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+// Yazma RPC'lerinde düzenlenebilir alanlar; category_id 0 ise kategori yok
+type ProductInput struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description      string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price            float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	ReorderThreshold int32                  `protobuf:"varint,4,opt,name=reorder_threshold,json=reorderThreshold,proto3" json:"reorder_threshold,omitempty"`
+	CategoryId       uint32                 `protobuf:"varint,5,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	ImageUrl         string                 `protobuf:"bytes,6,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	ExternalId       string                 `protobuf:"bytes,7,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// Boşsa "standard"
+	TaxClass string  `protobuf:"bytes,8,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	WeightKg float64 `protobuf:"fixed64,9,opt,name=weight_kg,json=weightKg,proto3" json:"weight_kg,omitempty"`
+	LengthCm float64 `protobuf:"fixed64,10,opt,name=length_cm,json=lengthCm,proto3" json:"length_cm,omitempty"`
+	WidthCm  float64 `protobuf:"fixed64,11,opt,name=width_cm,json=widthCm,proto3" json:"width_cm,omitempty"`
+	HeightCm float64 `protobuf:"fixed64,12,opt,name=height_cm,json=heightCm,proto3" json:"height_cm,omitempty"`
+	// Boşsa TRY
+	Currency      string `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductInput) Reset() {
+	*x = ProductInput{}
+	mi := &file_api_proto_product_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductInput) ProtoMessage() {}
+
+func (x *ProductInput) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductInput.ProtoReflect.Descriptor instead.
+func (*ProductInput) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProductInput) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *Product) GetDescription() string {
+func (x *ProductInput) GetDescription() string {
 	if x != nil {
 		return x.Description
 	}
 	return ""
 }
 
-func (x *Product) GetPrice() float64 {
+func (x *ProductInput) GetPrice() float64 {
 	if x != nil {
 		return x.Price
 	}
 	return 0
 }
 
-func (x *Product) GetStock() int32 {
+func (x *ProductInput) GetReorderThreshold() int32 {
 	if x != nil {
-		return x.Stock
+		return x.ReorderThreshold
 	}
 	return 0
 }
 
-func (x *Product) GetCategory() string {
+func (x *ProductInput) GetCategoryId() uint32 {
 	if x != nil {
-		return x.Category
+		return x.CategoryId
 	}
-	return ""
+	return 0
 }
 
-func (x *Product) GetImageUrl() string {
+func (x *ProductInput) GetImageUrl() string {
 	if x != nil {
 		return x.ImageUrl
 	}
 	return ""
 }
 
-func (x *Product) GetCreatedAt() string {
+func (x *ProductInput) GetExternalId() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.ExternalId
 	}
 	return ""
 }
 
-func (x *Product) GetUpdatedAt() string {
+func (x *ProductInput) GetTaxClass() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.TaxClass
 	}
 	return ""
 }
 
-var File_api_proto_product_proto protoreflect.FileDescriptor
+func (x *ProductInput) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
 
-const file_api_proto_product_proto_rawDesc = "" +
-	"\n" +
-	"\x17api/proto/product.proto\x12\aproduct\"#\n" +
-	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"@\n" +
-	"\x12GetProductResponse\x12*\n" +
-	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\"&\n" +
-	"\x12GetProductsRequest\x12\x10\n" +
-	"\x03ids\x18\x01 \x03(\rR\x03ids\"C\n" +
-	"\x13GetProductsResponse\x12,\n" +
-	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\"\xf2\x01\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
-	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1a\n" +
-	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1b\n" +
-	"\timage_url\x18\a \x01(\tR\bimageUrl\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\t \x01(\tR\tupdatedAt2\xa1\x01\n" +
+func (x *ProductInput) GetLengthCm() float64 {
+	if x != nil {
+		return x.LengthCm
+	}
+	return 0
+}
+
+func (x *ProductInput) GetWidthCm() float64 {
+	if x != nil {
+		return x.WidthCm
+	}
+	return 0
+}
+
+func (x *ProductInput) GetHeightCm() float64 {
+	if x != nil {
+		return x.HeightCm
+	}
+	return 0
+}
+
+func (x *ProductInput) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *ProductInput          `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_api_proto_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateProductRequest) GetProduct() *ProductInput {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type CreateProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductResponse) Reset() {
+	*x = CreateProductResponse{}
+	mi := &file_api_proto_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductResponse) ProtoMessage() {}
+
+func (x *CreateProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductResponse.ProtoReflect.Descriptor instead.
+func (*CreateProductResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+// expected_version optimistic concurrency içindir; farklıysa Aborted döner
+type UpdateProductRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion uint32                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Product         *ProductInput          `protobuf:"bytes,3,opt,name=product,proto3" json:"product,omitempty"`
+	// Yalnızca listelenen ProductInput alanları (ör. "price", "external_id")
+	// yazılır; listelenen boş external_id kaydı siler. Maske yoksa tüm alanlar
+	// yazılır, boş external_id ve currency kayıtlı değeri korur.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_api_proto_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateProductRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetExpectedVersion() uint32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetProduct() *ProductInput {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdateProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductResponse) Reset() {
+	*x = UpdateProductResponse{}
+	mi := &file_api_proto_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductResponse) ProtoMessage() {}
+
+func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProductResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type Product struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock       int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	// Kategori adı; kategori referansı için category_id kullanılmalı
+	Category   string            `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	ImageUrl   string            `protobuf:"bytes,7,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	CreatedAt  string            `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  string            `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CategoryId uint32            `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Options    []*ProductOption  `protobuf:"bytes,11,rep,name=options,proto3" json:"options,omitempty"`
+	Variants   []*ProductVariant `protobuf:"bytes,12,rep,name=variants,proto3" json:"variants,omitempty"`
+	// stock, aktif depolardaki stock_levels toplamıdır (satılabilir stok)
+	StockLevels      []*StockLevel `protobuf:"bytes,13,rep,name=stock_levels,json=stockLevels,proto3" json:"stock_levels,omitempty"`
+	Version          uint32        `protobuf:"varint,14,opt,name=version,proto3" json:"version,omitempty"`
+	ExternalId       string        `protobuf:"bytes,15,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	ReorderThreshold int32         `protobuf:"varint,16,opt,name=reorder_threshold,json=reorderThreshold,proto3" json:"reorder_threshold,omitempty"`
+	TaxClass         string        `protobuf:"bytes,17,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	// Kargo hesabı için; 0 bilinmiyor demektir
+	WeightKg float64 `protobuf:"fixed64,18,opt,name=weight_kg,json=weightKg,proto3" json:"weight_kg,omitempty"`
+	LengthCm float64 `protobuf:"fixed64,19,opt,name=length_cm,json=lengthCm,proto3" json:"length_cm,omitempty"`
+	WidthCm  float64 `protobuf:"fixed64,20,opt,name=width_cm,json=widthCm,proto3" json:"width_cm,omitempty"`
+	HeightCm float64 `protobuf:"fixed64,21,opt,name=height_cm,json=heightCm,proto3" json:"height_cm,omitempty"`
+	// price ve varyant fiyatlarının para birimi
+	Currency string `protobuf:"bytes,22,opt,name=currency,proto3" json:"currency,omitempty"`
+	// name, description ve category'nin dili (fallback sonrası)
+	Locale        string `protobuf:"bytes,23,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_api_proto_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Product) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Product) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *Product) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Product) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Product) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Product) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Product) GetCategoryId() uint32 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *Product) GetOptions() []*ProductOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Product) GetVariants() []*ProductVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *Product) GetStockLevels() []*StockLevel {
+	if x != nil {
+		return x.StockLevels
+	}
+	return nil
+}
+
+func (x *Product) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Product) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Product) GetReorderThreshold() int32 {
+	if x != nil {
+		return x.ReorderThreshold
+	}
+	return 0
+}
+
+func (x *Product) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *Product) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
+
+func (x *Product) GetLengthCm() float64 {
+	if x != nil {
+		return x.LengthCm
+	}
+	return 0
+}
+
+func (x *Product) GetWidthCm() float64 {
+	if x != nil {
+		return x.WidthCm
+	}
+	return 0
+}
+
+func (x *Product) GetHeightCm() float64 {
+	if x != nil {
+		return x.HeightCm
+	}
+	return 0
+}
+
+func (x *Product) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Product) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type StockLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WarehouseId   uint32                 `protobuf:"varint,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	WarehouseCode string                 `protobuf:"bytes,2,opt,name=warehouse_code,json=warehouseCode,proto3" json:"warehouse_code,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Active        bool                   `protobuf:"varint,4,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockLevel) Reset() {
+	*x = StockLevel{}
+	mi := &file_api_proto_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevel) ProtoMessage() {}
+
+func (x *StockLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevel.ProtoReflect.Descriptor instead.
+func (*StockLevel) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StockLevel) GetWarehouseId() uint32 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *StockLevel) GetWarehouseCode() string {
+	if x != nil {
+		return x.WarehouseCode
+	}
+	return ""
+}
+
+func (x *StockLevel) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockLevel) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type ProductOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductOption) Reset() {
+	*x = ProductOption{}
+	mi := &file_api_proto_product_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductOption) ProtoMessage() {}
+
+func (x *ProductOption) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductOption.ProtoReflect.Descriptor instead.
+func (*ProductOption) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProductOption) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductOption) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Kendi fiyatı, stoğu ve görseli olan satılabilir SKU
+type ProductVariant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,6,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariant) Reset() {
+	*x = ProductVariant{}
+	mi := &file_api_proto_product_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariant) ProtoMessage() {}
+
+func (x *ProductVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariant.ProtoReflect.Descriptor instead.
+func (*ProductVariant) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProductVariant) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ProductVariant) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+var File_api_proto_product_proto protoreflect.FileDescriptor
+
+const file_api_proto_product_proto_rawDesc = "" +
+	"\n" +
+	"\x17api/proto/product.proto\x12\aproduct\x1a google/protobuf/field_mask.proto\"W\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\"@\n" +
+	"\x12GetProductResponse\x12*\n" +
+	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\"Z\n" +
+	"\x12GetProductsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\rR\x03ids\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06locale\x18\x03 \x01(\tR\x06locale\"\x85\x01\n" +
+	"\x13GetProductsResponse\x12,\n" +
+	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\x12\x1f\n" +
+	"\vdeleted_ids\x18\x02 \x03(\rR\n" +
+	"deletedIds\x12\x1f\n" +
+	"\vmissing_ids\x18\x03 \x03(\rR\n" +
+	"missingIds\"(\n" +
+	"\x14WatchProductsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\rR\x03ids\"b\n" +
+	"\fProductEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\rR\tproductId\x12\x1f\n" +
+	"\voccurred_at\x18\x03 \x01(\tR\n" +
+	"occurredAt\"\x91\x03\n" +
+	"\fProductInput\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12+\n" +
+	"\x11reorder_threshold\x18\x04 \x01(\x05R\x10reorderThreshold\x12\x1f\n" +
+	"\vcategory_id\x18\x05 \x01(\rR\n" +
+	"categoryId\x12\x1b\n" +
+	"\timage_url\x18\x06 \x01(\tR\bimageUrl\x12\x1f\n" +
+	"\vexternal_id\x18\a \x01(\tR\n" +
+	"externalId\x12\x1b\n" +
+	"\ttax_class\x18\b \x01(\tR\btaxClass\x12\x1b\n" +
+	"\tweight_kg\x18\t \x01(\x01R\bweightKg\x12\x1b\n" +
+	"\tlength_cm\x18\n" +
+	" \x01(\x01R\blengthCm\x12\x19\n" +
+	"\bwidth_cm\x18\v \x01(\x01R\awidthCm\x12\x1b\n" +
+	"\theight_cm\x18\f \x01(\x01R\bheightCm\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\"G\n" +
+	"\x14CreateProductRequest\x12/\n" +
+	"\aproduct\x18\x01 \x01(\v2\x15.product.ProductInputR\aproduct\"C\n" +
+	"\x15CreateProductResponse\x12*\n" +
+	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\"\xbf\x01\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\rR\x0fexpectedVersion\x12/\n" +
+	"\aproduct\x18\x03 \x01(\v2\x15.product.ProductInputR\aproduct\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"C\n" +
+	"\x15UpdateProductResponse\x12*\n" +
+	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\"\xdd\x05\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1b\n" +
+	"\timage_url\x18\a \x01(\tR\bimageUrl\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\rR\n" +
+	"categoryId\x120\n" +
+	"\aoptions\x18\v \x03(\v2\x16.product.ProductOptionR\aoptions\x123\n" +
+	"\bvariants\x18\f \x03(\v2\x17.product.ProductVariantR\bvariants\x126\n" +
+	"\fstock_levels\x18\r \x03(\v2\x13.product.StockLevelR\vstockLevels\x12\x18\n" +
+	"\aversion\x18\x0e \x01(\rR\aversion\x12\x1f\n" +
+	"\vexternal_id\x18\x0f \x01(\tR\n" +
+	"externalId\x12+\n" +
+	"\x11reorder_threshold\x18\x10 \x01(\x05R\x10reorderThreshold\x12\x1b\n" +
+	"\ttax_class\x18\x11 \x01(\tR\btaxClass\x12\x1b\n" +
+	"\tweight_kg\x18\x12 \x01(\x01R\bweightKg\x12\x1b\n" +
+	"\tlength_cm\x18\x13 \x01(\x01R\blengthCm\x12\x19\n" +
+	"\bwidth_cm\x18\x14 \x01(\x01R\awidthCm\x12\x1b\n" +
+	"\theight_cm\x18\x15 \x01(\x01R\bheightCm\x12\x1a\n" +
+	"\bcurrency\x18\x16 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06locale\x18\x17 \x01(\tR\x06locale\"\x8a\x01\n" +
+	"\n" +
+	"StockLevel\x12!\n" +
+	"\fwarehouse_id\x18\x01 \x01(\rR\vwarehouseId\x12%\n" +
+	"\x0ewarehouse_code\x18\x02 \x01(\tR\rwarehouseCode\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x16\n" +
+	"\x06active\x18\x04 \x01(\bR\x06active\";\n" +
+	"\rProductOption\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"\x83\x02\n" +
+	"\x0eProductVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12G\n" +
+	"\n" +
+	"attributes\x18\x03 \x03(\v2'.product.ProductVariant.AttributesEntryR\n" +
+	"attributes\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1b\n" +
+	"\timage_url\x18\x06 \x01(\tR\bimageUrl\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x8a\x03\n" +
 	"\x0eProductService\x12E\n" +
 	"\n" +
 	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x1b.product.GetProductResponse\x12H\n" +
-	"\vGetProducts\x12\x1b.product.GetProductsRequest\x1a\x1c.product.GetProductsResponseB'Z%cluster-iac/api/proto/product;productb\x06proto3"
+	"\vGetProducts\x12\x1b.product.GetProductsRequest\x1a\x1c.product.GetProductsResponse\x12G\n" +
+	"\rWatchProducts\x12\x1d.product.WatchProductsRequest\x1a\x15.product.ProductEvent0\x01\x12N\n" +
+	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x1e.product.CreateProductResponse\x12N\n" +
+	"\rUpdateProduct\x12\x1d.product.UpdateProductRequest\x1a\x1e.product.UpdateProductResponseB'Z%cluster-iac/api/proto/product;productb\x06proto3"
 
 var (
 	file_api_proto_product_proto_rawDescOnce sync.Once
@@ -347,26 +1260,53 @@ func file_api_proto_product_proto_rawDescGZIP() []byte {
 	return file_api_proto_product_proto_rawDescData
 }
 
-var file_api_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_api_proto_product_proto_goTypes = []any{
-	(*GetProductRequest)(nil),   // 0: product.GetProductRequest
-	(*GetProductResponse)(nil),  // 1: product.GetProductResponse
-	(*GetProductsRequest)(nil),  // 2: product.GetProductsRequest
-	(*GetProductsResponse)(nil), // 3: product.GetProductsResponse
-	(*Product)(nil),             // 4: product.Product
+	(*GetProductRequest)(nil),     // 0: product.GetProductRequest
+	(*GetProductResponse)(nil),    // 1: product.GetProductResponse
+	(*GetProductsRequest)(nil),    // 2: product.GetProductsRequest
+	(*GetProductsResponse)(nil),   // 3: product.GetProductsResponse
+	(*WatchProductsRequest)(nil),  // 4: product.WatchProductsRequest
+	(*ProductEvent)(nil),          // 5: product.ProductEvent
+	(*ProductInput)(nil),          // 6: product.ProductInput
+	(*CreateProductRequest)(nil),  // 7: product.CreateProductRequest
+	(*CreateProductResponse)(nil), // 8: product.CreateProductResponse
+	(*UpdateProductRequest)(nil),  // 9: product.UpdateProductRequest
+	(*UpdateProductResponse)(nil), // 10: product.UpdateProductResponse
+	(*Product)(nil),               // 11: product.Product
+	(*StockLevel)(nil),            // 12: product.StockLevel
+	(*ProductOption)(nil),         // 13: product.ProductOption
+	(*ProductVariant)(nil),        // 14: product.ProductVariant
+	nil,                           // 15: product.ProductVariant.AttributesEntry
+	(*fieldmaskpb.FieldMask)(nil), // 16: google.protobuf.FieldMask
 }
 var file_api_proto_product_proto_depIdxs = []int32{
-	4, // 0: product.GetProductResponse.product:type_name -> product.Product
-	4, // 1: product.GetProductsResponse.products:type_name -> product.Product
-	0, // 2: product.ProductService.GetProduct:input_type -> product.GetProductRequest
-	2, // 3: product.ProductService.GetProducts:input_type -> product.GetProductsRequest
-	1, // 4: product.ProductService.GetProduct:output_type -> product.GetProductResponse
-	3, // 5: product.ProductService.GetProducts:output_type -> product.GetProductsResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	11, // 0: product.GetProductResponse.product:type_name -> product.Product
+	11, // 1: product.GetProductsResponse.products:type_name -> product.Product
+	6,  // 2: product.CreateProductRequest.product:type_name -> product.ProductInput
+	11, // 3: product.CreateProductResponse.product:type_name -> product.Product
+	6,  // 4: product.UpdateProductRequest.product:type_name -> product.ProductInput
+	16, // 5: product.UpdateProductRequest.update_mask:type_name -> google.protobuf.FieldMask
+	11, // 6: product.UpdateProductResponse.product:type_name -> product.Product
+	13, // 7: product.Product.options:type_name -> product.ProductOption
+	14, // 8: product.Product.variants:type_name -> product.ProductVariant
+	12, // 9: product.Product.stock_levels:type_name -> product.StockLevel
+	15, // 10: product.ProductVariant.attributes:type_name -> product.ProductVariant.AttributesEntry
+	0,  // 11: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	2,  // 12: product.ProductService.GetProducts:input_type -> product.GetProductsRequest
+	4,  // 13: product.ProductService.WatchProducts:input_type -> product.WatchProductsRequest
+	7,  // 14: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	9,  // 15: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
+	1,  // 16: product.ProductService.GetProduct:output_type -> product.GetProductResponse
+	3,  // 17: product.ProductService.GetProducts:output_type -> product.GetProductsResponse
+	5,  // 18: product.ProductService.WatchProducts:output_type -> product.ProductEvent
+	8,  // 19: product.ProductService.CreateProduct:output_type -> product.CreateProductResponse
+	10, // 20: product.ProductService.UpdateProduct:output_type -> product.UpdateProductResponse
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_product_proto_init() }
@@ -380,7 +1320,7 @@ func file_api_proto_product_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_product_proto_rawDesc), len(file_api_proto_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,9 @@
+package product
+
+// gRPC hata detaylarında (errdetails.ErrorInfo) kullanılan domain ve reason değerleri
+const (
+	ErrorDomain = "product.cluster-iac"
+
+	ReasonProductNotFound = "PRODUCT_NOT_FOUND"
+	ReasonProductDeleted  = "PRODUCT_DELETED"
+)
@@ -3,14 +3,28 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
 	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/basket/archive"
+	"cluster-iac/internal/basket/cache"
 	"cluster-iac/internal/basket/config"
+	"cluster-iac/internal/basket/events"
 	"cluster-iac/internal/basket/handler"
+	"cluster-iac/internal/basket/jobs"
 	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/retention"
 	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/basket/shipping"
+	"cluster-iac/internal/basket/tax"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/idempotency"
+	"cluster-iac/internal/logging"
+	"cluster-iac/internal/tenant"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
@@ -22,9 +36,18 @@ func main() {
 	// Config yükle
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		slog.Error("Failed to load config", "error", err)
+		os.Exit(1)
 	}
 
+	// JSON loglar; seviye çalışırken /admin/log-level ile değiştirilebilir
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		slog.Error("Invalid LOG_LEVEL", "error", err)
+		os.Exit(1)
+	}
+	levelVar := logging.Setup("basket-service", level)
+
 	// Redis bağlantısı
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.RedisAddr,
@@ -35,33 +58,123 @@ func main() {
 	// Redis bağlantısını test et
 	ctx := context.Background()
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		slog.Error("Failed to connect to Redis", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Redis connected successfully")
+
+	// Sepet ve liste anahtarları tenant başına ayrılır
+	tenants, err := tenant.ParseSet(cfg.Tenants)
+	if err != nil {
+		slog.Error("Invalid TENANTS", "error", err)
+		os.Exit(1)
+	}
+	moved, err := repository.MigrateTenantKeys(ctx, redisClient)
+	if err != nil {
+		slog.Error("Failed to migrate basket keys to tenant keys", "error", err)
+		os.Exit(1)
+	}
+	if moved > 0 {
+		slog.Info("Moved basket keys to tenant keys", "keys", moved, "tenant", tenant.Default)
 	}
-	log.Println("Redis connected successfully")
 
-	// gRPC product client bağlantısı
-	productConn, err := grpc.Dial(cfg.ProductGRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// gRPC product client bağlantısı; isteğin tenant'ı ve istek kimliği metadata ile iletilir
+	productConn, err := grpc.Dial(cfg.ProductGRPC,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tenant.UnaryClientInterceptor(), logging.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tenant.StreamClientInterceptor(), logging.StreamClientInterceptor()),
+	)
 	if err != nil {
-		log.Fatalf("Failed to connect to product service: %v", err)
+		slog.Error("Failed to connect to product service", "error", err)
+		os.Exit(1)
 	}
 	defer productConn.Close()
 
 	productClient := product.NewProductServiceClient(productConn)
-	log.Println("Product service gRPC client connected successfully")
+	slog.Info("Product service gRPC client connected successfully")
+
+	// Ürün snapshot cache'i; her tenant'ın WatchProducts stream'i ile invalidate edilir
+	productCache := cache.NewProductCache(cfg.ProductCacheSize, cfg.ProductCacheTTL)
+	for _, id := range tenants.IDs() {
+		go cache.WatchProductChanges(tenant.NewContext(ctx, id), productClient, productCache)
+	}
 
 	// Repository, service ve handler oluştur
-	basketRepo := repository.NewBasketRepository(redisClient)
-	basketService := service.NewBasketService(basketRepo, productClient)
-	basketHandler := handler.NewBasketHandler(basketService)
+	defaultClass, err := retention.ParseClass(cfg.DefaultUserClass)
+	if err != nil {
+		slog.Error("Invalid BASKET_DEFAULT_USER_CLASS", "value", cfg.DefaultUserClass, "error", err)
+		os.Exit(1)
+	}
+	retentionPolicy := retention.Policy{
+		Guest:        cfg.RetentionGuest,
+		Registered:   cfg.RetentionRegistered,
+		B2B:          cfg.RetentionB2B,
+		DefaultClass: defaultClass,
+	}
+	basketRepo := repository.NewBasketRepository(redisClient, retentionPolicy, clock.New())
+	// Vergi oranları; harici bir sağlayıcı TaxCalculator arayüzüyle takılabilir
+	taxRates, err := tax.LoadRateTable(cfg.TaxRatesFile)
+	if err != nil {
+		slog.Error("Failed to load tax rates", "error", err)
+		os.Exit(1)
+	}
+	shippingCatalog, err := shipping.LoadCatalog(cfg.ShippingMethodsFile)
+	if err != nil {
+		slog.Error("Failed to load shipping methods", "error", err)
+		os.Exit(1)
+	}
+	// Kargo ücretleri ve eşikleri sepetin para birimine bu kurlarla çevrilir
+	currencies, err := currency.NewConverter(ctx, currency.NewSource(cfg.ExchangeRatesSource))
+	if err != nil {
+		slog.Error("Failed to load exchange rates", "error", err)
+		os.Exit(1)
+	}
+	go currencies.RunRefresher(ctx, cfg.ExchangeRatesRefreshInterval)
+	defaultCurrency := currency.Normalize(cfg.DefaultCurrency)
+	if !currencies.Supports(defaultCurrency) {
+		slog.Error("BASKET_DEFAULT_CURRENCY has no exchange rate", "currency", cfg.DefaultCurrency)
+		os.Exit(1)
+	}
+	if !currencies.Supports(shippingCatalog.Currency()) {
+		slog.Error("Shipping method currency has no exchange rate", "currency", shippingCatalog.Currency())
+		os.Exit(1)
+	}
+
+	basketService := service.NewBasketService(basketRepo, productClient, productCache, tax.NewTableCalculator(taxRates), shippingCatalog, currencies, defaultCurrency)
+	basketHandler := handler.NewBasketHandler(basketService, currencies)
+	wishlistRepo := repository.NewWishlistRepository(redisClient)
+	wishlistService := service.NewWishlistService(wishlistRepo, basketRepo, basketService, productClient, productCache, defaultCurrency)
+	wishlistHandler := handler.NewWishlistHandler(wishlistService)
+
+	// Terk edilmiş sepetler için event üret, süresi dolmadan arşive taşı
+	if cfg.ArchiveLead <= cfg.SweepInterval {
+		slog.Warn("BASKET_ARCHIVE_LEAD should exceed BASKET_SWEEP_INTERVAL; baskets may expire before they are archived",
+			"archive_lead", cfg.ArchiveLead.String(), "sweep_interval", cfg.SweepInterval.String())
+	}
+	archiveStore, err := archive.NewFileStore(cfg.ArchiveDir)
+	if err != nil {
+		slog.Error("Failed to initialize basket archive", "error", err)
+		os.Exit(1)
+	}
+	publishers := events.MultiPublisher{
+		events.NewLogPublisher(),
+		events.NewStreamPublisher(redisClient, cfg.EventsStream, 100000),
+	}
+	if cfg.EventsWebhookURL != "" {
+		publishers = append(publishers, events.NewWebhookPublisher(cfg.EventsWebhookURL, 5*time.Second))
+	}
+	abandonedService := service.NewAbandonedBasketService(basketRepo, archiveStore, publishers, clock.New(), cfg.AbandonAfter, cfg.ArchiveLead)
+	go jobs.RunAbandonedBasketSweeper(ctx, basketRepo, abandonedService, tenants.IDs(), cfg.SweepInterval)
 
-	// Gin router oluştur
-	r := gin.Default()
+	// Gin router oluştur; erişim logları ve panic kurtarma logging.Middleware'dedir
+	r := gin.New()
 
 	// CORS middleware ekle
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Idempotency-Key, X-Request-ID, Accept-Currency, Accept-Language, X-Tenant-ID")
+		c.Header("Access-Control-Expose-Headers", "Idempotent-Replayed")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusOK)
@@ -71,16 +184,45 @@ func main() {
 		c.Next()
 	})
 
+	// Tekrarlanan yazma isteklerinde (ör. AddItem) miktarın iki kez artmaması için
+	r.Use(idempotency.Middleware(redisClient, idempotency.Options{
+		Prefix:         "idempotency:basket:",
+		TTL:            cfg.IdempotencyTTL,
+		LockTTL:        cfg.IdempotencyLockTTL,
+		MaxMemoryBytes: 1 << 20,
+		// Basket servisi yalnızca küçük JSON gövdeleri alır
+		MaxBodyBytes: 1 << 20,
+	}))
+
 	// Basket routes
-	baskets := r.Group("/baskets")
+	baskets := r.Group("/baskets", handler.UserClassMiddleware())
 	{
 		baskets.GET("/:user_id", basketHandler.GetBasket)
+		baskets.GET("/:user_id/expiry", basketHandler.GetExpiry)
+		baskets.GET("/:user_id/shipping-options", basketHandler.GetShippingOptions)
+		baskets.PUT("/:user_id/shipping", basketHandler.SelectShipping)
+		baskets.DELETE("/:user_id/shipping", basketHandler.ClearShipping)
 		baskets.POST("/:user_id/items", basketHandler.AddItem)
 		baskets.DELETE("/:user_id/items/:product_id", basketHandler.RemoveItem)
 		baskets.PUT("/:user_id/items/:product_id", basketHandler.UpdateItemQuantity)
 		baskets.DELETE("/:user_id", basketHandler.ClearBasket)
+		baskets.POST("/:user_id/items/:product_id/save", wishlistHandler.SaveForLater)
+
+		// Wishlist ve "sonra al" listeleri
+		baskets.GET("/:user_id/lists", wishlistHandler.GetLists)
+		baskets.POST("/:user_id/lists", wishlistHandler.CreateList)
+		baskets.GET("/:user_id/lists/:list_id", wishlistHandler.GetList)
+		baskets.DELETE("/:user_id/lists/:list_id", wishlistHandler.DeleteList)
+		baskets.POST("/:user_id/lists/:list_id/items", wishlistHandler.AddItem)
+		baskets.DELETE("/:user_id/lists/:list_id/items/:product_id", wishlistHandler.RemoveItem)
+		baskets.POST("/:user_id/lists/:list_id/items/:product_id/move", wishlistHandler.MoveToBasket)
+		baskets.POST("/:user_id/lists/:list_id/share", wishlistHandler.ShareList)
+		baskets.DELETE("/:user_id/lists/:list_id/share", wishlistHandler.UnshareList)
 	}
 
+	// Paylaşılan listeler salt okunurdur
+	r.GET("/shared-lists/:token", wishlistHandler.GetSharedList)
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "OK", "service": "basket-service"})
@@ -89,11 +231,17 @@ func main() {
 		c.Status(http.StatusOK)
 	})
 
-	// Server başlat
+	// Log seviyesi tenant'tan bağımsızdır
+	mux := http.NewServeMux()
+	mux.Handle("/admin/log-level", logging.LevelHandler(levelVar))
+	mux.Handle("/", tenants.Middleware(r))
+
+	// Server başlat; tenant X-Tenant-ID başlığından çözülüp context'e konur
 	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("Basket service starting on port %s", cfg.ServerPort)
+	slog.Info("Basket service starting", "port", cfg.ServerPort)
 	
-	if err := r.Run(serverAddr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := http.ListenAndServe(serverAddr, logging.Middleware(mux)); err != nil {
+		slog.Error("Failed to start server", "error", err)
+		os.Exit(1)
 	}
 }
@@ -2,71 +2,195 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/idempotency"
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/logging"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/alerts"
 	"cluster-iac/internal/product/config"
 	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/events"
 	"cluster-iac/internal/product/handler"
-	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/model"
 	"cluster-iac/internal/product/service"
+	"cluster-iac/internal/product/storage"
+	"cluster-iac/internal/tenant"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
 	// Config yükle
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		slog.Error("Failed to load config", "error", err)
+		os.Exit(1)
 	}
 
+	// JSON loglar; seviye çalışırken /admin/log-level ile değiştirilebilir
+	level, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		slog.Error("Invalid LOG_LEVEL", "error", err)
+		os.Exit(1)
+	}
+	levelVar := logging.Setup("product-service", level)
+
 	// Database bağlantısı
 	err = database.ConnectDB(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	// Idempotency-Key yanıtları Redis'te tutulur; REDIS_ADDR verilmezse özellik kapalıdır
+	var redisClient *redis.Client
+	if cfg.RedisAddr != "" {
+		redisClient = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword})
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			slog.Error("Failed to connect to Redis", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("REDIS_ADDR is not set, Idempotency-Key support is disabled")
+	}
+
+	// Ürün değişikliklerini WatchProducts aboneleri için yayınla
+	eventBus := events.NewBus()
+
+	// Fiyatları istenen para biriminde sunmak için kurlar
+	currencies, err := currency.NewConverter(context.Background(), currency.NewSource(cfg.ExchangeRatesSource))
+	if err != nil {
+		slog.Error("Failed to load exchange rates", "error", err)
+		os.Exit(1)
+	}
+	go currencies.RunRefresher(context.Background(), cfg.ExchangeRatesRefreshInterval)
+
+	// Ürün ve kategori metinleri için desteklenen diller ve fallback'ler
+	locales, err := locale.NewResolver(cfg.DefaultLocale, cfg.SupportedLocales, cfg.LocaleFallbacks)
+	if err != nil {
+		slog.Error("Invalid locale configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Görseller için blob store; anahtarlar ürün id'si içerdiği için tenant'lar paylaşır
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		slog.Error("Failed to initialize blob store", "error", err)
+		os.Exit(1)
 	}
 
-	// Repository, service ve handler oluştur
-	productRepo := repository.NewProductRepository(database.DB)
-	productService := service.NewProductService(productRepo)
-	productHandler := handler.NewProductHandler(productService)
+	notifiers := alerts.MultiNotifier{alerts.NewLogNotifier()}
+	if cfg.StockAlertWebhookURL != "" {
+		notifiers = append(notifiers, alerts.NewWebhookNotifier(cfg.StockAlertWebhookURL, 5*time.Second))
+	}
+
+	// Her tenant kendi service'lerini ve arka plan işlerini çalıştırır
+	tenants, err := tenant.ParseSet(cfg.Tenants)
+	if err != nil {
+		slog.Error("Invalid TENANTS", "error", err)
+		os.Exit(1)
+	}
+	deps := &shared{
+		cfg:         cfg,
+		redisClient: redisClient,
+		eventBus:    eventBus,
+		currencies:  currencies,
+		locales:     locales,
+		blobStore:   blobStore,
+		notifiers:   notifiers,
+	}
+	apps := make(map[string]*tenantApp)
+	for _, id := range tenants.IDs() {
+		apps[id] = startTenant(context.Background(), id, deps)
+	}
+	slog.Info("Serving tenants", "tenants", tenants.IDs())
 
 	// gRPC server başlat
-	go startGRPCServer(cfg, productService)
+	go startGRPCServer(tenants, apps, eventBus)
 
 	// HTTP server başlat
-	startHTTPServer(cfg, productHandler)
+	startHTTPServer(cfg, tenantRouter(tenants, apps), levelVar)
 }
 
-func startGRPCServer(cfg *config.Config, productService service.ProductService) {
+func startGRPCServer(tenants *tenant.Set, apps map[string]*tenantApp, eventBus *events.Bus) {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", "50051"))
 	if err != nil {
-		log.Fatalf("Failed to listen for gRPC: %v", err)
+		slog.Error("Failed to listen for gRPC", "error", err)
+		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer()
-	product.RegisterProductServiceServer(grpcServer, &grpcProductServer{productService: productService})
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor()),
+	)
+	product.RegisterProductServiceServer(grpcServer, &grpcProductServer{tenants: tenants, apps: apps, eventBus: eventBus})
 
-	log.Printf("gRPC server starting on port 50051")
+	slog.Info("gRPC server starting", "port", "50051")
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve gRPC: %v", err)
+		slog.Error("Failed to serve gRPC", "error", err)
+		os.Exit(1)
 	}
 }
 
-func startHTTPServer(cfg *config.Config, productHandler *handler.ProductHandler) {
-	// Gin router oluştur
-	r := gin.Default()
+// startHTTPServer tenant route'larını ve tenant'tan bağımsız admin uç
+// noktalarını aynı portta sunar
+func startHTTPServer(cfg *config.Config, h http.Handler, levelVar *slog.LevelVar) {
+	mux := http.NewServeMux()
+	mux.Handle("/admin/log-level", logging.LevelHandler(levelVar))
+	mux.Handle("/", h)
+
+	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
+	slog.Info("HTTP server starting", "port", cfg.ServerPort)
+
+	if err := http.ListenAndServe(serverAddr, logging.Middleware(mux)); err != nil {
+		slog.Error("Failed to start HTTP server", "error", err)
+		os.Exit(1)
+	}
+}
+
+// routeHandlers bir tenant'ın HTTP handler'larıdır
+type routeHandlers struct {
+	product     *handler.ProductHandler
+	category    *handler.CategoryHandler
+	variant     *handler.VariantHandler
+	inventory   *handler.InventoryHandler
+	price       *handler.PriceHandler
+	importer    *handler.ImportHandler
+	image       *handler.ImageHandler
+	pricing     *handler.PricingHandler
+	translation *handler.TranslationHandler
+	audit       *handler.AuditHandler
+}
+
+// newRouter bir tenant'ın route'larını kurar; tenant'lar aynı route'ları
+// kendi handler'larıyla sunar
+func newRouter(cfg *config.Config, redisClient *redis.Client, h routeHandlers) *gin.Engine {
+	// Gin router oluştur; erişim logları ve panic kurtarma logging.Middleware'dedir
+	r := gin.New()
 
 	// CORS middleware ekle
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, If-Match, X-Actor, X-Request-ID, Idempotency-Key, Accept-Currency, Accept-Language, X-Tenant-ID")
+		c.Header("Access-Control-Expose-Headers", "ETag, Location, Idempotent-Replayed, Content-Language")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusOK)
@@ -76,15 +200,117 @@ func startHTTPServer(cfg *config.Config, productHandler *handler.ProductHandler)
 		c.Next()
 	})
 
+	if redisClient != nil {
+		r.Use(idempotency.Middleware(redisClient, idempotency.Options{
+			Prefix:         "idempotency:product:",
+			TTL:            cfg.IdempotencyTTL,
+			LockTTL:        cfg.IdempotencyLockTTL,
+			MaxMemoryBytes: 1 << 20,
+			// En büyük gövdeyi kabul eden route'ların sınırı
+			MaxBodyBytes: max(h.importer.MaxRequestBytes(), h.image.MaxRequestBytes()),
+		}))
+	}
+
 	// Product routes
 	products := r.Group("/products")
 	{
-		products.POST("/", productHandler.CreateProduct)
-		products.GET("/", productHandler.GetAllProducts)
-		products.GET("/category", productHandler.GetProductsByCategory)
-		products.GET("/:id", productHandler.GetProductByID)
-		products.PUT("/:id", productHandler.UpdateProduct)
-		products.DELETE("/:id", productHandler.DeleteProduct)
+		products.POST("/", h.product.CreateProduct)
+		products.GET("/", h.product.GetAllProducts)
+		products.GET("/category", h.product.GetProductsByCategory)
+		products.GET("/trash", h.product.GetTrash)
+		products.POST("/import", h.importer.ImportProducts)
+		products.GET("/export", h.product.ExportProducts)
+		products.GET("/:id", h.product.GetProductByID)
+		products.PUT("/:id", h.product.UpdateProduct)
+		products.PATCH("/:id", h.product.PatchProduct)
+		products.DELETE("/:id", h.product.DeleteProduct)
+		products.POST("/:id/restore", h.product.RestoreProduct)
+		products.GET("/:id/options", h.variant.GetOptions)
+		products.PUT("/:id/options", h.variant.ReplaceOptions)
+		products.GET("/:id/variants", h.variant.GetVariants)
+		products.POST("/:id/variants", h.variant.CreateVariant)
+		products.PUT("/:id/variants/:variant_id", h.variant.UpdateVariant)
+		products.DELETE("/:id/variants/:variant_id", h.variant.DeleteVariant)
+		products.GET("/:id/inventory", h.inventory.GetProductInventory)
+		products.GET("/:id/images", h.image.GetImages)
+		products.POST("/:id/images", h.image.UploadImages)
+		products.PUT("/:id/images/order", h.image.ReorderImages)
+		products.DELETE("/:id/images/:image_id", h.image.DeleteImage)
+		products.GET("/:id/prices", h.price.GetPrices)
+		products.POST("/:id/prices/schedules", h.price.CreateSchedule)
+		products.DELETE("/:id/prices/schedules/:schedule_id", h.price.CancelSchedule)
+		products.GET("/:id/price-lists", h.pricing.GetProductPriceLists)
+		products.GET("/:id/translations", h.translation.GetProductTranslations)
+		products.PUT("/:id/translations/:locale", h.translation.SetProductTranslation)
+		products.DELETE("/:id/translations/:locale", h.translation.DeleteProductTranslation)
+	}
+
+	// Çeviri tamamlanma raporu ve eksik çeviriler
+	translations := r.Group("/translations")
+	{
+		translations.GET("/completeness", h.translation.GetCompleteness)
+		translations.GET("/:locale/missing", h.translation.GetMissing)
+	}
+
+	// Para birimleri ve para birimi başına fiyat listeleri
+	r.GET("/currencies", h.pricing.GetCurrencies)
+	priceLists := r.Group("/price-lists")
+	{
+		priceLists.GET("/:currency", h.pricing.GetPriceList)
+		priceLists.PUT("/:currency/products/:id", h.pricing.SetListPrice)
+		priceLists.DELETE("/:currency/products/:id", h.pricing.DeleteListPrice)
+	}
+
+	// Warehouse ve envanter routes
+	warehouses := r.Group("/warehouses")
+	{
+		warehouses.POST("/", h.inventory.CreateWarehouse)
+		warehouses.GET("/", h.inventory.GetWarehouses)
+		warehouses.GET("/:id", h.inventory.GetWarehouseByID)
+		warehouses.PUT("/:id", h.inventory.UpdateWarehouse)
+	}
+
+	inventory := r.Group("/inventory")
+	{
+		inventory.POST("/movements", h.inventory.RecordMovement)
+		inventory.GET("/movements", h.inventory.GetMovements)
+	}
+
+	// Yerel blob store kullanılıyorsa yüklenen görselleri sun
+	if cfg.BlobStore == "local" {
+		r.Static("/media", cfg.MediaDir)
+	}
+
+	// Toplu içe aktarma işleri
+	imports := r.Group("/imports")
+	{
+		imports.GET("/:id", h.importer.GetImport)
+		imports.GET("/:id/rows", h.importer.GetImportRows)
+		imports.GET("/:id/errors", h.importer.GetImportErrors)
+	}
+
+	// Ürün değişikliklerinin denetim kayıtları
+	r.GET("/audit", h.audit.ListEntries)
+
+	// Category routes
+	categories := r.Group("/categories")
+	{
+		categories.POST("/", h.category.CreateCategory)
+		categories.GET("/", h.category.GetAllCategories)
+		categories.GET("/tree", h.category.GetCategoryTree)
+		categories.GET("/:id", h.category.GetCategoryByID)
+		categories.PUT("/:id", h.category.UpdateCategory)
+		categories.DELETE("/:id", h.category.DeleteCategory)
+		categories.GET("/:id/translations", h.translation.GetCategoryTranslations)
+		categories.PUT("/:id/translations/:locale", h.translation.SetCategoryTranslation)
+		categories.DELETE("/:id/translations/:locale", h.translation.DeleteCategoryTranslation)
+	}
+
+	// Admin routes; gateway üzerinden dışarı açılmaz
+	admin := r.Group("/admin")
+	{
+		admin.DELETE("/products/trash/:id", h.product.PurgeProduct)
+		admin.POST("/products/trash/purge", h.product.PurgeTrash)
 	}
 
 	// Health check endpoint
@@ -95,63 +321,403 @@ func startHTTPServer(cfg *config.Config, productHandler *handler.ProductHandler)
 		c.Status(http.StatusOK)
 	})
 
-	// Server başlat
-	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("HTTP server starting on port %s", cfg.ServerPort)
-	
-	if err := r.Run(serverAddr); err != nil {
-		log.Fatalf("Failed to start HTTP server: %v", err)
-	}
+	return r
 }
 
 // gRPC server implementasyonu
 type grpcProductServer struct {
 	product.UnimplementedProductServiceServer
-	productService service.ProductService
+	tenants  *tenant.Set
+	apps     map[string]*tenantApp
+	eventBus *events.Bus
+}
+
+// app çağrının metadata'sındaki tenant'ın service'lerini döndürür; tenant
+// gönderilmeyen çağrılar Default tenant'a gider
+func (s *grpcProductServer) app(ctx context.Context) (*tenantApp, error) {
+	id, err := s.tenants.Resolve(tenant.FromIncomingContext(ctx))
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+	return s.apps[id], nil
+}
+
+// grpcActor yazma çağrılarının denetim kaydı aktörüdür; kimlik x-actor,
+// istek kimliği logging interceptor'ının context'e koyduğu değerdir. gRPC
+// portu gateway'den yayınlanmaz; x-actor'u küme içindeki çağıran servis verir.
+func grpcActor(ctx context.Context) model.Actor {
+	actor := model.Actor{Source: model.AuditSourceGRPC}
+	if values := metadata.ValueFromIncomingContext(ctx, "x-actor"); len(values) > 0 {
+		actor.Name = strings.TrimSpace(values[0])
+	}
+	actor.RequestID = logging.RequestID(ctx)
+	return actor
 }
 
 func (s *grpcProductServer) GetProduct(ctx context.Context, req *product.GetProductRequest) (*product.GetProductResponse, error) {
-	prod, err := s.productService.GetProductByID(uint(req.Id))
+	app, err := s.app(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &product.GetProductResponse{
-		Product: &product.Product{
-			Id:          uint32(prod.ID),
-			Name:        prod.Name,
-			Description: prod.Description,
-			Price:       prod.Price,
-			Stock:       int32(prod.Stock),
-			Category:    prod.Category,
-			ImageUrl:    prod.ImageURL,
-			CreatedAt:   prod.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   prod.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
-	}, nil
+	prod, err := app.productService.GetProductWithDeleted(uint(req.Id))
+	if errors.Is(err, service.ErrProductNotFound) {
+		return nil, productNotFoundError(req.Id, product.ReasonProductNotFound, nil)
+	}
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+
+	// Silinmiş ürünler de NotFound döner; reason ile "hiç yok"tan ayrılır
+	if prod.DeletedAt.Valid {
+		return nil, productNotFoundError(req.Id, product.ReasonProductDeleted, map[string]string{
+			"deleted_at": prod.DeletedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	code, err := app.pricingService.Negotiate(req.Currency, "")
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+	if err := app.pricingService.Present(prod, code); err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+	// locale Accept-Language gibi yorumlanır; desteklenmeyen diller atlanır
+	chain, err := app.translationService.Resolve("", req.Locale)
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+	if err := app.translationService.Localize(prod, chain); err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+
+	return &product.GetProductResponse{Product: toProtoProduct(prod)}, nil
 }
 
 func (s *grpcProductServer) GetProducts(ctx context.Context, req *product.GetProductsRequest) (*product.GetProductsResponse, error) {
-	var products []*product.Product
-	
+	app, err := s.app(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &product.GetProductsResponse{}
+
+	code, err := app.pricingService.Negotiate(req.Currency, "")
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+	chain, err := app.translationService.Resolve("", req.Locale)
+	if err != nil {
+		return nil, problem.ToGRPC(err, "")
+	}
+
 	for _, id := range req.Ids {
-		prod, err := s.productService.GetProductByID(uint(id))
+		prod, err := app.productService.GetProductWithDeleted(uint(id))
+		if errors.Is(err, service.ErrProductNotFound) {
+			resp.MissingIds = append(resp.MissingIds, id)
+			continue
+		}
 		if err != nil {
-			continue // Hata durumunda bu ürünü atla
+			// Yalnızca bulunamayan ürünler atlanır; diğer hatalarda ürünler
+			// sessizce kaybolmasın diye çağrı başarısız olur
+			return nil, problem.ToGRPC(err, "")
 		}
-		
-		products = append(products, &product.Product{
-			Id:          uint32(prod.ID),
-			Name:        prod.Name,
-			Description: prod.Description,
-			Price:       prod.Price,
-			Stock:       int32(prod.Stock),
-			Category:    prod.Category,
-			ImageUrl:    prod.ImageURL,
-			CreatedAt:   prod.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   prod.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		if prod.DeletedAt.Valid {
+			resp.DeletedIds = append(resp.DeletedIds, id)
+			continue
+		}
+
+		if err := app.pricingService.Present(prod, code); err != nil {
+			return nil, problem.ToGRPC(err, "")
+		}
+		if err := app.translationService.Localize(prod, chain); err != nil {
+			return nil, problem.ToGRPC(err, "")
+		}
+		resp.Products = append(resp.Products, toProtoProduct(prod))
+	}
+
+	return resp, nil
+}
+
+// WatchProducts yalnızca çağıran tenant'ın event'lerini gönderir
+func (s *grpcProductServer) WatchProducts(req *product.WatchProductsRequest, stream grpc.ServerStreamingServer[product.ProductEvent]) error {
+	app, err := s.app(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[uint]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		watched[uint(id)] = true
+	}
+
+	eventCh, unsubscribe := s.eventBus.Subscribe(64)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case evt, ok := <-eventCh:
+			if !ok {
+				return nil
+			}
+			if evt.Tenant != app.id || (len(watched) > 0 && !watched[evt.ProductID]) {
+				continue
+			}
+
+			err := stream.Send(&product.ProductEvent{
+				Type:       string(evt.Type),
+				ProductId:  uint32(evt.ProductID),
+				OccurredAt: evt.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *grpcProductServer) CreateProduct(ctx context.Context, req *product.CreateProductRequest) (*product.CreateProductResponse, error) {
+	app, err := s.app(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prod := fromProtoInput(req.Product)
+	if err := app.productService.CreateProduct(prod, grpcActor(ctx)); err != nil {
+		return nil, productWriteError(0, err)
+	}
+
+	return &product.CreateProductResponse{Product: toProtoProduct(prod)}, nil
+}
+
+func (s *grpcProductServer) UpdateProduct(ctx context.Context, req *product.UpdateProductRequest) (*product.UpdateProductResponse, error) {
+	if req.ExpectedVersion == 0 {
+		return nil, problem.ToGRPC(problem.NewValidation(problem.FieldError{
+			Field: "expected_version", Code: "required", Message: "is required",
+		}), "")
+	}
+
+	app, err := s.app(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Maskede olmayan alanlar kayıtlı üründen gelir
+	prod, err := app.productService.GetProductByID(uint(req.Id))
+	if err != nil {
+		return nil, productWriteError(req.Id, err)
+	}
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		if err := applyProtoInput(prod, req.GetProduct(), paths); err != nil {
+			return nil, problem.ToGRPC(err, "")
+		}
+	} else {
+		// Maske yoksa tüm alanlar yazılır; HTTP PUT gibi boş external_id
+		// kayıtlı değeri korur, boş currency'yi service korur
+		stored := prod.ExternalID
+		_ = applyProtoInput(prod, req.GetProduct(), productInputFields)
+		if prod.ExternalID == nil {
+			prod.ExternalID = stored
+		}
+	}
+	if err := app.productService.UpdateProduct(prod, uint(req.ExpectedVersion), grpcActor(ctx)); err != nil {
+		return nil, productWriteError(req.Id, err)
+	}
+
+	return &product.UpdateProductResponse{Product: toProtoProduct(prod)}, nil
+}
+
+// productInputFields update_mask'te kullanılabilen ProductInput alanlarıdır
+var productInputFields = []string{
+	"name", "description", "price", "currency", "reorder_threshold", "category_id", "image_url",
+	"tax_class", "weight_kg", "length_cm", "width_cm", "height_cm", "external_id",
+}
+
+func fromProtoInput(input *product.ProductInput) *model.Product {
+	prod := &model.Product{}
+	_ = applyProtoInput(prod, input, productInputFields)
+	return prod
+}
+
+// applyProtoInput input'un paths'te listelenen alanlarını prod'a yazar;
+// bilinmeyen bir alan adı InvalidArgument olur
+func applyProtoInput(prod *model.Product, input *product.ProductInput, paths []string) error {
+	for _, path := range paths {
+		switch path {
+		case "name":
+			prod.Name = input.GetName()
+		case "description":
+			prod.Description = input.GetDescription()
+		case "price":
+			prod.Price = input.GetPrice()
+		case "currency":
+			prod.Currency = input.GetCurrency()
+		case "reorder_threshold":
+			prod.ReorderThreshold = int(input.GetReorderThreshold())
+		case "category_id":
+			prod.CategoryID = nil
+			if input.GetCategoryId() != 0 {
+				categoryID := uint(input.GetCategoryId())
+				prod.CategoryID = &categoryID
+			}
+		case "image_url":
+			prod.ImageURL = input.GetImageUrl()
+		case "tax_class":
+			prod.TaxClass = input.GetTaxClass()
+		case "weight_kg":
+			prod.WeightKg = input.GetWeightKg()
+		case "length_cm":
+			prod.LengthCm = input.GetLengthCm()
+		case "width_cm":
+			prod.WidthCm = input.GetWidthCm()
+		case "height_cm":
+			prod.HeightCm = input.GetHeightCm()
+		case "external_id":
+			prod.ExternalID = nil
+			if input.GetExternalId() != "" {
+				externalID := input.GetExternalId()
+				prod.ExternalID = &externalID
+			}
+		default:
+			return problem.NewValidation(problem.FieldError{
+				Field: "update_mask", Code: "unknown_field", Message: fmt.Sprintf("unknown product field %q", path),
+			})
+		}
+	}
+	return nil
+}
+
+// productWriteError servis hatalarını gRPC kodlarına çevirir; alan
+// ihlalleri BadRequest detayıyla InvalidArgument olur
+func productWriteError(id uint32, err error) error {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return productNotFoundError(id, product.ReasonProductNotFound, nil)
+	case errors.Is(err, service.ErrUnknownCategory):
+		err = problem.NewValidation(problem.FieldError{
+			Field: "category_id", Code: "not_found", Message: "category not found",
+		})
+	}
+	return problem.ToGRPC(err, "product.")
+}
+
+func toProtoProduct(prod *model.Product) *product.Product {
+	var categoryID uint32
+	var categoryName string
+	if prod.CategoryID != nil {
+		categoryID = uint32(*prod.CategoryID)
+	}
+	if prod.Category != nil {
+		categoryName = prod.Category.Name
+	}
+
+	options := make([]*product.ProductOption, 0, len(prod.Options))
+	for _, option := range prod.Options {
+		options = append(options, &product.ProductOption{Name: option.Name, Values: option.Values})
+	}
+
+	// İstenen para birimi varsa fiyatlar ona çevrilmiş olarak gönderilir
+	price, currencyCode := prod.Price, prod.Currency
+	if prod.DisplayPrice != nil {
+		price, currencyCode = prod.DisplayPrice.Amount, prod.DisplayPrice.Currency
+	}
+
+	variants := make([]*product.ProductVariant, 0, len(prod.Variants))
+	for _, variant := range prod.Variants {
+		variantPrice := variant.Price
+		if variant.DisplayPrice != nil {
+			variantPrice = variant.DisplayPrice.Amount
+		}
+		variants = append(variants, &product.ProductVariant{
+			Id:         uint32(variant.ID),
+			Sku:        variant.SKU,
+			Attributes: variant.Attributes,
+			Price:      variantPrice,
+			Stock:      int32(variant.Stock),
+			ImageUrl:   variant.ImageURL,
 		})
 	}
 
-	return &product.GetProductsResponse{Products: products}, nil
+	stockLevels := make([]*product.StockLevel, 0, len(prod.StockLevels))
+	for _, level := range prod.StockLevels {
+		stockLevel := &product.StockLevel{
+			WarehouseId: uint32(level.WarehouseID),
+			Quantity:    int32(level.Quantity),
+		}
+		if level.Warehouse != nil {
+			stockLevel.WarehouseCode = level.Warehouse.Code
+			stockLevel.Active = level.Warehouse.Active
+		}
+		stockLevels = append(stockLevels, stockLevel)
+	}
+
+	var externalID string
+	if prod.ExternalID != nil {
+		externalID = *prod.ExternalID
+	}
+
+	return &product.Product{
+		Id:               uint32(prod.ID),
+		Name:             prod.Name,
+		Description:      prod.Description,
+		Price:            price,
+		Currency:         currencyCode,
+		Locale:           prod.Locale,
+		Stock:            int32(prod.Stock),
+		Category:         categoryName,
+		ImageUrl:         prod.ImageURL,
+		CreatedAt:        prod.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:        prod.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CategoryId:       categoryID,
+		Options:          options,
+		Variants:         variants,
+		StockLevels:      stockLevels,
+		Version:          uint32(prod.Version),
+		ExternalId:       externalID,
+		ReorderThreshold: int32(prod.ReorderThreshold),
+		TaxClass:         prod.TaxClass,
+		WeightKg:         prod.WeightKg,
+		LengthCm:         prod.LengthCm,
+		WidthCm:          prod.WidthCm,
+		HeightCm:         prod.HeightCm,
+	}
+}
+
+func productNotFoundError(id uint32, reason string, metadata map[string]string) error {
+	st := status.Newf(codes.NotFound, "product %d not found", id)
+	if reason == product.ReasonProductDeleted {
+		st = status.Newf(codes.NotFound, "product %d has been deleted", id)
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   product.ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func newBlobStore(cfg *config.Config) (storage.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "local":
+		return storage.NewLocalStore(cfg.MediaDir, cfg.MediaBaseURL)
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 blob store")
+		}
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.S3PublicURL,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
 }
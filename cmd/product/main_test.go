@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/service"
+	"cluster-iac/internal/tenant"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type grpcFixture struct {
+	client product.ProductServiceClient
+	repo   repository.ProductRepository
+}
+
+// newGRPCFixture Default tenant'ı SQLite üzerinde kurar ve sunucuyu bellek
+// içi bir bağlantı (bufconn) üzerinden çağıran bir istemci döndürür
+func newGRPCFixture(t *testing.T) *grpcFixture {
+	t.Helper()
+	databasetest.Open(t)
+	db := database.ForTenant(tenant.Default)
+	currencies, err := currency.NewConverter(context.Background(), currency.NewSource(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locales, err := locale.NewResolver("en", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenants, err := tenant.ParseSet("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	productRepo := repository.NewProductRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	app := &tenantApp{
+		id:                 tenant.Default,
+		productService:     service.NewProductService(productRepo, categoryRepo, currencies, nil),
+		pricingService:     service.NewPricingService(repository.NewPriceListRepository(db), productRepo, repository.NewVariantRepository(db), currencies, nil),
+		translationService: service.NewTranslationService(repository.NewTranslationRepository(db), productRepo, categoryRepo, locales, nil),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	product.RegisterProductServiceServer(server, &grpcProductServer{tenants: tenants, apps: map[string]*tenantApp{tenant.Default: app}})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &grpcFixture{client: product.NewProductServiceClient(conn), repo: productRepo}
+}
+
+func (f *grpcFixture) create(t *testing.T, product *model.Product) *model.Product {
+	t.Helper()
+	if err := f.repo.Create(product, model.Actor{Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	return product
+}
+
+func assertCode(t *testing.T, name string, err error, want codes.Code) *status.Status {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != want {
+		t.Fatalf("%s: code = %s (%v), want %s", name, st.Code(), err, want)
+	}
+	return st
+}
+
+// fieldViolations BadRequest detayındaki alan -> açıklama eşlemesidir
+func fieldViolations(st *status.Status) map[string]string {
+	violations := map[string]string{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				violations[violation.Field] = violation.Description
+			}
+		}
+	}
+	return violations
+}
+
+func errorInfo(st *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestGRPCErrorDetails(t *testing.T) {
+	f := newGRPCFixture(t)
+	ctx := context.Background()
+	existing := f.create(t, &model.Product{Name: "Mug", Price: 10, Currency: "TRY"})
+	deleted := f.create(t, &model.Product{Name: "Plate", Price: 5, Currency: "TRY"})
+	if err := f.repo.Delete(deleted.ID, deleted.Version, model.Actor{Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alan ihlalleri istek mesajındaki yollarıyla BadRequest detayı olur
+	_, err := f.client.CreateProduct(ctx, &product.CreateProductRequest{Product: &product.ProductInput{Price: -1, Currency: "XXX"}})
+	violations := fieldViolations(assertCode(t, "invalid product", err, codes.InvalidArgument))
+	for _, field := range []string{"product.name", "product.price", "product.currency"} {
+		if violations[field] == "" {
+			t.Fatalf("invalid product: no violation for %s in %v", field, violations)
+		}
+	}
+
+	_, err = f.client.UpdateProduct(ctx, &product.UpdateProductRequest{Id: uint32(existing.ID), Product: &product.ProductInput{Name: "Mug"}})
+	if violations := fieldViolations(assertCode(t, "missing version", err, codes.InvalidArgument)); violations["expected_version"] == "" {
+		t.Fatalf("missing version violations = %v", violations)
+	}
+
+	_, err = f.client.UpdateProduct(ctx, &product.UpdateProductRequest{
+		Id: uint32(existing.ID), ExpectedVersion: uint32(existing.Version),
+		Product: &product.ProductInput{Name: "Mug", Price: 10, CategoryId: 99},
+	})
+	if violations := fieldViolations(assertCode(t, "unknown category", err, codes.InvalidArgument)); violations["product.category_id"] != "category not found" {
+		t.Fatalf("unknown category violations = %v", violations)
+	}
+
+	_, err = f.client.UpdateProduct(ctx, &product.UpdateProductRequest{
+		Id: uint32(existing.ID), ExpectedVersion: uint32(existing.Version) + 5,
+		Product: &product.ProductInput{Name: "Mug", Price: 10},
+	})
+	assertCode(t, "stale version", err, codes.Aborted)
+
+	_, err = f.client.UpdateProduct(ctx, &product.UpdateProductRequest{
+		Id: uint32(existing.ID), ExpectedVersion: uint32(existing.Version),
+		Product: &product.ProductInput{Price: 12}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"price", "stock"}},
+	})
+	if violations := fieldViolations(assertCode(t, "unknown mask field", err, codes.InvalidArgument)); violations["update_mask"] == "" {
+		t.Fatalf("unknown mask field violations = %v", violations)
+	}
+
+	// NotFound, ürünün hiç olmadığını ya da silindiğini ErrorInfo reason'ıyla ayırır
+	for _, tc := range []struct {
+		name   string
+		id     uint
+		reason string
+	}{
+		{"missing", 999, product.ReasonProductNotFound},
+		{"deleted", deleted.ID, product.ReasonProductDeleted},
+	} {
+		_, err := f.client.GetProduct(ctx, &product.GetProductRequest{Id: uint32(tc.id)})
+		info := errorInfo(assertCode(t, tc.name, err, codes.NotFound))
+		if info == nil || info.Reason != tc.reason || info.Domain != product.ErrorDomain {
+			t.Fatalf("%s: error info = %v, want reason %s", tc.name, info, tc.reason)
+		}
+	}
+	_, err = f.client.UpdateProduct(ctx, &product.UpdateProductRequest{Id: 999, ExpectedVersion: 1, Product: &product.ProductInput{Name: "Mug"}})
+	if info := errorInfo(assertCode(t, "update missing", err, codes.NotFound)); info == nil || info.Reason != product.ReasonProductNotFound {
+		t.Fatalf("update missing: error info = %v", info)
+	}
+
+	unknownTenant := metadata.AppendToOutgoingContext(ctx, tenant.MetadataKey, "globex")
+	_, err = f.client.GetProduct(unknownTenant, &product.GetProductRequest{Id: uint32(existing.ID)})
+	assertCode(t, "unknown tenant", err, codes.NotFound)
+}
+
+func TestGRPCUpdateProductFieldMask(t *testing.T) {
+	f := newGRPCFixture(t)
+	ctx := context.Background()
+	externalID := "ERP-1"
+	existing := f.create(t, &model.Product{ExternalID: &externalID, Name: "Mug", Description: "Blue", Price: 10, Currency: "USD"})
+
+	update := func(version uint, input *product.ProductInput, paths ...string) *product.Product {
+		t.Helper()
+		req := &product.UpdateProductRequest{Id: uint32(existing.ID), ExpectedVersion: uint32(version), Product: input}
+		if len(paths) > 0 {
+			req.UpdateMask = &fieldmaskpb.FieldMask{Paths: paths}
+		}
+		resp, err := f.client.UpdateProduct(ctx, req)
+		if err != nil {
+			t.Fatalf("UpdateProduct %v: %v", paths, err)
+		}
+		return resp.Product
+	}
+
+	// Yalnızca maskedeki alan değişir; boş external_id ve currency korunur
+	got := update(existing.Version, &product.ProductInput{Price: 12}, "price")
+	if got.Price != 12 || got.Name != "Mug" || got.Description != "Blue" || got.ExternalId != "ERP-1" || got.Currency != "USD" {
+		t.Fatalf("after price mask = %+v", got)
+	}
+
+	// Maske yoksa tüm alanlar yazılır, boş external_id ve currency yine korunur
+	got = update(uint(got.Version), &product.ProductInput{Name: "Big mug", Price: 14})
+	if got.Name != "Big mug" || got.Description != "" || got.ExternalId != "ERP-1" || got.Currency != "USD" {
+		t.Fatalf("after full update = %+v", got)
+	}
+
+	// Maskede listelenen boş external_id kaydı siler
+	got = update(uint(got.Version), &product.ProductInput{}, "external_id")
+	if got.ExternalId != "" || got.Name != "Big mug" {
+		t.Fatalf("after clearing external_id = %+v", got)
+	}
+	stored, err := f.repo.GetByID(existing.ID)
+	if err != nil || stored.ExternalID != nil || stored.Price != 14 || stored.Currency != "USD" {
+		t.Fatalf("stored product = %+v (%v)", stored, err)
+	}
+}
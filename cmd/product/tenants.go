@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/product/alerts"
+	"cluster-iac/internal/product/config"
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/handler"
+	"cluster-iac/internal/product/jobs"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/service"
+	"cluster-iac/internal/product/storage"
+	"cluster-iac/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// shared tüm tenant'ların ortak kullandığı bağımlılıklardır
+type shared struct {
+	cfg         *config.Config
+	redisClient *redis.Client
+	eventBus    *events.Bus
+	currencies  *currency.Converter
+	locales     *locale.Resolver
+	blobStore   storage.BlobStore
+	notifiers   alerts.Notifier
+}
+
+// tenantApp bir tenant'ın service, handler ve router'ıdır. Repository'ler
+// tenant'la sınırlanmış veritabanı bağlantısını kullanır; service'ler tenant'ı
+// ayrıca bilmez.
+type tenantApp struct {
+	id                 string
+	productService     service.ProductService
+	pricingService     service.PricingService
+	translationService service.TranslationService
+	router             *gin.Engine
+}
+
+// startTenant tenant'ın service'lerini kurar ve arka plan işlerini başlatır
+func startTenant(ctx context.Context, id string, deps *shared) *tenantApp {
+	// Arka plan işlerinin log satırları tenant'ı taşır
+	ctx = tenant.NewContext(ctx, id)
+	cfg := deps.cfg
+	db := database.ForTenant(id)
+	publisher := events.ForTenant(deps.eventBus, id)
+
+	// Repository, service ve handler oluştur
+	productRepo := repository.NewProductRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	productService := service.NewProductService(productRepo, categoryRepo, deps.currencies, publisher)
+	variantRepo := repository.NewVariantRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	categoryService := service.NewCategoryService(categoryRepo)
+	variantService := service.NewVariantService(variantRepo, productRepo, publisher)
+	inventoryService := service.NewInventoryService(inventoryRepo, productRepo, publisher)
+	priceService := service.NewPriceService(repository.NewPriceRepository(db), productRepo, publisher)
+	pricingService := service.NewPricingService(repository.NewPriceListRepository(db), productRepo, variantRepo, deps.currencies, publisher)
+	translationService := service.NewTranslationService(repository.NewTranslationRepository(db), productRepo, categoryRepo, deps.locales, publisher)
+	importService := service.NewImportService(repository.NewImportRepository(db), publisher, deps.currencies, cfg.ImportDir, cfg.ImportBatchSize)
+
+	// Küçük görsel kuyruğu tenant başınadır; worker görselleri tenant bağlantısıyla okur
+	thumbnailQueue := make(chan uint, 256)
+	imageLimits := service.ImageLimits{
+		MaxBytes:      cfg.ImageMaxBytes,
+		MaxPixels:     cfg.ImageMaxPixels,
+		MaxPerProduct: cfg.ImageMaxPerProduct,
+		ThumbnailSize: cfg.ThumbnailSize,
+	}
+	imageService := service.NewImageService(repository.NewImageRepository(db), productRepo, deps.blobStore, publisher, imageLimits, thumbnailQueue)
+	go jobs.RunImageWorker(ctx, imageService, thumbnailQueue, cfg.ImageWorkerInterval)
+
+	// Önceki çalıştırmada yarıda kalan içe aktarma işlerini kapat
+	if err := importService.RecoverInterrupted(); err != nil {
+		slog.ErrorContext(ctx, "failed to recover import jobs", "error", err)
+	}
+
+	// Retention süresi dolan silinmiş ürünleri temizle
+	go jobs.RunTrashPurger(ctx, productService, cfg.TrashRetention, cfg.TrashPurgeInterval)
+
+	// Planlı fiyat değişikliklerini uygula ve süresi dolanları geri al
+	go jobs.RunPriceScheduler(ctx, priceService, cfg.PriceSchedulerInterval)
+
+	// Stok eşiklerini izle ve uyarıları notifier'lara ilet
+	stockAlertService := service.NewStockAlertService(repository.NewStockAlertRepository(db), deps.notifiers, publisher)
+	go jobs.RunStockAlertEvaluator(ctx, deps.eventBus, id, stockAlertService, cfg.StockAlertSweepInterval)
+
+	return &tenantApp{
+		id:                 id,
+		productService:     productService,
+		pricingService:     pricingService,
+		translationService: translationService,
+		router: newRouter(cfg, deps.redisClient, routeHandlers{
+			product:     handler.NewProductHandler(productService, pricingService, translationService, cfg.TrashRetention),
+			category:    handler.NewCategoryHandler(categoryService, translationService),
+			variant:     handler.NewVariantHandler(variantService),
+			inventory:   handler.NewInventoryHandler(inventoryService),
+			price:       handler.NewPriceHandler(priceService),
+			importer:    handler.NewImportHandler(importService, cfg.ImportMaxBytes),
+			image:       handler.NewImageHandler(imageService, cfg.ImageMaxBytes, cfg.ImageMaxPerProduct),
+			pricing:     handler.NewPricingHandler(pricingService),
+			translation: handler.NewTranslationHandler(translationService),
+			audit:       handler.NewAuditHandler(service.NewAuditService(repository.NewAuditRepository(db))),
+		}),
+	}
+}
+
+// tenantRouter isteği X-Tenant-ID başlığından çözülen tenant'ın router'ına verir
+func tenantRouter(tenants *tenant.Set, apps map[string]*tenantApp) http.Handler {
+	return tenants.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := tenant.FromContext(r.Context())
+		apps[id].router.ServeHTTP(w, r)
+	}))
+}
@@ -2,16 +2,27 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/logging"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/ratelimit"
+	"cluster-iac/internal/tenant"
+
+	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 type Config struct {
@@ -25,6 +36,15 @@ func main() {
 	productServiceURL := getEnv("PRODUCT_SERVICE_URL", "http://localhost:8080")
 	basketServiceURL := getEnv("BASKET_SERVICE_URL", "http://localhost:8081")
 	gatewayPort := getEnv("GATEWAY_PORT", "8082")
+	// Log seviyesi uç noktası dışarı açılmasın diye ayrı porttan sunulur
+	adminPort := getEnv("GATEWAY_ADMIN_PORT", "9082")
+
+	level, err := logging.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		slog.Error("Invalid LOG_LEVEL", "error", err)
+		os.Exit(1)
+	}
+	levelVar := logging.Setup("api-gateway", level)
 
 	config := &Config{
 		ProductServiceURL: productServiceURL,
@@ -32,16 +52,95 @@ func main() {
 		GatewayPort:      gatewayPort,
 	}
 
+	// Servis yanıt başlıklarını bu süre içinde göndermezse 504 döner; gövde
+	// akışı (ör. export) süre sınırına tabi değildir
+	upstreamTimeout, err := time.ParseDuration(getEnv("UPSTREAM_TIMEOUT", "30s"))
+	if err != nil {
+		slog.Error("Invalid UPSTREAM_TIMEOUT", "error", err)
+		os.Exit(1)
+	}
+	// Tenant'lar servislerle aynı TENANTS listesinden; TENANT_HOSTS host adlarını tenant'lara eşler
+	tenants, err := tenant.ParseSet(getEnv("TENANTS", ""))
+	if err != nil {
+		slog.Error("Invalid TENANTS", "error", err)
+		os.Exit(1)
+	}
+	tenantHosts, err := tenant.ParseHosts(getEnv("TENANT_HOSTS", ""), tenants)
+	if err != nil {
+		slog.Error("Invalid TENANT_HOSTS", "error", err)
+		os.Exit(1)
+	}
+
+	// Hız sınırları Redis'te tutulur ki tüm gateway'ler aynı sayaçları kullansın;
+	// REDIS_ADDR verilmezse sınırlama kapalıdır
+	var limiter ratelimit.Limiter
+	rateLimits, err := ratelimit.LoadPolicy(getEnv("RATE_LIMITS_FILE", ""))
+	if err != nil {
+		slog.Error("Failed to load rate limits", "error", err)
+		os.Exit(1)
+	}
+	for _, key := range rateLimits.APIKeys {
+		if _, err := retention.ParseClass(key.UserClass); key.UserClass != "" && err != nil {
+			slog.Error("Invalid user class for API key", "api_key", key.Name, "user_class", key.UserClass)
+			os.Exit(1)
+		}
+	}
+	if redisAddr := getEnv("REDIS_ADDR", ""); redisAddr != "" {
+		redisClient := redis.NewClient(&redis.Options{Addr: redisAddr, Password: getEnv("REDIS_PASSWORD", "")})
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			slog.Error("Failed to connect to Redis", "error", err)
+			os.Exit(1)
+		}
+		limiter = ratelimit.NewRedisLimiter(redisClient, "ratelimit:")
+	} else {
+		slog.Warn("REDIS_ADDR is not set, rate limiting is disabled")
+	}
+
+	// Gateway bir proxy'nin (ör. nginx) arkasındaysa istemci IP'si yalnızca bu
+	// adreslerden gelen X-Real-IP başlığından okunur
+	var trustedProxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = upstreamTimeout
+	upstreamClient.Transport = transport
+
 	app := fiber.New(fiber.Config{
 		AppName: "Cluster IAC API Gateway",
+		// Toplu ürün içe aktarma dosyaları varsayılan 4MB sınırını aşabilir
+		BodyLimit:               50 * 1024 * 1024,
+		EnableTrustedProxyCheck: len(trustedProxies) > 0,
+		TrustedProxies:          trustedProxies,
+		ProxyHeader:             proxyHeader(trustedProxies),
+		// Gateway'in kendi hataları (bilinmeyen route, gövde sınırı) da problem+json döner
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			code := fiber.StatusInternalServerError
+			detail := "an unexpected error occurred"
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				code = fiberErr.Code
+				detail = fiberErr.Message
+			} else {
+				slog.ErrorContext(requestContext(c), "gateway error", "method", c.Method(), "path", c.Path(), "error", err)
+			}
+
+			p := problem.ForStatus(code, detail)
+			p.Instance = c.Path()
+			return c.Status(code).JSON(p, problem.ContentType)
+		},
 	})
 
 	// Middleware
-	app.Use(logger.New())
+	app.Use(accessLog())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowMethods:  "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,If-Match,X-Request-ID,Idempotency-Key,X-API-Key,Accept-Currency,Accept-Language,X-Tenant-ID",
+		ExposeHeaders: "ETag,Location,Idempotent-Replayed,Content-Language,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After",
 	}))
 
 	// Health check
@@ -53,43 +152,216 @@ func main() {
 		})
 	})
 
+	// Health check dışındaki istekler hız sınırından geçer ve bir tenant'a çözülür
+	if limiter != nil {
+		app.Use(rateLimit(rateLimits, limiter))
+	}
+	app.Use(resolveTenant(tenants, tenantHosts))
+	app.Use(resolveIdentity(rateLimits))
+
 	// Product Service Routes
 	productGroup := app.Group("/api/products")
 	{
 		productGroup.Post("/", proxyToService(config.ProductServiceURL+"/products/", "POST"))
+		productGroup.Post("/import", proxyToService(config.ProductServiceURL+"/products/import", "POST"))
+		productGroup.Get("/export", proxyToService(config.ProductServiceURL+"/products/export", "GET"))
 		productGroup.Get("/", proxyToService(config.ProductServiceURL+"/products/", "GET"))
 		productGroup.Get("/category", proxyToService(config.ProductServiceURL+"/products/category", "GET"))
+		productGroup.Get("/trash", proxyToService(config.ProductServiceURL+"/products/trash", "GET"))
 		productGroup.Get("/:id", proxyToService(config.ProductServiceURL+"/products/:id", "GET"))
 		productGroup.Put("/:id", proxyToService(config.ProductServiceURL+"/products/:id", "PUT"))
+		productGroup.Patch("/:id", proxyToService(config.ProductServiceURL+"/products/:id", "PATCH"))
 		productGroup.Delete("/:id", proxyToService(config.ProductServiceURL+"/products/:id", "DELETE"))
+		productGroup.Post("/:id/restore", proxyToService(config.ProductServiceURL+"/products/:id/restore", "POST"))
+		productGroup.Get("/:id/options", proxyToService(config.ProductServiceURL+"/products/:id/options", "GET"))
+		productGroup.Put("/:id/options", proxyToService(config.ProductServiceURL+"/products/:id/options", "PUT"))
+		productGroup.Get("/:id/variants", proxyToService(config.ProductServiceURL+"/products/:id/variants", "GET"))
+		productGroup.Post("/:id/variants", proxyToService(config.ProductServiceURL+"/products/:id/variants", "POST"))
+		productGroup.Put("/:id/variants/:variant_id", proxyToService(config.ProductServiceURL+"/products/:id/variants/:variant_id", "PUT"))
+		productGroup.Delete("/:id/variants/:variant_id", proxyToService(config.ProductServiceURL+"/products/:id/variants/:variant_id", "DELETE"))
+		productGroup.Get("/:id/inventory", proxyToService(config.ProductServiceURL+"/products/:id/inventory", "GET"))
+		productGroup.Get("/:id/images", proxyToService(config.ProductServiceURL+"/products/:id/images", "GET"))
+		productGroup.Post("/:id/images", proxyToService(config.ProductServiceURL+"/products/:id/images", "POST"))
+		productGroup.Put("/:id/images/order", proxyToService(config.ProductServiceURL+"/products/:id/images/order", "PUT"))
+		productGroup.Delete("/:id/images/:image_id", proxyToService(config.ProductServiceURL+"/products/:id/images/:image_id", "DELETE"))
+		productGroup.Get("/:id/prices", proxyToService(config.ProductServiceURL+"/products/:id/prices", "GET"))
+		productGroup.Post("/:id/prices/schedules", proxyToService(config.ProductServiceURL+"/products/:id/prices/schedules", "POST"))
+		productGroup.Delete("/:id/prices/schedules/:schedule_id", proxyToService(config.ProductServiceURL+"/products/:id/prices/schedules/:schedule_id", "DELETE"))
+		productGroup.Get("/:id/price-lists", proxyToService(config.ProductServiceURL+"/products/:id/price-lists", "GET"))
+		productGroup.Get("/:id/translations", proxyToService(config.ProductServiceURL+"/products/:id/translations", "GET"))
+		productGroup.Put("/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/products/:id/translations/:locale", "PUT"))
+		productGroup.Delete("/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/products/:id/translations/:locale", "DELETE"))
+	}
+
+	// Currency & Price List Routes
+	app.Get("/api/currencies", proxyToService(config.ProductServiceURL+"/currencies", "GET"))
+	priceListGroup := app.Group("/api/price-lists")
+	{
+		priceListGroup.Get("/:currency", proxyToService(config.ProductServiceURL+"/price-lists/:currency", "GET"))
+		priceListGroup.Put("/:currency/products/:id", proxyToService(config.ProductServiceURL+"/price-lists/:currency/products/:id", "PUT"))
+		priceListGroup.Delete("/:currency/products/:id", proxyToService(config.ProductServiceURL+"/price-lists/:currency/products/:id", "DELETE"))
+	}
+
+	// Category Routes
+	categoryGroup := app.Group("/api/categories")
+	{
+		categoryGroup.Post("/", proxyToService(config.ProductServiceURL+"/categories/", "POST"))
+		categoryGroup.Get("/", proxyToService(config.ProductServiceURL+"/categories/", "GET"))
+		categoryGroup.Get("/tree", proxyToService(config.ProductServiceURL+"/categories/tree", "GET"))
+		categoryGroup.Get("/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "GET"))
+		categoryGroup.Put("/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "PUT"))
+		categoryGroup.Delete("/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "DELETE"))
+		categoryGroup.Get("/:id/translations", proxyToService(config.ProductServiceURL+"/categories/:id/translations", "GET"))
+		categoryGroup.Put("/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/categories/:id/translations/:locale", "PUT"))
+		categoryGroup.Delete("/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/categories/:id/translations/:locale", "DELETE"))
+	}
+
+	// Translation Routes
+	translationGroup := app.Group("/api/translations")
+	{
+		translationGroup.Get("/completeness", proxyToService(config.ProductServiceURL+"/translations/completeness", "GET"))
+		translationGroup.Get("/:locale/missing", proxyToService(config.ProductServiceURL+"/translations/:locale/missing", "GET"))
 	}
 
+	// Warehouse & Inventory Routes
+	warehouseGroup := app.Group("/api/warehouses")
+	{
+		warehouseGroup.Post("/", proxyToService(config.ProductServiceURL+"/warehouses/", "POST"))
+		warehouseGroup.Get("/", proxyToService(config.ProductServiceURL+"/warehouses/", "GET"))
+		warehouseGroup.Get("/:id", proxyToService(config.ProductServiceURL+"/warehouses/:id", "GET"))
+		warehouseGroup.Put("/:id", proxyToService(config.ProductServiceURL+"/warehouses/:id", "PUT"))
+	}
+
+	inventoryGroup := app.Group("/api/inventory")
+	{
+		inventoryGroup.Post("/movements", proxyToService(config.ProductServiceURL+"/inventory/movements", "POST"))
+		inventoryGroup.Get("/movements", proxyToService(config.ProductServiceURL+"/inventory/movements", "GET"))
+	}
+
+	importGroup := app.Group("/api/imports")
+	{
+		importGroup.Get("/:id", proxyToService(config.ProductServiceURL+"/imports/:id", "GET"))
+		importGroup.Get("/:id/rows", proxyToService(config.ProductServiceURL+"/imports/:id/rows", "GET"))
+		importGroup.Get("/:id/errors", proxyToService(config.ProductServiceURL+"/imports/:id/errors", "GET"))
+	}
+
+	app.Get("/api/audit", proxyToService(config.ProductServiceURL+"/audit", "GET"))
+
+	// Yerel blob store'daki ürün görselleri; Fiber "*" wildcard'ını "*1" adıyla verir
+	app.Get("/media/*", proxyToService(config.ProductServiceURL+"/media/:*1", "GET"))
+
 	// Basket Service Routes
 	basketGroup := app.Group("/api/baskets")
 	{
 		basketGroup.Get("/:user_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id", "GET"))
+		basketGroup.Get("/:user_id/expiry", proxyToService(config.BasketServiceURL+"/baskets/:user_id/expiry", "GET"))
+		basketGroup.Get("/:user_id/shipping-options", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping-options", "GET"))
+		basketGroup.Put("/:user_id/shipping", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping", "PUT"))
+		basketGroup.Delete("/:user_id/shipping", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping", "DELETE"))
 		basketGroup.Post("/:user_id/items", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items", "POST"))
 		basketGroup.Delete("/:user_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id", "DELETE"))
 		basketGroup.Put("/:user_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id", "PUT"))
 		basketGroup.Delete("/:user_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id", "DELETE"))
+		basketGroup.Post("/:user_id/items/:product_id/save", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id/save", "POST"))
+		basketGroup.Get("/:user_id/lists", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists", "GET"))
+		basketGroup.Post("/:user_id/lists", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists", "POST"))
+		basketGroup.Get("/:user_id/lists/:list_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id", "GET"))
+		basketGroup.Delete("/:user_id/lists/:list_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id", "DELETE"))
+		basketGroup.Post("/:user_id/lists/:list_id/items", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items", "POST"))
+		basketGroup.Delete("/:user_id/lists/:list_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items/:product_id", "DELETE"))
+		basketGroup.Post("/:user_id/lists/:list_id/items/:product_id/move", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items/:product_id/move", "POST"))
+		basketGroup.Post("/:user_id/lists/:list_id/share", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/share", "POST"))
+		basketGroup.Delete("/:user_id/lists/:list_id/share", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/share", "DELETE"))
 	}
+	app.Get("/api/shared-lists/:token", proxyToService(config.BasketServiceURL+"/shared-lists/:token", "GET"))
 
 	// Legacy routes (without /api prefix for backward compatibility)
 	app.Post("/products", proxyToService(config.ProductServiceURL+"/products/", "POST"))
 	app.Get("/products", proxyToService(config.ProductServiceURL+"/products/", "GET"))
 	app.Get("/products/category", proxyToService(config.ProductServiceURL+"/products/category", "GET"))
+	app.Get("/products/trash", proxyToService(config.ProductServiceURL+"/products/trash", "GET"))
+	app.Post("/products/import", proxyToService(config.ProductServiceURL+"/products/import", "POST"))
+	app.Get("/products/export", proxyToService(config.ProductServiceURL+"/products/export", "GET"))
 	app.Get("/products/:id", proxyToService(config.ProductServiceURL+"/products/:id", "GET"))
 	app.Put("/products/:id", proxyToService(config.ProductServiceURL+"/products/:id", "PUT"))
+	app.Patch("/products/:id", proxyToService(config.ProductServiceURL+"/products/:id", "PATCH"))
 	app.Delete("/products/:id", proxyToService(config.ProductServiceURL+"/products/:id", "DELETE"))
+	app.Post("/products/:id/restore", proxyToService(config.ProductServiceURL+"/products/:id/restore", "POST"))
+	app.Get("/products/:id/options", proxyToService(config.ProductServiceURL+"/products/:id/options", "GET"))
+	app.Put("/products/:id/options", proxyToService(config.ProductServiceURL+"/products/:id/options", "PUT"))
+	app.Get("/products/:id/variants", proxyToService(config.ProductServiceURL+"/products/:id/variants", "GET"))
+	app.Post("/products/:id/variants", proxyToService(config.ProductServiceURL+"/products/:id/variants", "POST"))
+	app.Put("/products/:id/variants/:variant_id", proxyToService(config.ProductServiceURL+"/products/:id/variants/:variant_id", "PUT"))
+	app.Delete("/products/:id/variants/:variant_id", proxyToService(config.ProductServiceURL+"/products/:id/variants/:variant_id", "DELETE"))
+	app.Get("/products/:id/inventory", proxyToService(config.ProductServiceURL+"/products/:id/inventory", "GET"))
+	app.Get("/products/:id/images", proxyToService(config.ProductServiceURL+"/products/:id/images", "GET"))
+	app.Post("/products/:id/images", proxyToService(config.ProductServiceURL+"/products/:id/images", "POST"))
+	app.Put("/products/:id/images/order", proxyToService(config.ProductServiceURL+"/products/:id/images/order", "PUT"))
+	app.Delete("/products/:id/images/:image_id", proxyToService(config.ProductServiceURL+"/products/:id/images/:image_id", "DELETE"))
+	app.Get("/products/:id/prices", proxyToService(config.ProductServiceURL+"/products/:id/prices", "GET"))
+	app.Post("/products/:id/prices/schedules", proxyToService(config.ProductServiceURL+"/products/:id/prices/schedules", "POST"))
+	app.Delete("/products/:id/prices/schedules/:schedule_id", proxyToService(config.ProductServiceURL+"/products/:id/prices/schedules/:schedule_id", "DELETE"))
+	app.Get("/products/:id/price-lists", proxyToService(config.ProductServiceURL+"/products/:id/price-lists", "GET"))
+	app.Get("/products/:id/translations", proxyToService(config.ProductServiceURL+"/products/:id/translations", "GET"))
+	app.Put("/products/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/products/:id/translations/:locale", "PUT"))
+	app.Delete("/products/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/products/:id/translations/:locale", "DELETE"))
+
+	app.Get("/currencies", proxyToService(config.ProductServiceURL+"/currencies", "GET"))
+	app.Get("/price-lists/:currency", proxyToService(config.ProductServiceURL+"/price-lists/:currency", "GET"))
+	app.Put("/price-lists/:currency/products/:id", proxyToService(config.ProductServiceURL+"/price-lists/:currency/products/:id", "PUT"))
+	app.Delete("/price-lists/:currency/products/:id", proxyToService(config.ProductServiceURL+"/price-lists/:currency/products/:id", "DELETE"))
+
+	app.Post("/categories", proxyToService(config.ProductServiceURL+"/categories/", "POST"))
+	app.Get("/categories", proxyToService(config.ProductServiceURL+"/categories/", "GET"))
+	app.Get("/categories/tree", proxyToService(config.ProductServiceURL+"/categories/tree", "GET"))
+	app.Get("/categories/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "GET"))
+	app.Put("/categories/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "PUT"))
+	app.Delete("/categories/:id", proxyToService(config.ProductServiceURL+"/categories/:id", "DELETE"))
+	app.Get("/categories/:id/translations", proxyToService(config.ProductServiceURL+"/categories/:id/translations", "GET"))
+	app.Put("/categories/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/categories/:id/translations/:locale", "PUT"))
+	app.Delete("/categories/:id/translations/:locale", proxyToService(config.ProductServiceURL+"/categories/:id/translations/:locale", "DELETE"))
+	app.Get("/translations/completeness", proxyToService(config.ProductServiceURL+"/translations/completeness", "GET"))
+	app.Get("/translations/:locale/missing", proxyToService(config.ProductServiceURL+"/translations/:locale/missing", "GET"))
+
+	app.Post("/warehouses", proxyToService(config.ProductServiceURL+"/warehouses/", "POST"))
+	app.Get("/warehouses", proxyToService(config.ProductServiceURL+"/warehouses/", "GET"))
+	app.Get("/warehouses/:id", proxyToService(config.ProductServiceURL+"/warehouses/:id", "GET"))
+	app.Put("/warehouses/:id", proxyToService(config.ProductServiceURL+"/warehouses/:id", "PUT"))
+	app.Post("/inventory/movements", proxyToService(config.ProductServiceURL+"/inventory/movements", "POST"))
+	app.Get("/inventory/movements", proxyToService(config.ProductServiceURL+"/inventory/movements", "GET"))
+	app.Get("/imports/:id", proxyToService(config.ProductServiceURL+"/imports/:id", "GET"))
+	app.Get("/imports/:id/rows", proxyToService(config.ProductServiceURL+"/imports/:id/rows", "GET"))
+	app.Get("/imports/:id/errors", proxyToService(config.ProductServiceURL+"/imports/:id/errors", "GET"))
+	app.Get("/audit", proxyToService(config.ProductServiceURL+"/audit", "GET"))
 
 	app.Get("/baskets/:user_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id", "GET"))
+	app.Get("/baskets/:user_id/expiry", proxyToService(config.BasketServiceURL+"/baskets/:user_id/expiry", "GET"))
+	app.Get("/baskets/:user_id/shipping-options", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping-options", "GET"))
+	app.Put("/baskets/:user_id/shipping", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping", "PUT"))
+	app.Delete("/baskets/:user_id/shipping", proxyToService(config.BasketServiceURL+"/baskets/:user_id/shipping", "DELETE"))
 	app.Post("/baskets/:user_id/items", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items", "POST"))
 	app.Delete("/baskets/:user_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id", "DELETE"))
 	app.Put("/baskets/:user_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id", "PUT"))
 	app.Delete("/baskets/:user_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id", "DELETE"))
+	app.Post("/baskets/:user_id/items/:product_id/save", proxyToService(config.BasketServiceURL+"/baskets/:user_id/items/:product_id/save", "POST"))
+	app.Get("/baskets/:user_id/lists", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists", "GET"))
+	app.Post("/baskets/:user_id/lists", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists", "POST"))
+	app.Get("/baskets/:user_id/lists/:list_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id", "GET"))
+	app.Delete("/baskets/:user_id/lists/:list_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id", "DELETE"))
+	app.Post("/baskets/:user_id/lists/:list_id/items", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items", "POST"))
+	app.Delete("/baskets/:user_id/lists/:list_id/items/:product_id", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items/:product_id", "DELETE"))
+	app.Post("/baskets/:user_id/lists/:list_id/items/:product_id/move", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/items/:product_id/move", "POST"))
+	app.Post("/baskets/:user_id/lists/:list_id/share", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/share", "POST"))
+	app.Delete("/baskets/:user_id/lists/:list_id/share", proxyToService(config.BasketServiceURL+"/baskets/:user_id/lists/:list_id/share", "DELETE"))
+	app.Get("/shared-lists/:token", proxyToService(config.BasketServiceURL+"/shared-lists/:token", "GET"))
+
+	go startAdminServer(adminPort, levelVar)
 
-	log.Printf("API Gateway starting on port %s", config.GatewayPort)
-	log.Fatal(app.Listen(fmt.Sprintf(":%s", config.GatewayPort)))
+	slog.Info("API Gateway starting", "port", config.GatewayPort)
+	if err := app.Listen(fmt.Sprintf(":%s", config.GatewayPort)); err != nil {
+		slog.Error("Failed to start API Gateway", "error", err)
+		os.Exit(1)
+	}
 }
 
 func getEnv(key, defaultValue string) string {
@@ -99,6 +371,197 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// upstreamClient tüm servis çağrılarında paylaşılır; transport main'de ayarlanır
+var upstreamClient = &http.Client{}
+
+// writeProblem gateway'in kendi ürettiği hataları servislerle aynı
+// application/problem+json formatında yazar
+func writeProblem(c *fiber.Ctx, kind problem.Kind, detail string) error {
+	p := problem.For(kind, detail)
+	p.Instance = c.Path()
+	return c.Status(p.Status).JSON(p, problem.ContentType)
+}
+
+// resolveTenant tenant'ı önce host adından, host eşlenmemişse X-Tenant-ID
+// başlığından çözer; ikisi de yoksa default tenant kullanılır. Eşlenmiş bir
+// host'a başka bir tenant'ın başlığıyla gelen istek reddedilir, böylece bir
+// mağazanın adresinden diğerinin verisi okunamaz.
+func resolveTenant(tenants *tenant.Set, hosts map[string]string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(tenant.HeaderName)
+
+		host := strings.ToLower(c.Hostname())
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		id, mapped := hosts[host]
+		if mapped {
+			if header != "" && !strings.EqualFold(strings.TrimSpace(header), id) {
+				return writeProblem(c, problem.Invalid, "X-Tenant-ID does not match the tenant of this host")
+			}
+		} else {
+			resolved, err := tenants.Resolve(header)
+			if err != nil {
+				return writeProblem(c, problem.KindOf(err), err.Error())
+			}
+			id = resolved
+		}
+
+		c.Locals(tenantLocal, id)
+		return c.Next()
+	}
+}
+
+// resolveIdentity isteğin kimliğini istemcinin başlıklarından değil, tanınan
+// API anahtarından çözer. Sepetin saklama sınıfı (X-User-Class) anahtarda
+// tanımlıysa iletilir; tanımlı değilse başlık gönderilmez, basket servisi
+// sepette kayıtlı sınıfı, yeni sepette varsayılan sınıfı kullanır. Böylece
+// anonim bir istemci sepetini süresiz (b2b) yapamaz ve anahtarsız bir yazma
+// kayıtlı bir b2b sepetini guest süresine düşürmez. Denetim kaydının aktörü
+// (X-Actor) anahtarın adıdır; anahtarsız isteklerde başlık gönderilmez ve
+// kayıt anonymous olur.
+func resolveIdentity(policy *ratelimit.Policy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if key, ok := policy.Key(c.Get("X-API-Key")); ok {
+			c.Locals(actorLocal, key.Name)
+			if key.UserClass != "" {
+				c.Locals(userClassLocal, key.UserClass)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// proxyHeader güvenilen proxy tanımlıysa istemci IP'sinin okunacağı başlıktır
+func proxyHeader(trustedProxies []string) string {
+	if len(trustedProxies) == 0 {
+		return ""
+	}
+	return "X-Real-IP"
+}
+
+// rateLimit isteğin route kuralına göre IP ya da API anahtarı (X-API-Key)
+// bucket'ından token harcar. Yanıtlar RateLimit-*
+// başlıklarını taşır; limit aşıldığında Retry-After ile 429 döner. Redis'e
+// ulaşılamazsa istekler sınırlanmadan geçer.
+func rateLimit(policy *ratelimit.Policy, limiter ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rule := policy.Match(c.Method(), c.Path())
+		buckets, keyName := policy.Buckets(rule, ratelimit.Client{
+			APIKey: c.Get("X-API-Key"),
+			IP:     c.IP(),
+		})
+
+		result, err := limiter.Allow(c.UserContext(), buckets)
+		if err != nil {
+			slog.WarnContext(requestContext(c), "Rate limit check failed, request is not limited", "rule", rule.Name, "error", err)
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			slog.InfoContext(requestContext(c), "Rate limit exceeded", "rule", rule.Name, "api_key", keyName, "ip", c.IP())
+			return writeProblem(c, problem.TooManyRequests, fmt.Sprintf("rate limit %q exceeded, retry in %d seconds", rule.Name, retryAfter))
+		}
+		return c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// tenantLocal çözülen tenant'ın fiber.Ctx'teki anahtarıdır
+const tenantLocal = "tenant"
+
+// userClassLocal çözülen sepet saklama sınıfının fiber.Ctx'teki anahtarıdır
+const userClassLocal = "user_class"
+
+const userClassHeader = "X-User-Class"
+
+// actorLocal isteği yapan API anahtarının adının fiber.Ctx'teki anahtarıdır
+const actorLocal = "actor"
+
+const actorHeader = "X-Actor"
+
+// requestIDLocal isteğin kimliğinin fiber.Ctx'teki anahtarıdır
+const requestIDLocal = "request_id"
+
+// accessLog isteğin kimliğini X-Request-ID başlığından alır (yoksa üretir),
+// yanıt başlığına yazar ve istek bitince servislerle aynı biçimde bir JSON
+// erişim satırı yazar. Başlıklar yalnızca DEBUG seviyesinde, hassas olanlar
+// gizlenerek loglanır.
+func accessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := logging.EnsureRequestID(c.Get(logging.RequestIDHeader))
+		c.Locals(requestIDLocal, id)
+		c.Set(logging.RequestIDHeader, id)
+
+		// Hata burada yanıta çevrilir ki satır gerçek durum kodunu içersin
+		chainErr := c.Next()
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		ctx := requestContext(c)
+		status := c.Response().StatusCode()
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote_addr", c.IP(),
+		}
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			attrs = append(attrs, "headers", logging.RedactHeaders(c.GetReqHeaders()))
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		slog.Log(ctx, level, "request", attrs...)
+		return nil
+	}
+}
+
+// requestContext log satırlarına istek kimliğini ve tenant'ı ekleyen context'tir
+func requestContext(c *fiber.Ctx) context.Context {
+	ctx := c.UserContext()
+	if id, ok := c.Locals(requestIDLocal).(string); ok {
+		ctx = logging.WithRequestID(ctx, id)
+	}
+	if id, ok := c.Locals(tenantLocal).(string); ok {
+		ctx = tenant.NewContext(ctx, id)
+	}
+	return ctx
+}
+
+// startAdminServer log seviyesi uç noktasını ayrı bir portta sunar
+func startAdminServer(port string, levelVar *slog.LevelVar) {
+	mux := http.NewServeMux()
+	mux.Handle("/admin/log-level", logging.LevelHandler(levelVar))
+
+	slog.Info("Admin server starting", "port", port)
+	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), logging.Middleware(mux)); err != nil {
+		slog.Error("Failed to start admin server", "error", err)
+		os.Exit(1)
+	}
+}
+
 func proxyToService(targetURL string, method string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// URL parametrelerini hedef URL'e ekle
@@ -114,16 +577,15 @@ func proxyToService(targetURL string, method string) fiber.Handler {
 
 		// Request body'yi oku
 		var body io.Reader
-		if method == "POST" || method == "PUT" {
+		if method == "POST" || method == "PUT" || method == "PATCH" {
 			body = bytes.NewReader(c.Body())
 		}
 
 		// HTTP request oluştur
 		req, err := http.NewRequest(method, url, body)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to create request",
-			})
+			slog.ErrorContext(requestContext(c), "Failed to create upstream request", "url", url, "error", err)
+			return writeProblem(c, problem.Internal, "failed to create upstream request")
 		}
 
 		// Headers'ı kopyala
@@ -133,37 +595,60 @@ func proxyToService(targetURL string, method string) fiber.Handler {
 			}
 		})
 
-		// Content-Type header'ı ekle
-		if method == "POST" || method == "PUT" {
-			req.Header.Set("Content-Type", "application/json")
+		// İstemcinin gönderdiği değer değil, gateway'in çözdüğü tenant iletilir
+		if id, ok := c.Locals(tenantLocal).(string); ok {
+			req.Header.Set(tenant.HeaderName, id)
+		}
+		// İstemcinin gönderdiği sınıf silinir; yalnızca gateway'in çözdüğü sınıf iletilir
+		req.Header.Del(userClassHeader)
+		if class, ok := c.Locals(userClassLocal).(string); ok {
+			req.Header.Set(userClassHeader, class)
+		}
+		// Denetim kaydının aktörü istemciden alınmaz; yalnızca anahtarın adı iletilir
+		req.Header.Del(actorHeader)
+		if name, ok := c.Locals(actorLocal).(string); ok {
+			req.Header.Set(actorHeader, name)
+		}
+		// Servis logları gateway'in satırıyla aynı istek kimliğini taşır
+		if id, ok := c.Locals(requestIDLocal).(string); ok {
+			req.Header.Set(logging.RequestIDHeader, id)
 		}
 
-		// HTTP client ile request'i gönder
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
-				"error": "Failed to forward request",
-			})
+		// Content-Type gönderilmediyse JSON varsay; CSV ve multipart yüklemeler olduğu gibi iletilir
+		if (method == "POST" || method == "PUT") && req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
 		}
-		defer resp.Body.Close()
 
-		// Response body'yi oku
-		respBody, err := io.ReadAll(resp.Body)
+		// HTTP client ile request'i gönder
+		resp, err := upstreamClient.Do(req)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to read response",
-			})
+			slog.ErrorContext(requestContext(c), "Failed to forward request", "method", method, "url", url, "error", err)
+			var netErr net.Error
+			if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+				return writeProblem(c, problem.Timeout, "upstream service did not respond in time")
+			}
+			return writeProblem(c, problem.BadGateway, "upstream service is unreachable")
 		}
 
-		// Response headers'ı kopyala
+		// Response headers'ı kopyala; Content-Length body stream'i ile ayarlanır
 		for key, values := range resp.Header {
-			for _, value := range values {
-				c.Set(key, value)
+			if key == "Content-Length" {
+				continue
+			}
+			// Tekrarlanan başlıklar (ör. iki Vary) birleştirilir, son değer ezmez
+			for i, value := range values {
+				if i == 0 {
+					c.Set(key, value)
+				} else {
+					c.Append(key, value)
+				}
 			}
 		}
 
-		// Response'u döndür
-		return c.Status(resp.StatusCode).Send(respBody)
+		// Response'u belleğe almadan aktar (ör. büyük ürün export'ları);
+		// fasthttp body'yi gönderdikten sonra kapatır
+		c.Status(resp.StatusCode)
+		c.Response().SetBodyStream(resp.Body, int(resp.ContentLength))
+		return nil
 	}
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/basket/handler"
+	basketmodel "cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/ratelimit"
+	"cluster-iac/internal/tenant"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+)
+
+// testPolicy sınıfı tanımlı (partner-secret, b2b) ve sınıfsız (plain-secret)
+// iki API anahtarı olan bir policy yükler
+func testPolicy(t *testing.T) *ratelimit.Policy {
+	t.Helper()
+	partner := sha256.Sum256([]byte("partner-secret"))
+	plain := sha256.Sum256([]byte("plain-secret"))
+	path := filepath.Join(t.TempDir(), "limits.json")
+	limits := fmt.Sprintf(`{
+		"default": {"rate": 10, "per": "1s", "burst": 20},
+		"api_keys": [
+			{"name": "partner", "key_sha256": %q, "user_class": "b2b"},
+			{"name": "plain", "key_sha256": %q}
+		]
+	}`, hex.EncodeToString(partner[:]), hex.EncodeToString(plain[:]))
+	if err := os.WriteFile(path, []byte(limits), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := ratelimit.LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestUserClassComesFromAPIKey(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Class", r.Header.Get(userClassHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+	policy := testPolicy(t)
+
+	app := fiber.New()
+	app.Use(resolveIdentity(policy))
+	app.Get("/baskets/:user_id", proxyToService(upstream.URL+"/baskets/:user_id", "GET"))
+
+	for _, tc := range []struct {
+		name, apiKey, class, want string
+	}{
+		{"anonymous", "", "", ""},
+		{"anonymous claiming b2b", "", "b2b", ""},
+		{"unknown key", "guessed", "b2b", ""},
+		{"key without class", "plain-secret", "registered", ""},
+		{"key with class", "partner-secret", "guest", "b2b"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/baskets/u1", nil)
+		if tc.apiKey != "" {
+			req.Header.Set("X-API-Key", tc.apiKey)
+		}
+		if tc.class != "" {
+			req.Header.Set(userClassHeader, tc.class)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := resp.Header.Get("X-Seen-Class"); got != tc.want {
+			t.Fatalf("%s: basket service saw class %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestActorComesFromAPIKey(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Actor", strings.Join(r.Header.Values(actorHeader), ","))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	app := fiber.New()
+	app.Use(resolveIdentity(testPolicy(t)))
+	app.Put("/products/:id", proxyToService(upstream.URL+"/products/:id", "PUT"))
+
+	for _, tc := range []struct {
+		name, apiKey, actor, want string
+	}{
+		{"anonymous", "", "", ""},
+		{"anonymous claiming an actor", "", "admin", ""},
+		{"unknown key", "guessed", "admin", ""},
+		{"key without class", "plain-secret", "", "plain"},
+		{"key with a claimed actor", "partner-secret", "admin", "partner"},
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/products/1", strings.NewReader(`{}`))
+		if tc.apiKey != "" {
+			req.Header.Set("X-API-Key", tc.apiKey)
+		}
+		if tc.actor != "" {
+			req.Header.Set(actorHeader, tc.actor)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := resp.Header.Get("X-Seen-Actor"); got != tc.want {
+			t.Fatalf("%s: product service saw actor %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+// Anahtarsız bir yazma, sepette kayıtlı sınıfı guest'e düşürmez
+func TestKeylessWriteKeepsStoredBasketClass(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	retentionPolicy := retention.Policy{Guest: 7 * 24 * time.Hour, Registered: 90 * 24 * time.Hour, DefaultClass: basketmodel.UserClassGuest}
+	repo := repository.NewBasketRepository(client, retentionPolicy, clk)
+	h := handler.NewBasketHandler(service.NewBasketService(repo, nil, nil, nil, nil, nil, "USD"), nil)
+	router := gin.New()
+	baskets := router.Group("/baskets", handler.UserClassMiddleware())
+	baskets.GET("/:user_id/expiry", h.GetExpiry)
+	baskets.DELETE("/:user_id/items/:product_id", h.RemoveItem)
+	upstream := httptest.NewServer(router)
+	defer upstream.Close()
+
+	app := fiber.New()
+	app.Use(resolveIdentity(testPolicy(t)))
+	app.Get("/baskets/:user_id/expiry", proxyToService(upstream.URL+"/baskets/:user_id/expiry", "GET"))
+	app.Delete("/baskets/:user_id/items/:product_id", proxyToService(upstream.URL+"/baskets/:user_id/items/:product_id", "DELETE"))
+
+	// Partner anahtarıyla yazılmış b2b sepeti
+	ctx := retention.WithClass(tenant.NewContext(context.Background(), tenant.Default), basketmodel.UserClassB2B)
+	basket := &basketmodel.Basket{UserID: "u1", Items: []basketmodel.BasketItem{
+		{ProductID: 1, Name: "Mug", Price: 10, Quantity: 1},
+		{ProductID: 2, Name: "Plate", Price: 5, Quantity: 1},
+	}, Total: 15}
+	if err := repo.SaveBasket(ctx, basket); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/baskets/u1/items/2", nil)
+	req.Header.Set(userClassHeader, "guest")
+	resp, err := app.Test(req)
+	if err != nil || resp.StatusCode >= 300 {
+		t.Fatalf("keyless DELETE item = %v (%v)", resp, err)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/baskets/u1/expiry", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expiry map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&expiry); err != nil {
+		t.Fatal(err)
+	}
+	if expiry["user_class"] != "b2b" || expiry["ttl_seconds"] != nil {
+		t.Fatalf("expiry after keyless write = %v, want the stored b2b class", expiry)
+	}
+	if ttl := mr.TTL("basket:" + tenant.Default + ":u1"); ttl != 0 {
+		t.Fatalf("basket TTL after keyless write = %v, want none", ttl)
+	}
+}
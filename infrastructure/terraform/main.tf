@@ -211,6 +211,14 @@ resource "aws_security_group" "storage" {
     description     = "Redis from API Services"
   }
 
+  ingress {
+    from_port       = 6379
+    to_port         = 6379
+    protocol        = "tcp"
+    security_groups = [aws_security_group.gateway.id]
+    description     = "Redis from Gateway (rate limits)"
+  }
+
   egress {
     from_port   = 0
     to_port     = 0
@@ -252,6 +260,7 @@ resource "aws_instance" "gateway" {
 
   user_data = base64encode(templatefile("${path.module}/user-data/gateway.sh", {
     api_services_private_ip = aws_instance.api_services.private_ip
+    storage_private_ip      = aws_instance.storage.private_ip
   }))
 
   tags = {
@@ -40,6 +40,8 @@ WorkingDirectory=/opt/cluster-iac
 Environment=PRODUCT_SERVICE_URL=http://${api_services_private_ip}:8080
 Environment=BASKET_SERVICE_URL=http://${api_services_private_ip}:8081
 Environment=GATEWAY_PORT=8082
+Environment=REDIS_ADDR=${storage_private_ip}:6379
+Environment=TRUSTED_PROXIES=127.0.0.1
 ExecStart=/opt/cluster-iac/gateway
 Restart=always
 RestartSec=10
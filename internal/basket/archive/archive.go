@@ -0,0 +1,66 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+)
+
+// Record Redis'ten kaldırılan bir sepetin kalıcı kopyasıdır
+type Record struct {
+	Tenant         string       `json:"tenant"`
+	Basket         model.Basket `json:"basket"`
+	LastActivityAt time.Time    `json:"last_activity_at"`
+	ArchivedAt     time.Time    `json:"archived_at"`
+}
+
+// Store arşivlenen sepetleri kalıcı bir ortama yazar
+type Store interface {
+	Put(ctx context.Context, record Record) error
+}
+
+// FileStore kayıtları günlük NDJSON dosyalarına ekler
+// (dir/2006-01-02.ndjson); her yazma fsync edilir, böylece sepet Redis'ten
+// silinmeden önce diskte olduğu garanti edilir
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Put(ctx context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, record.ArchivedAt.UTC().Format("2006-01-02")+".ndjson")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"cluster-iac/api/proto/product"
+)
+
+// View ürünün hangi tenant için, hangi para birimi ve dilde istendiğidir;
+// aynı ürün farklı görünümlerde farklı fiyat ve metinle döner, başka bir
+// tenant'a ise hiç dönmez. Locale istenen değerdir, fallback sonrası
+// kullanılan dil Product.Locale'dedir.
+type View struct {
+	Tenant   string
+	Currency string
+	Locale   string
+}
+
+type ProductEntry struct {
+	Product   *product.Product
+	FetchedAt time.Time
+	ExpiresAt time.Time
+	view      View
+}
+
+func (e *ProductEntry) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+// ProductCache product servisinden alınan ürün snapshot'larını tutan LRU cache.
+// Fiyat ve metinler görünüme göre değiştiği için kayıtlar ürün ve View
+// başına tutulur; Invalidate ürünün tüm görünümlerini düşürür. Süresi
+// dolan kayıtlar silinmez; product servisine ulaşılamadığında stale olarak
+// kullanılabilmeleri için LRU tarafından çıkarılana kadar saklanır.
+type ProductCache interface {
+	Get(id uint, view View) (*ProductEntry, bool)
+	Set(p *product.Product, view View)
+	Invalidate(id uint)
+	ExpireAll()
+}
+
+type productCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[uint]map[View]*list.Element
+	now      func() time.Time
+}
+
+func NewProductCache(capacity int, ttl time.Duration) ProductCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &productCache{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[uint]map[View]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (c *productCache) Get(id uint, view View) (*ProductEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[id][view]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+
+	entry := *elem.Value.(*ProductEntry)
+	return &entry, true
+}
+
+func (c *productCache) Set(p *product.Product, view View) {
+	if p == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entry := &ProductEntry{Product: p, FetchedAt: now, ExpiresAt: now.Add(c.ttl), view: view}
+
+	id := uint(p.Id)
+	if elem, ok := c.items[id][view]; ok {
+		elem.Value = entry
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	if c.items[id] == nil {
+		c.items[id] = make(map[View]*list.Element, 1)
+	}
+	c.items[id][view] = c.ll.PushFront(entry)
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+
+		evicted := oldest.Value.(*ProductEntry)
+		evictedID := uint(evicted.Product.Id)
+		delete(c.items[evictedID], evicted.view)
+		if len(c.items[evictedID]) == 0 {
+			delete(c.items, evictedID)
+		}
+	}
+}
+
+func (c *productCache) Invalidate(id uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, elem := range c.items[id] {
+		c.ll.Remove(elem)
+	}
+	delete(c.items, id)
+}
+
+// ExpireAll kayıtları silmeden süresini doldurur; bir sonraki okuma product
+// servisine gider ama servis erişilemezse stale kayıt hâlâ kullanılabilir.
+func (c *productCache) ExpireAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, byView := range c.items {
+		for _, elem := range byView {
+			elem.Value.(*ProductEntry).ExpiresAt = now
+		}
+	}
+}
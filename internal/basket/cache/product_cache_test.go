@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"cluster-iac/api/proto/product"
+)
+
+func TestProductCacheViewsDoNotCollideAcrossTenants(t *testing.T) {
+	c := NewProductCache(10, time.Minute)
+	viewA := View{Tenant: "a", Currency: "USD", Locale: "en"}
+	viewB := View{Tenant: "b", Currency: "USD", Locale: "en"}
+
+	// İki tenant'ta aynı id'li farklı ürünler olabilir
+	c.Set(&product.Product{Id: 1, Name: "A mug"}, viewA)
+	if _, ok := c.Get(1, viewB); ok {
+		t.Fatal("tenant b got tenant a's cached product")
+	}
+
+	c.Set(&product.Product{Id: 1, Name: "B mug"}, viewB)
+	for view, want := range map[View]string{viewA: "A mug", viewB: "B mug"} {
+		entry, ok := c.Get(1, view)
+		if !ok || entry.Product.Name != want {
+			t.Fatalf("Get(1, %+v) = %+v, %v; want %s", view, entry, ok, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/api/proto/product"
+)
+
+const (
+	minWatchBackoff = time.Second
+	maxWatchBackoff = 30 * time.Second
+)
+
+// WatchProductChanges context'teki tenant için product servisinin
+// WatchProducts stream'ine abone olur ve değişen ürünleri cache'ten düşürür.
+// Stream koptuğunda arada kaçırılan event'ler olabileceği için tüm
+// kayıtların süresi doldurulur ve yeniden bağlanılır.
+func WatchProductChanges(ctx context.Context, client product.ProductServiceClient, productCache ProductCache) {
+	backoff := minWatchBackoff
+
+	for {
+		stream, err := client.WatchProducts(ctx, &product.WatchProductsRequest{})
+		if err == nil {
+			for {
+				evt, recvErr := stream.Recv()
+				if recvErr != nil {
+					err = recvErr
+					break
+				}
+				backoff = minWatchBackoff
+				productCache.Invalidate(uint(evt.ProductId))
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		productCache.ExpireAll()
+		slog.WarnContext(ctx, "product watch stream interrupted", "retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxWatchBackoff {
+			backoff = maxWatchBackoff
+		}
+	}
+}
@@ -2,16 +2,49 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	RedisAddr     string
-	RedisPassword string
-	RedisDB       string
-	ServerPort    string
-	ProductGRPC   string
+	RedisAddr        string
+	RedisPassword    string
+	RedisDB          string
+	ServerPort       string
+	ProductGRPC      string
+	ProductCacheSize int
+	ProductCacheTTL  time.Duration
+	// Idempotency-Key ile saklanan yanıtların ve işlem kilidinin süresi
+	IdempotencyTTL     time.Duration
+	IdempotencyLockTTL time.Duration
+	// Sepet saklama süreleri kullanıcı sınıfına göre; 0 süresiz demektir
+	RetentionGuest      time.Duration
+	RetentionRegistered time.Duration
+	RetentionB2B        time.Duration
+	DefaultUserClass    string
+	// Terk edilmiş sepet taraması: AbandonAfter kadar güncellenmeyen sepet için
+	// event üretilir, süresinin dolmasına ArchiveLead kalınca sepet ArchiveDir'e taşınır
+	AbandonAfter     time.Duration
+	ArchiveLead      time.Duration
+	SweepInterval    time.Duration
+	ArchiveDir       string
+	EventsStream     string
+	EventsWebhookURL string
+	// Boşsa gömülü varsayılan vergi tablosu kullanılır
+	TaxRatesFile string
+	// Boşsa gömülü varsayılan kargo yöntemleri kullanılır
+	ShippingMethodsFile string
+	// Kur kaynağı product servisiyle aynı olmalıdır; DefaultCurrency para
+	// birimi istenmeden oluşturulan sepetler içindir
+	ExchangeRatesSource          string
+	ExchangeRatesRefreshInterval time.Duration
+	DefaultCurrency              string
+	// "default" tenant'ına ek olarak sunulan tenant'lar; product servisiyle aynı olmalıdır
+	Tenants string
+	// Başlangıç log seviyesi; çalışırken /admin/log-level ile değiştirilebilir
+	LogLevel string
 }
 
 func LoadConfig() (*Config, error) {
@@ -19,10 +52,58 @@ func LoadConfig() (*Config, error) {
 	_ = godotenv.Load("config.env")
 
 	return &Config{
-		RedisAddr:     os.Getenv("REDIS_ADDR"),
-		RedisPassword: os.Getenv("REDIS_PASSWORD"),
-		RedisDB:       os.Getenv("REDIS_DB"),
-		ServerPort:    os.Getenv("BASKET_SERVER_PORT"),
-		ProductGRPC:   os.Getenv("PRODUCT_GRPC_ADDR"),
+		RedisAddr:        os.Getenv("REDIS_ADDR"),
+		RedisPassword:    os.Getenv("REDIS_PASSWORD"),
+		RedisDB:          os.Getenv("REDIS_DB"),
+		ServerPort:       os.Getenv("BASKET_SERVER_PORT"),
+		ProductGRPC:      os.Getenv("PRODUCT_GRPC_ADDR"),
+		ProductCacheSize: getEnvInt("PRODUCT_CACHE_SIZE", 1000),
+		ProductCacheTTL:  getEnvDuration("PRODUCT_CACHE_TTL", 30*time.Second),
+
+		IdempotencyTTL:     getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLockTTL: getEnvDuration("IDEMPOTENCY_LOCK_TTL", time.Minute),
+
+		RetentionGuest:      getEnvDuration("BASKET_RETENTION_GUEST", 7*24*time.Hour),
+		RetentionRegistered: getEnvDuration("BASKET_RETENTION_REGISTERED", 90*24*time.Hour),
+		RetentionB2B:        getEnvDuration("BASKET_RETENTION_B2B", 0),
+		DefaultUserClass:    getEnv("BASKET_DEFAULT_USER_CLASS", "guest"),
+
+		AbandonAfter:     getEnvDuration("BASKET_ABANDON_AFTER", 2*time.Hour),
+		ArchiveLead:      getEnvDuration("BASKET_ARCHIVE_LEAD", time.Hour),
+		SweepInterval:    getEnvDuration("BASKET_SWEEP_INTERVAL", 5*time.Minute),
+		ArchiveDir:       getEnv("BASKET_ARCHIVE_DIR", "data/basket-archive"),
+		EventsStream:     getEnv("BASKET_EVENTS_STREAM", "events:basket"),
+		EventsWebhookURL: os.Getenv("BASKET_EVENTS_WEBHOOK_URL"),
+
+		TaxRatesFile:        os.Getenv("TAX_RATES_FILE"),
+		ShippingMethodsFile: os.Getenv("SHIPPING_METHODS_FILE"),
+
+		ExchangeRatesSource:          os.Getenv("EXCHANGE_RATES_SOURCE"),
+		ExchangeRatesRefreshInterval: getEnvDuration("EXCHANGE_RATES_REFRESH_INTERVAL", time.Hour),
+		DefaultCurrency:              getEnv("BASKET_DEFAULT_CURRENCY", "TRY"),
+
+		Tenants:  os.Getenv("TENANTS"),
+		LogLevel: os.Getenv("LOG_LEVEL"),
 	}, nil
 }
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
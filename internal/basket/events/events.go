@@ -0,0 +1,133 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+	"github.com/go-redis/redis/v8"
+)
+
+type EventType string
+
+const (
+	// Sepet yapılandırılan süre boyunca güncellenmediğinde
+	BasketAbandoned EventType = "basket.abandoned"
+	// Sepet Redis'ten silinmeden önce kalıcı arşive taşındığında
+	BasketArchived EventType = "basket.archived"
+)
+
+type Event struct {
+	Type           EventType          `json:"type"`
+	Tenant         string             `json:"tenant"`
+	UserID         string             `json:"user_id"`
+	Items          []model.BasketItem `json:"items"`
+	Total          float64            `json:"total"`
+	LastActivityAt time.Time          `json:"last_activity_at"`
+	OccurredAt     time.Time          `json:"occurred_at"`
+}
+
+// Publisher sepet event'lerini dış bir kanala iletir
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	slog.InfoContext(ctx, "basket event",
+		"type", event.Type, "user_id", event.UserID, "items", len(event.Items),
+		"total", event.Total, "last_activity_at", event.LastActivityAt.Format(time.RFC3339))
+	return nil
+}
+
+// StreamPublisher event'leri bir Redis stream'ine yazar; tüketiciler consumer
+// group ile kendi hızlarında okuyabilir
+type StreamPublisher struct {
+	client redis.Cmdable
+	stream string
+	maxLen int64
+}
+
+func NewStreamPublisher(client redis.Cmdable, stream string, maxLen int64) *StreamPublisher {
+	return &StreamPublisher{client: client, stream: stream, maxLen: maxLen}
+}
+
+func (p *StreamPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":    string(event.Type),
+			"tenant":  event.Tenant,
+			"user_id": event.UserID,
+			"payload": body,
+		},
+	}).Err()
+}
+
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiPublisher event'i tüm publisher'lara gönderir; biri başarısız olsa da
+// diğerleri denenir
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -1,32 +1,79 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"cluster-iac/internal/basket/retention"
 	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/basket/tax"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/problem"
 
 	"github.com/gin-gonic/gin"
 )
 
 type BasketHandler struct {
 	basketService service.BasketService
+	currencies    *currency.Converter
 }
 
-func NewBasketHandler(basketService service.BasketService) *BasketHandler {
-	return &BasketHandler{basketService: basketService}
+func NewBasketHandler(basketService service.BasketService, currencies *currency.Converter) *BasketHandler {
+	return &BasketHandler{basketService: basketService, currencies: currencies}
+}
+
+// requestedCurrency currency parametresini ya da Accept-Currency başlığını
+// çözer; ikisi de yoksa sepetin kendi para birimi kullanılır
+func (h *BasketHandler) requestedCurrency(c *gin.Context) (string, bool) {
+	c.Writer.Header().Add("Vary", currency.HeaderName)
+
+	code, err := h.currencies.Negotiate(c.Query("currency"), c.GetHeader(currency.HeaderName))
+	if err != nil {
+		writeProblem(c, err)
+		return "", false
+	}
+	return code, true
+}
+
+// requestedLocale locale parametresini ya da Accept-Language başlığındaki
+// dilleri tercih sırasıyla product servisine iletilecek biçime getirir;
+// desteklenen diller ve fallback'ler product servisinde çözülür
+func requestedLocale(c *gin.Context) (string, bool) {
+	if query := c.Query("locale"); query != "" {
+		tag := locale.Normalize(query)
+		if !locale.Valid(tag) {
+			writeProblem(c, problem.New(problem.Invalid, fmt.Sprintf("locale %q is not a valid language tag", query)))
+			return "", false
+		}
+		return tag, true
+	}
+	return strings.Join(locale.ParseAcceptLanguage(c.GetHeader(locale.HeaderName)), ","), true
 }
 
 func (h *BasketHandler) GetBasket(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
 		return
 	}
 
-	basket, err := h.basketService.GetBasket(c.Request.Context(), userID)
+	code, ok := h.requestedCurrency(c)
+	if !ok {
+		return
+	}
+
+	// country (ve opsiyonel region) verilirse yanıt vergi bilgisini içerir
+	opts := service.GetBasketOptions{Currency: code}
+	if country := c.Query("country"); country != "" {
+		opts.Destination = &tax.Destination{Country: country, Region: c.Query("region")}
+	}
+
+	basket, err := h.basketService.GetBasket(c.Request.Context(), userID, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
@@ -36,23 +83,34 @@ func (h *BasketHandler) GetBasket(c *gin.Context) {
 func (h *BasketHandler) AddItem(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
 		return
 	}
 
 	var req struct {
 		ProductID uint `json:"product_id" binding:"required"`
+		SKUID     uint `json:"sku_id"`
 		Quantity  int  `json:"quantity" binding:"required,min=1"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
 		return
 	}
 
-	err := h.basketService.AddItem(c.Request.Context(), userID, req.ProductID, req.Quantity)
+	code, ok := h.requestedCurrency(c)
+	if !ok {
+		return
+	}
+	tag, ok := requestedLocale(c)
+	if !ok {
+		return
+	}
+
+	opts := service.AddItemOptions{Currency: code, Locale: tag}
+	err := h.basketService.AddItem(c.Request.Context(), userID, req.ProductID, req.SKUID, req.Quantity, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
@@ -62,20 +120,25 @@ func (h *BasketHandler) AddItem(c *gin.Context) {
 func (h *BasketHandler) RemoveItem(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
 		return
 	}
 
 	productIDStr := c.Param("product_id")
 	productID, err := strconv.ParseUint(productIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		writeProblem(c, problem.New(problem.Invalid, "Invalid product ID"))
 		return
 	}
 
-	err = h.basketService.RemoveItem(c.Request.Context(), userID, uint(productID))
+	skuID, ok := parseSKUID(c)
+	if !ok {
+		return
+	}
+
+	err = h.basketService.RemoveItem(c.Request.Context(), userID, uint(productID), skuID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
@@ -85,14 +148,19 @@ func (h *BasketHandler) RemoveItem(c *gin.Context) {
 func (h *BasketHandler) UpdateItemQuantity(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
 		return
 	}
 
 	productIDStr := c.Param("product_id")
 	productID, err := strconv.ParseUint(productIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		writeProblem(c, problem.New(problem.Invalid, "Invalid product ID"))
+		return
+	}
+
+	skuID, ok := parseSKUID(c)
+	if !ok {
 		return
 	}
 
@@ -101,13 +169,13 @@ func (h *BasketHandler) UpdateItemQuantity(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
 		return
 	}
 
-	err = h.basketService.UpdateItemQuantity(c.Request.Context(), userID, uint(productID), req.Quantity)
+	err = h.basketService.UpdateItemQuantity(c.Request.Context(), userID, uint(productID), skuID, req.Quantity)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
@@ -117,15 +185,115 @@ func (h *BasketHandler) UpdateItemQuantity(c *gin.Context) {
 func (h *BasketHandler) ClearBasket(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
 		return
 	}
 
 	err := h.basketService.ClearBasket(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Basket cleared successfully"})
 }
+
+func (h *BasketHandler) GetExpiry(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
+		return
+	}
+
+	expiry, err := h.basketService.GetExpiry(c.Request.Context(), userID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expiry)
+}
+
+func (h *BasketHandler) GetShippingOptions(c *gin.Context) {
+	country := c.Query("country")
+	if country == "" {
+		writeProblem(c, problem.New(problem.Invalid, "country query parameter is required"))
+		return
+	}
+
+	options, err := h.basketService.GetShippingOptions(c.Request.Context(), c.Param("user_id"), country)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *BasketHandler) SelectShipping(c *gin.Context) {
+	var req struct {
+		MethodID string `json:"method_id" binding:"required"`
+		Country  string `json:"country" binding:"required,len=2"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	basket, err := h.basketService.SelectShipping(c.Request.Context(), c.Param("user_id"), req.MethodID, req.Country)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, basket)
+}
+
+func (h *BasketHandler) ClearShipping(c *gin.Context) {
+	if err := h.basketService.ClearShipping(c.Request.Context(), c.Param("user_id")); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shipping method removed from basket"})
+}
+
+// UserClassMiddleware X-User-Class header'ını (guest, registered, b2b) request
+// context'ine ekler; header yoksa sepette kayıtlı ya da varsayılan sınıf kullanılır.
+// Header'ı yalnızca gateway koyar (istemcininkini silip sınıfı tanımlı API
+// anahtarından), bu yüzden servis doğrudan dışarı açılmamalıdır.
+func UserClassMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader("X-User-Class")
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		class, err := retention.ParseClass(value)
+		if err != nil {
+			writeProblem(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(retention.WithClass(c.Request.Context(), class))
+		c.Next()
+	}
+}
+
+// parseSKUID varyantlı satırları hedeflemek için opsiyonel sku_id query parametresini okur
+func parseSKUID(c *gin.Context) (uint, bool) {
+	skuIDStr := c.Query("sku_id")
+	if skuIDStr == "" {
+		return 0, true
+	}
+
+	skuID, err := strconv.ParseUint(skuIDStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid SKU ID"))
+		return 0, false
+	}
+	return uint(skuID), true
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/tenant"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type expiryFixture struct {
+	redis  *miniredis.Miniredis
+	clock  *clock.Fake
+	repo   repository.BasketRepository
+	router *gin.Engine
+}
+
+func newExpiryFixture(t *testing.T) *expiryFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	policy := retention.Policy{Guest: 48 * time.Hour, Registered: 30 * 24 * time.Hour, DefaultClass: model.UserClassGuest}
+	repo := repository.NewBasketRepository(client, policy, clk)
+	h := NewBasketHandler(service.NewBasketService(repo, nil, nil, nil, nil, nil, "USD"), nil)
+
+	router := gin.New()
+	baskets := router.Group("/baskets", UserClassMiddleware())
+	baskets.GET("/:user_id", h.GetBasket)
+	baskets.GET("/:user_id/expiry", h.GetExpiry)
+	return &expiryFixture{redis: mr, clock: clk, repo: repo, router: router}
+}
+
+func (f *expiryFixture) save(t *testing.T, userID string, class model.UserClass) {
+	t.Helper()
+	ctx := retention.WithClass(tenant.NewContext(context.Background(), tenant.Default), class)
+	basket := &model.Basket{UserID: userID, Items: []model.BasketItem{{ProductID: 1, Name: "Mug", Price: 10, Quantity: 1}}, Total: 10}
+	if err := f.repo.SaveBasket(ctx, basket); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f *expiryFixture) getExpiry(t *testing.T, userID string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/baskets/"+userID+"/expiry", nil))
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET expiry body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func TestGetExpiryResponse(t *testing.T) {
+	f := newExpiryFixture(t)
+	f.save(t, "guest-user", model.UserClassGuest)
+	f.save(t, "b2b-user", model.UserClassB2B)
+
+	f.clock.Advance(12 * time.Hour)
+	f.redis.FastForward(12 * time.Hour)
+
+	code, body := f.getExpiry(t, "guest-user")
+	if code != http.StatusOK {
+		t.Fatalf("GET expiry = %d %v", code, body)
+	}
+	want := map[string]any{
+		"user_id":           "guest-user",
+		"user_class":        "guest",
+		"retention_seconds": float64(48 * 3600),
+		"expires_at":        "2026-03-03T12:00:00Z",
+		"ttl_seconds":       float64(36 * 3600),
+	}
+	for field, value := range want {
+		if body[field] != value {
+			t.Fatalf("%s = %v, want %v (body %v)", field, body[field], value, body)
+		}
+	}
+	// Süre bilgisini okumak sepetin süresini yenilemez
+	if ttl := f.redis.TTL("basket:" + tenant.Default + ":guest-user"); ttl != 36*time.Hour {
+		t.Fatalf("TTL after GET expiry = %v, want 36h", ttl)
+	}
+
+	code, body = f.getExpiry(t, "b2b-user")
+	if code != http.StatusOK || body["user_class"] != "b2b" || body["retention_seconds"] != float64(0) {
+		t.Fatalf("GET b2b expiry = %d %v", code, body)
+	}
+	if body["expires_at"] != nil || body["ttl_seconds"] != nil {
+		t.Fatalf("indefinite basket has an expiry: %v", body)
+	}
+
+	// Süresi dolan sepet bulunamaz
+	f.clock.Advance(36 * time.Hour)
+	f.redis.FastForward(36 * time.Hour)
+	if code, body := f.getExpiry(t, "guest-user"); code != http.StatusNotFound {
+		t.Fatalf("GET expiry of expired basket = %d %v", code, body)
+	}
+}
+
+func TestUserClassMiddlewareRejectsUnknownClass(t *testing.T) {
+	f := newExpiryFixture(t)
+	req := httptest.NewRequest(http.MethodGet, "/baskets/u1", nil)
+	req.Header.Set("X-User-Class", "platinum")
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown class = %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"cluster-iac/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeProblem hatayı application/problem+json olarak yazar; product
+// servisinden gelen gRPC hataları servis katmanında tipli hatalara çevrilir
+func writeProblem(c *gin.Context, err error) {
+	problem.Write(c.Writer, c.Request, err)
+	c.Abort()
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistHandler struct {
+	wishlistService service.WishlistService
+}
+
+func NewWishlistHandler(wishlistService service.WishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService: wishlistService}
+}
+
+func (h *WishlistHandler) GetLists(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
+		return
+	}
+
+	lists, err := h.wishlistService.GetLists(c.Request.Context(), userID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lists": lists})
+}
+
+func (h *WishlistHandler) CreateList(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		writeProblem(c, problem.New(problem.Invalid, "User ID is required"))
+		return
+	}
+
+	var req struct {
+		Name string `json:"name" binding:"required,max=100"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	list, err := h.wishlistService.CreateList(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, list)
+}
+
+func (h *WishlistHandler) GetList(c *gin.Context) {
+	list, err := h.wishlistService.GetList(c.Request.Context(), c.Param("user_id"), c.Param("list_id"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *WishlistHandler) DeleteList(c *gin.Context) {
+	err := h.wishlistService.DeleteList(c.Request.Context(), c.Param("user_id"), c.Param("list_id"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List deleted successfully"})
+}
+
+func (h *WishlistHandler) AddItem(c *gin.Context) {
+	var req struct {
+		ProductID uint `json:"product_id" binding:"required"`
+		SKUID     uint `json:"sku_id"`
+		Quantity  int  `json:"quantity" binding:"omitempty,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	list, err := h.wishlistService.AddItem(c.Request.Context(), c.Param("user_id"), c.Param("list_id"), req.ProductID, req.SKUID, req.Quantity)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *WishlistHandler) RemoveItem(c *gin.Context) {
+	productID, skuID, ok := parseLine(c)
+	if !ok {
+		return
+	}
+
+	err := h.wishlistService.RemoveItem(c.Request.Context(), c.Param("user_id"), c.Param("list_id"), productID, skuID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed from list successfully"})
+}
+
+// MoveToBasket liste satırını sepete taşır ve güncel sepeti döndürür
+func (h *WishlistHandler) MoveToBasket(c *gin.Context) {
+	productID, skuID, ok := parseLine(c)
+	if !ok {
+		return
+	}
+	tag, ok := requestedLocale(c)
+	if !ok {
+		return
+	}
+
+	basket, err := h.wishlistService.MoveToBasket(c.Request.Context(), c.Param("user_id"), c.Param("list_id"), productID, skuID, tag)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, basket)
+}
+
+// SaveForLater sepet satırını listeye taşır; list_id verilmezse "saved-for-later"
+func (h *WishlistHandler) SaveForLater(c *gin.Context) {
+	productID, skuID, ok := parseLine(c)
+	if !ok {
+		return
+	}
+
+	listID := c.DefaultQuery("list_id", model.SavedForLaterListID)
+	list, err := h.wishlistService.MoveFromBasket(c.Request.Context(), c.Param("user_id"), listID, productID, skuID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *WishlistHandler) ShareList(c *gin.Context) {
+	list, err := h.wishlistService.ShareList(c.Request.Context(), c.Param("user_id"), c.Param("list_id"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list_id":     list.ID,
+		"share_token": list.ShareToken,
+		"share_path":  "/shared-lists/" + list.ShareToken,
+	})
+}
+
+func (h *WishlistHandler) UnshareList(c *gin.Context) {
+	err := h.wishlistService.UnshareList(c.Request.Context(), c.Param("user_id"), c.Param("list_id"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List is no longer shared"})
+}
+
+// GetSharedList token ile salt okunur liste görünümü döndürür
+func (h *WishlistHandler) GetSharedList(c *gin.Context) {
+	list, err := h.wishlistService.GetSharedList(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// parseLine path'teki product_id ve opsiyonel sku_id query parametresini okur
+func parseLine(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid product ID"))
+		return 0, 0, false
+	}
+
+	skuID, ok := parseSKUID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint(productID), skuID, true
+}
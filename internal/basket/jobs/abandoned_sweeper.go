@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/service"
+	"cluster-iac/internal/tenant"
+)
+
+// RunAbandonedBasketSweeper her interval'de tenant'ların terk edilmiş
+// sepetlerini sırayla tarar; birden fazla replika varsa taramayı kilidi alan
+// tek replika yapar.
+func RunAbandonedBasketSweeper(ctx context.Context, repo repository.BasketRepository, sweeper service.AbandonedBasketService, tenants []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Kilit bir sonraki tick'ten önce düşer; aynı tick'i yalnızca bir replika işler
+			locked, err := repo.TryLock(ctx, "abandoned-basket-sweep", interval/2)
+			if err != nil {
+				slog.ErrorContext(ctx, "abandoned basket sweep lock failed", "error", err)
+				continue
+			}
+			if !locked {
+				continue
+			}
+
+			for _, id := range tenants {
+				tenantCtx := tenant.NewContext(ctx, id)
+				result, err := sweeper.Sweep(tenantCtx)
+				if err != nil {
+					slog.ErrorContext(tenantCtx, "abandoned basket sweep failed", "error", err)
+					continue
+				}
+				if result.Abandoned > 0 || result.Archived > 0 {
+					slog.InfoContext(tenantCtx, "abandoned basket sweep finished", "abandoned", result.Abandoned, "archived", result.Archived)
+				}
+			}
+		}
+	}
+}
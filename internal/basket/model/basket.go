@@ -5,18 +5,101 @@ import (
 )
 
 type BasketItem struct {
-	ProductID   uint    `json:"product_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	ImageURL    string  `json:"image_url"`
-	Quantity    int     `json:"quantity"`
+	ProductID   uint              `json:"product_id"`
+	SKUID       uint              `json:"sku_id,omitempty"`
+	SKU         string            `json:"sku,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Locale      string            `json:"locale,omitempty"` // Name ve Description'ın snapshot alındığı dil
+	Price       float64           `json:"price"`
+	ImageURL    string            `json:"image_url"`
+	Quantity    int               `json:"quantity"`
+	TaxClass    string            `json:"tax_class,omitempty"`
+	// Birim ağırlık (kg) ve boyutlar (cm); kargo ücreti hesabında kullanılır
+	WeightKg   float64   `json:"weight_kg,omitempty"`
+	LengthCm   float64   `json:"length_cm,omitempty"`
+	WidthCm    float64   `json:"width_cm,omitempty"`
+	HeightCm   float64   `json:"height_cm,omitempty"`
+	SnapshotAt time.Time `json:"snapshot_at"`
+	// Stale, product servisine ulaşılamadığı için ürün bilgisinin süresi dolmuş
+	// bir cache kaydından alındığını belirtir
+	Stale bool `json:"stale"`
+	// Unavailable, ürünün (ya da SKU'nun) product servisinde silindiğini veya
+	// artık var olmadığını belirtir; bu item'lar toplama dahil edilmez
+	Unavailable bool `json:"unavailable"`
+	// Tax yalnızca sepet bir teslimat bölgesiyle istendiğinde hesaplanır, saklanmaz
+	Tax *ItemTax `json:"tax,omitempty"`
 }
 
+type ItemTax struct {
+	Rate  float64 `json:"rate"`
+	Net   float64 `json:"net"`
+	Tax   float64 `json:"tax"`
+	Gross float64 `json:"gross"`
+}
+
+// BasketTax sepetin seçilen bölgeye göre vergi özetidir; Total katalog
+// fiyatlarının toplamı olarak kalır
+type BasketTax struct {
+	Country          string  `json:"country"`
+	Region           string  `json:"region,omitempty"`
+	PricesIncludeTax bool    `json:"prices_include_tax"`
+	Net              float64 `json:"net"`
+	Tax              float64 `json:"tax"`
+	Gross            float64 `json:"gross"`
+}
+
+// SameLine iki item'ın sepette aynı satırı (ürün + SKU) temsil edip etmediğini döndürür
+func (i BasketItem) SameLine(productID, skuID uint) bool {
+	return i.ProductID == productID && i.SKUID == skuID
+}
+
+// UserClass sepetin saklama süresini belirleyen kullanıcı sınıfıdır
+type UserClass string
+
+const (
+	UserClassGuest      UserClass = "guest"
+	UserClassRegistered UserClass = "registered"
+	UserClassB2B        UserClass = "b2b"
+)
+
 type Basket struct {
-	UserID    string       `json:"user_id"`
-	Items     []BasketItem `json:"items"`
-	Total     float64      `json:"total"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	UserID    string    `json:"user_id"`
+	UserClass UserClass `json:"user_class,omitempty"`
+	// Currency satır fiyatlarının para birimidir; sepette ürün varken değişmez
+	Currency string       `json:"currency,omitempty"`
+	Items    []BasketItem `json:"items"`
+	Total    float64      `json:"total"`
+	Tax      *BasketTax   `json:"tax,omitempty"`
+	Shipping *Shipping    `json:"shipping,omitempty"`
+	// GrandTotal yalnızca sepet okunurken hesaplanır: ürünler (vergi
+	// hesaplandıysa vergi dahil) ve seçilen kargo ücreti
+	GrandTotal *float64  `json:"grand_total,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Shipping sepette seçilen kargo yöntemidir; ücret sepet her okunduğunda
+// güncel içerikle yeniden hesaplanır
+type Shipping struct {
+	MethodID string  `json:"method_id"`
+	Country  string  `json:"country"`
+	Name     string  `json:"name"`
+	Carrier  string  `json:"carrier"`
+	Cost     float64 `json:"cost"`
+	Free     bool    `json:"free"`
+	// Unavailable sepet değiştiği için yöntemin artık uygun olmadığını
+	// belirtir; ücret toplama eklenmez ve yeni bir yöntem seçilmelidir
+	Unavailable bool `json:"unavailable"`
+}
+
+// BasketExpiry sepetin saklama politikasını ve ne zaman silineceğini açıklar;
+// sepet süresiz tutuluyorsa ExpiresAt ve TTLSeconds boştur
+type BasketExpiry struct {
+	UserID           string     `json:"user_id"`
+	UserClass        UserClass  `json:"user_class"`
+	RetentionSeconds int64      `json:"retention_seconds"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	TTLSeconds       *int64     `json:"ttl_seconds"`
 }
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// SavedForLaterListID her kullanıcının varsayılan listesidir; oluşturulmadan
+// da kullanılabilir
+const SavedForLaterListID = "saved-for-later"
+
+type WishlistItem struct {
+	ProductID  uint              `json:"product_id"`
+	SKUID      uint              `json:"sku_id,omitempty"`
+	SKU        string            `json:"sku,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Name       string            `json:"name"`
+	ImageURL   string            `json:"image_url"`
+	Quantity   int               `json:"quantity"`
+	// Listeye eklendiği andaki fiyat ve para birimi; fiyat düşüşü aynı para
+	// birimindeki güncel fiyata göre hesaplanır
+	PriceWhenAdded float64   `json:"price_when_added"`
+	Currency       string    `json:"currency,omitempty"`
+	AddedAt        time.Time `json:"added_at"`
+
+	// Aşağıdaki alanlar saklanmaz, liste okunurken product servisinden
+	// hesaplanır; servise ulaşılamazsa boş kalır
+	CurrentPrice *float64 `json:"current_price,omitempty"`
+	Available    *bool    `json:"available,omitempty"`
+	PriceDropped *bool    `json:"price_dropped,omitempty"`
+}
+
+// SameLine iki item'ın listede aynı satırı (ürün + SKU) temsil edip etmediğini döndürür
+func (i WishlistItem) SameLine(productID, skuID uint) bool {
+	return i.ProductID == productID && i.SKUID == skuID
+}
+
+// Wishlist kullanıcının adlandırılmış listesidir. ShareToken verilmişse liste
+// salt okunur olarak /shared-lists/:token üzerinden görülebilir.
+type Wishlist struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	Name       string         `json:"name"`
+	Items      []WishlistItem `json:"items"`
+	ShareToken string         `json:"share_token,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
@@ -3,41 +3,139 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/tenant"
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrCurrencyLocked dolu bir sepete başka para biriminde fiyatlanmış bir
+// ürün eklenmek istendiğinde döner
+var ErrCurrencyLocked = problem.New(problem.Conflict, "basket is priced in another currency")
+
+// Sepetler ve index'leri tenant başına ayrılır; tenant context'ten okunur:
+//
+//	basket:{tenant}:{user_id}    sepet JSON'u
+//	baskets:{tenant}:activity    bildirilmemiş son aktivite zamanına (unix
+//	                             saniye) göre sıralı sepetler; terk edildi
+//	                             event'i gönderilen sepet, yeniden
+//	                             güncellenene kadar çıkarılır
+//	baskets:{tenant}:expiry      süreli sepetlerin silineceği zamana (unix
+//	                             saniye) göre sıralı index
+//	baskets:{tenant}:archived:{user_id}:{updated_at}
+//	                             sepetin bu sürümünün arşive yazıldığını
+//	                             gösterir; silme başarısız olursa tekrar
+//	                             yazılmasını önler
+func basketKey(ctx context.Context, userID string) string {
+	return fmt.Sprintf("basket:%s:%s", tenant.ID(ctx), userID)
+}
+
+func activityKey(ctx context.Context) string {
+	return fmt.Sprintf("baskets:%s:activity", tenant.ID(ctx))
+}
+
+func expiryKey(ctx context.Context) string {
+	return fmt.Sprintf("baskets:%s:expiry", tenant.ID(ctx))
+}
+
+func archivedKey(ctx context.Context, basket *model.Basket) string {
+	return fmt.Sprintf("baskets:%s:archived:%s:%d", tenant.ID(ctx), basket.UserID, basket.UpdatedAt.UnixNano())
+}
+
+// archivedMarkerTTL, silme başarısız olan bir sepetin sonraki taramalarda
+// yeniden arşive yazılmasını engellediği süredir
+const archivedMarkerTTL = 7 * 24 * time.Hour
+
+// ArchivedBasket ArchiveBasket'in Redis'ten kaldırdığı sepettir
+type ArchivedBasket struct {
+	// Basket Redis sepeti zaten düşürdüyse nil'dir
+	Basket *model.Basket
+	// Abandoned sepet için henüz terk edildi event'i gönderilmediğini belirtir
+	Abandoned bool
+}
+
+// claimAbandoned sepeti aktivite index'inden yalnızca skor değişmediyse
+// çıkarır; arada gelen bir güncelleme yeni skorla kalır
+var claimAbandoned = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if score and tonumber(score) == tonumber(ARGV[2]) then
+	return redis.call("ZREM", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
 type BasketRepository interface {
+	// GetBasket sepet varsa saklama süresini yeniler; yoksa kaydedilmemiş boş bir sepet döner
 	GetBasket(ctx context.Context, userID string) (*model.Basket, error)
 	SaveBasket(ctx context.Context, basket *model.Basket) error
 	DeleteBasket(ctx context.Context, userID string) error
-	AddItem(ctx context.Context, userID string, item *model.BasketItem) error
-	RemoveItem(ctx context.Context, userID string, productID uint) error
-	UpdateItemQuantity(ctx context.Context, userID string, productID uint, quantity int) error
+	// AddItem item'ı currency cinsinden fiyatlanmış olarak ekler; boş sepet
+	// bu para birimine kilitlenir
+	AddItem(ctx context.Context, userID, currency string, item *model.BasketItem) error
+	RemoveItem(ctx context.Context, userID string, productID, skuID uint) error
+	UpdateItemQuantity(ctx context.Context, userID string, productID, skuID uint, quantity int) error
+	// SetShipping sepetin kargo seçimini kaydeder; nil seçimi kaldırır
+	SetShipping(ctx context.Context, userID string, shipping *model.Shipping) error
+	// PeekBasket sepeti saklama süresini yenilemeden okur; sepet yoksa nil
+	PeekBasket(ctx context.Context, userID string) (*model.Basket, error)
+	// GetExpiry sepetin saklama bilgisini süresini yenilemeden döndürür; sepet yoksa nil
+	GetExpiry(ctx context.Context, userID string) (*model.BasketExpiry, error)
+
+	// IdleBaskets son aktivitesi before'dan eski olan ve henüz terk edildi
+	// olarak bildirilmemiş sepetleri en eskiden başlayarak döndürür
+	IdleBaskets(ctx context.Context, before time.Time, offset, limit int64) ([]BasketActivity, error)
+	// MarkAbandoned sepeti bu aktivite için bildirilmiş sayar; sepet arada
+	// güncellendiyse ya da zaten bildirildiyse false döner
+	MarkAbandoned(ctx context.Context, activity BasketActivity) (bool, error)
+	// UnmarkAbandoned bildirim başarısız olduğunda sepeti index'e geri koyar
+	UnmarkAbandoned(ctx context.Context, activity BasketActivity) error
+	// ExpiringBaskets before'dan önce süresi dolacak sepetleri döndürür
+	ExpiringBaskets(ctx context.Context, before time.Time, offset, limit int64) ([]string, error)
+	// ArchiveBasket sepetin süresi hâlâ before'dan önce doluyorsa store'a verir
+	// ve Redis'ten siler; kaldırılmadıysa nil döner. Sepet arada okunur ya da
+	// güncellenirse silinmez. store sepetin aynı sürümü için bir kez çağrılır,
+	// bu yüzden event'ler dönüşten sonra gönderilmelidir.
+	ArchiveBasket(ctx context.Context, userID string, before time.Time, store func(*model.Basket) error) (*ArchivedBasket, error)
+	// TryLock birden fazla replika varken periyodik işlerin tek kopyada
+	// çalışması için basit bir Redis kilidi alır
+	TryLock(ctx context.Context, name string, ttl time.Duration) (bool, error)
+}
+
+type BasketActivity struct {
+	UserID       string
+	LastActivity time.Time
 }
 
 type basketRepository struct {
 	redisClient *redis.Client
+	policy      retention.Policy
+	clock       clock.Clock
 }
 
-func NewBasketRepository(redisClient *redis.Client) BasketRepository {
-	return &basketRepository{redisClient: redisClient}
+func NewBasketRepository(redisClient *redis.Client, policy retention.Policy, clk clock.Clock) BasketRepository {
+	return &basketRepository{redisClient: redisClient, policy: policy, clock: clk}
 }
 
 func (r *basketRepository) GetBasket(ctx context.Context, userID string) (*model.Basket, error) {
-	key := fmt.Sprintf("basket:%s", userID)
+	key := basketKey(ctx, userID)
 	data, err := r.redisClient.Get(ctx, key).Result()
 	if err == redis.Nil {
 		// Basket bulunamadı, yeni oluştur
+		now := r.clock.Now()
 		return &model.Basket{
 			UserID:    userID,
+			UserClass: r.policy.Resolve(ctx, ""),
 			Items:     []model.BasketItem{},
 			Total:     0,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			CreatedAt: now,
+			UpdatedAt: now,
 		}, nil
 	} else if err != nil {
 		return nil, err
@@ -48,36 +146,279 @@ func (r *basketRepository) GetBasket(ctx context.Context, userID string) (*model
 		return nil, err
 	}
 
+	// Okuma sepetin saklama süresini yeniler; güncel politika uygulanır
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		r.applyRetention(ctx, pipe, basket.UserID, r.policy.TTL(basket.UserClass))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &basket, nil
+}
+
+func (r *basketRepository) PeekBasket(ctx context.Context, userID string) (*model.Basket, error) {
+	data, err := r.redisClient.Get(ctx, basketKey(ctx, userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var basket model.Basket
+	if err := json.Unmarshal(data, &basket); err != nil {
+		return nil, err
+	}
 	return &basket, nil
 }
 
 func (r *basketRepository) SaveBasket(ctx context.Context, basket *model.Basket) error {
-	key := fmt.Sprintf("basket:%s", basket.UserID)
-	basket.UpdatedAt = time.Now()
+	key := basketKey(ctx, basket.UserID)
+	basket.UpdatedAt = r.clock.Now()
+	basket.UserClass = r.policy.Resolve(ctx, basket.UserClass)
 
 	data, err := json.Marshal(basket)
 	if err != nil {
 		return err
 	}
 
-	// 24 saat TTL ile kaydet
-	return r.redisClient.Set(ctx, key, data, 24*time.Hour).Err()
+	// Sepet, aktivite ve süre index'leri birlikte güncellenir; yeni aktivite
+	// sepeti yeniden terk edildi bildirimine aday yapar
+	ttl := r.policy.TTL(basket.UserClass)
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, 0)
+		r.applyRetention(ctx, pipe, basket.UserID, ttl)
+		pipe.ZAdd(ctx, activityKey(ctx), &redis.Z{Score: float64(basket.UpdatedAt.Unix()), Member: basket.UserID})
+		return nil
+	})
+	return err
+}
+
+// applyRetention sepet anahtarının TTL'ini ve süre index'ini ayarlar; sıfır
+// TTL sepeti süresiz yapar
+func (r *basketRepository) applyRetention(ctx context.Context, pipe redis.Pipeliner, userID string, ttl time.Duration) {
+	key := basketKey(ctx, userID)
+	if ttl <= 0 {
+		pipe.Persist(ctx, key)
+		pipe.ZRem(ctx, expiryKey(ctx), userID)
+		return
+	}
+
+	pipe.Expire(ctx, key, ttl)
+	pipe.ZAdd(ctx, expiryKey(ctx), &redis.Z{Score: float64(r.clock.Now().Add(ttl).Unix()), Member: userID})
 }
 
 func (r *basketRepository) DeleteBasket(ctx context.Context, userID string) error {
-	key := fmt.Sprintf("basket:%s", userID)
-	return r.redisClient.Del(ctx, key).Err()
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, basketKey(ctx, userID))
+		pipe.ZRem(ctx, activityKey(ctx), userID)
+		pipe.ZRem(ctx, expiryKey(ctx), userID)
+		return nil
+	})
+	return err
 }
 
-func (r *basketRepository) AddItem(ctx context.Context, userID string, item *model.BasketItem) error {
+func (r *basketRepository) GetExpiry(ctx context.Context, userID string) (*model.BasketExpiry, error) {
+	var getCmd *redis.StringCmd
+	var scoreCmd *redis.FloatCmd
+	_, err := r.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		getCmd = pipe.Get(ctx, basketKey(ctx, userID))
+		scoreCmd = pipe.ZScore(ctx, expiryKey(ctx), userID)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if getCmd.Err() == redis.Nil {
+		return nil, nil
+	}
+
+	var basket model.Basket
+	if err := json.Unmarshal([]byte(getCmd.Val()), &basket); err != nil {
+		return nil, err
+	}
+
+	class := basket.UserClass
+	if class == "" {
+		class = r.policy.DefaultClass
+	}
+	expiry := &model.BasketExpiry{
+		UserID:           userID,
+		UserClass:        class,
+		RetentionSeconds: int64(r.policy.TTL(class) / time.Second),
+	}
+
+	// Süre index'i saatle aynı zaman tabanını kullanır; süresiz sepetler index'te yoktur
+	if scoreCmd.Err() == nil {
+		expiresAt := time.Unix(int64(scoreCmd.Val()), 0)
+		seconds := int64(expiresAt.Sub(r.clock.Now()) / time.Second)
+		if seconds < 0 {
+			seconds = 0
+		}
+		expiry.ExpiresAt = &expiresAt
+		expiry.TTLSeconds = &seconds
+	}
+	return expiry, nil
+}
+
+func (r *basketRepository) IdleBaskets(ctx context.Context, before time.Time, offset, limit int64) ([]BasketActivity, error) {
+	entries, err := r.redisClient.ZRangeByScoreWithScores(ctx, activityKey(ctx), &redis.ZRangeBy{
+		Min:    "-inf",
+		Max:    strconv.FormatInt(before.Unix(), 10),
+		Offset: offset,
+		Count:  limit,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	idle := make([]BasketActivity, 0, len(entries))
+	for _, entry := range entries {
+		userID, _ := entry.Member.(string)
+		idle = append(idle, BasketActivity{
+			UserID:       userID,
+			LastActivity: time.Unix(int64(entry.Score), 0),
+		})
+	}
+	return idle, nil
+}
+
+func (r *basketRepository) MarkAbandoned(ctx context.Context, activity BasketActivity) (bool, error) {
+	removed, err := claimAbandoned.Run(ctx, r.redisClient, []string{activityKey(ctx)}, activity.UserID, activity.LastActivity.Unix()).Int()
+	if err != nil {
+		return false, err
+	}
+	return removed == 1, nil
+}
+
+func (r *basketRepository) UnmarkAbandoned(ctx context.Context, activity BasketActivity) error {
+	// NX: arada gelen bir güncellemenin yeni skoru ezilmez
+	return r.redisClient.ZAddNX(ctx, activityKey(ctx), &redis.Z{
+		Score:  float64(activity.LastActivity.Unix()),
+		Member: activity.UserID,
+	}).Err()
+}
+
+func (r *basketRepository) ExpiringBaskets(ctx context.Context, before time.Time, offset, limit int64) ([]string, error) {
+	return r.redisClient.ZRangeByScore(ctx, expiryKey(ctx), &redis.ZRangeBy{
+		Min:    "-inf",
+		Max:    strconv.FormatInt(before.Unix(), 10),
+		Offset: offset,
+		Count:  limit,
+	}).Result()
+}
+
+func (r *basketRepository) ArchiveBasket(ctx context.Context, userID string, before time.Time, store func(*model.Basket) error) (*ArchivedBasket, error) {
+	key := basketKey(ctx, userID)
+	var removed *ArchivedBasket
+
+	// Okuma da TTL'i yenilediği (EXPIRE) için WATCH edilen anahtar değişir ve
+	// transaction iptal olur
+	err := r.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+		removed = nil
+		expiresAt, err := tx.ZScore(ctx, expiryKey(ctx), userID).Result()
+		if err == redis.Nil || (err == nil && int64(expiresAt) > before.Unix()) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := tx.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		var archived ArchivedBasket
+		var marker string
+		if err == nil {
+			var basket model.Basket
+			if err := json.Unmarshal(data, &basket); err != nil {
+				return err
+			}
+			if err := r.storeOnce(ctx, tx, &basket, store); err != nil {
+				return err
+			}
+			marker = archivedKey(ctx, &basket)
+			archived.Basket = &basket
+
+			// Aktivite index'inde kalan sepet için terk edildi event'i gönderilmemiştir
+			_, err = tx.ZScore(ctx, activityKey(ctx), userID).Result()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			archived.Abandoned = err == nil
+		}
+
+		// Redis'in zaten düşürdüğü sepetler de index'lerden temizlenir
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.ZRem(ctx, activityKey(ctx), userID)
+			pipe.ZRem(ctx, expiryKey(ctx), userID)
+			if marker != "" {
+				pipe.Del(ctx, marker)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		removed = &archived
+		return nil
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		// Sepet arşivlenirken kullanıldı; süresi yenilendiği için artık aday değil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// storeOnce sepetin bu sürümü daha önce arşive yazılmadıysa store'u çağırır
+// ve işaretler. İşaret silmeyle aynı transaction'da kaldırılır; silme
+// başarısız olursa sonraki deneme kaydı tekrar yazmaz.
+func (r *basketRepository) storeOnce(ctx context.Context, tx *redis.Tx, basket *model.Basket, store func(*model.Basket) error) error {
+	marker := archivedKey(ctx, basket)
+	stored, err := tx.Exists(ctx, marker).Result()
+	if err != nil || stored > 0 {
+		return err
+	}
+	if err := store(basket); err != nil {
+		return err
+	}
+	return tx.Set(ctx, marker, r.clock.Now().Unix(), archivedMarkerTTL).Err()
+}
+
+func (r *basketRepository) TryLock(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return r.redisClient.SetNX(ctx, "lock:"+name, r.clock.Now().Unix(), ttl).Result()
+}
+
+func (r *basketRepository) AddItem(ctx context.Context, userID, currency string, item *model.BasketItem) error {
 	basket, err := r.GetBasket(ctx, userID)
 	if err != nil {
 		return err
 	}
 
+	if len(basket.Items) == 0 {
+		basket.Currency = currency
+	} else if basket.Currency != "" && basket.Currency != currency {
+		return ErrCurrencyLocked
+	}
+
 	// Mevcut item'ı kontrol et
 	for i, existingItem := range basket.Items {
-		if existingItem.ProductID == item.ProductID {
+		if existingItem.SameLine(item.ProductID, item.SKUID) {
+			// Stale snapshot'ı güncel ürün bilgisiyle yenile
+			if existingItem.Stale && !item.Stale {
+				quantity := existingItem.Quantity
+				basket.Items[i] = *item
+				basket.Items[i].Quantity = quantity
+			}
+
 			// Miktarı güncelle
 			basket.Items[i].Quantity += item.Quantity
 			basket.Total = r.calculateTotal(basket.Items)
@@ -91,14 +432,14 @@ func (r *basketRepository) AddItem(ctx context.Context, userID string, item *mod
 	return r.SaveBasket(ctx, basket)
 }
 
-func (r *basketRepository) RemoveItem(ctx context.Context, userID string, productID uint) error {
+func (r *basketRepository) RemoveItem(ctx context.Context, userID string, productID, skuID uint) error {
 	basket, err := r.GetBasket(ctx, userID)
 	if err != nil {
 		return err
 	}
 
 	for i, item := range basket.Items {
-		if item.ProductID == productID {
+		if item.SameLine(productID, skuID) {
 			basket.Items = append(basket.Items[:i], basket.Items[i+1:]...)
 			basket.Total = r.calculateTotal(basket.Items)
 			return r.SaveBasket(ctx, basket)
@@ -108,14 +449,14 @@ func (r *basketRepository) RemoveItem(ctx context.Context, userID string, produc
 	return nil
 }
 
-func (r *basketRepository) UpdateItemQuantity(ctx context.Context, userID string, productID uint, quantity int) error {
+func (r *basketRepository) UpdateItemQuantity(ctx context.Context, userID string, productID, skuID uint, quantity int) error {
 	basket, err := r.GetBasket(ctx, userID)
 	if err != nil {
 		return err
 	}
 
 	for i, item := range basket.Items {
-		if item.ProductID == productID {
+		if item.SameLine(productID, skuID) {
 			if quantity <= 0 {
 				// Miktar 0 veya daha az ise item'ı kaldır
 				basket.Items = append(basket.Items[:i], basket.Items[i+1:]...)
@@ -130,6 +471,16 @@ func (r *basketRepository) UpdateItemQuantity(ctx context.Context, userID string
 	return nil
 }
 
+func (r *basketRepository) SetShipping(ctx context.Context, userID string, shipping *model.Shipping) error {
+	basket, err := r.GetBasket(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	basket.Shipping = shipping
+	return r.SaveBasket(ctx, basket)
+}
+
 func (r *basketRepository) calculateTotal(items []model.BasketItem) float64 {
 	total := 0.0
 	for _, item := range items {
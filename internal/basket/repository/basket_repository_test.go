@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/tenant"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+var testPolicy = retention.Policy{
+	Guest:        7 * 24 * time.Hour,
+	Registered:   90 * 24 * time.Hour,
+	B2B:          0,
+	DefaultClass: model.UserClassGuest,
+}
+
+type repoFixture struct {
+	ctx   context.Context
+	redis *miniredis.Miniredis
+	clock *clock.Fake
+	repo  BasketRepository
+}
+
+func newRepoFixture(t *testing.T) *repoFixture {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	return &repoFixture{
+		ctx:   tenant.NewContext(context.Background(), "acme"),
+		redis: mr,
+		clock: clk,
+		repo:  NewBasketRepository(client, testPolicy, clk),
+	}
+}
+
+// advance saati ve Redis'in TTL'lerini birlikte ilerletir
+func (f *repoFixture) advance(d time.Duration) {
+	f.clock.Advance(d)
+	f.redis.FastForward(d)
+}
+
+func (f *repoFixture) save(t *testing.T, ctx context.Context, userID string) {
+	t.Helper()
+	basket := &model.Basket{UserID: userID, Items: []model.BasketItem{{ProductID: 1, Name: "Mug", Price: 10, Quantity: 1}}, Total: 10}
+	if err := f.repo.SaveBasket(ctx, basket); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertRetention sepet anahtarının TTL'ini ve süre index'indeki silinme zamanını doğrular
+func (f *repoFixture) assertRetention(t *testing.T, userID string, ttl time.Duration) {
+	t.Helper()
+	key := basketKey(f.ctx, userID)
+	if got := f.redis.TTL(key); got != ttl {
+		t.Fatalf("TTL of %s = %v, want %v", key, got, ttl)
+	}
+
+	members, _ := f.redis.ZMembers(expiryKey(f.ctx))
+	indexed := false
+	for _, member := range members {
+		indexed = indexed || member == userID
+	}
+	if ttl == 0 {
+		if indexed {
+			t.Fatalf("indefinite basket %s is in the expiry index", userID)
+		}
+		return
+	}
+	score, err := f.redis.ZScore(expiryKey(f.ctx), userID)
+	if want := f.clock.Now().Add(ttl).Unix(); !indexed || err != nil || int64(score) != want {
+		t.Fatalf("expiry index score of %s = %v (%v, indexed %v), want %d", userID, score, err, indexed, want)
+	}
+}
+
+func TestBasketRetentionPerUserClass(t *testing.T) {
+	f := newRepoFixture(t)
+
+	for _, tc := range []struct {
+		userID string
+		class  model.UserClass
+		ttl    time.Duration
+	}{
+		{"guest", model.UserClassGuest, 7 * 24 * time.Hour},
+		{"registered", model.UserClassRegistered, 90 * 24 * time.Hour},
+		{"b2b", model.UserClassB2B, 0},
+	} {
+		f.save(t, retention.WithClass(f.ctx, tc.class), tc.userID)
+		f.assertRetention(t, tc.userID, tc.ttl)
+
+		basket, err := f.repo.PeekBasket(f.ctx, tc.userID)
+		if err != nil || basket.UserClass != tc.class {
+			t.Fatalf("stored class of %s = %v (%v), want %s", tc.userID, basket, err, tc.class)
+		}
+	}
+
+	// Header'sız istek varsayılan sınıfı alır
+	f.save(t, f.ctx, "anonymous")
+	f.assertRetention(t, "anonymous", testPolicy.Guest)
+}
+
+func TestBasketRetentionRefreshedOnReadAndWrite(t *testing.T) {
+	f := newRepoFixture(t)
+	f.save(t, f.ctx, "u1")
+
+	f.advance(3 * 24 * time.Hour)
+	f.assertRetention(t, "u1", 4*24*time.Hour)
+
+	// Okuma süreyi tam saklama süresine yeniler
+	if _, err := f.repo.GetBasket(f.ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	f.assertRetention(t, "u1", testPolicy.Guest)
+
+	f.advance(5 * 24 * time.Hour)
+	f.save(t, f.ctx, "u1")
+	f.assertRetention(t, "u1", testPolicy.Guest)
+
+	// Saklama bilgisini okumak ve sweeper'ın Peek'i süreyi yenilemez
+	f.advance(24 * time.Hour)
+	if _, err := f.repo.GetExpiry(f.ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.repo.PeekBasket(f.ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	f.assertRetention(t, "u1", 6*24*time.Hour)
+
+	// Okuma sepette kayıtlı sınıfın süresini uygular; sınıf yazmada değişir ve
+	// süresiz sepet index'ten çıkar
+	if _, err := f.repo.GetBasket(retention.WithClass(f.ctx, model.UserClassRegistered), "u1"); err != nil {
+		t.Fatal(err)
+	}
+	f.assertRetention(t, "u1", testPolicy.Guest)
+	f.save(t, retention.WithClass(f.ctx, model.UserClassB2B), "u1")
+	f.assertRetention(t, "u1", 0)
+}
+
+func TestBasketExpires(t *testing.T) {
+	f := newRepoFixture(t)
+	f.save(t, f.ctx, "guest")
+	f.save(t, retention.WithClass(f.ctx, model.UserClassB2B), "b2b")
+
+	f.advance(testPolicy.Guest - time.Second)
+	if basket, _ := f.repo.PeekBasket(f.ctx, "guest"); basket == nil {
+		t.Fatal("basket expired before its retention period")
+	}
+
+	f.advance(time.Second)
+	if basket, _ := f.repo.PeekBasket(f.ctx, "guest"); basket != nil {
+		t.Fatalf("basket still exists after its retention period: %+v", basket)
+	}
+	if expiry, err := f.repo.GetExpiry(f.ctx, "guest"); err != nil || expiry != nil {
+		t.Fatalf("GetExpiry of expired basket = %+v, %v", expiry, err)
+	}
+	basket, err := f.repo.GetBasket(f.ctx, "guest")
+	if err != nil || len(basket.Items) != 0 || !basket.CreatedAt.Equal(f.clock.Now()) {
+		t.Fatalf("GetBasket after expiry = %+v, %v; want a new empty basket", basket, err)
+	}
+
+	// Süresiz sepet kalır
+	f.advance(365 * 24 * time.Hour)
+	if basket, _ := f.repo.PeekBasket(f.ctx, "b2b"); basket == nil {
+		t.Fatal("b2b basket expired")
+	}
+}
+
+func TestBasketExpiryReportsRemainingTime(t *testing.T) {
+	f := newRepoFixture(t)
+	f.save(t, retention.WithClass(f.ctx, model.UserClassRegistered), "u1")
+	savedAt := f.clock.Now()
+
+	f.advance(10 * 24 * time.Hour)
+	expiry, err := f.repo.GetExpiry(f.ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expiry.UserClass != model.UserClassRegistered || expiry.RetentionSeconds != int64(testPolicy.Registered/time.Second) {
+		t.Fatalf("expiry = %+v", expiry)
+	}
+	if !expiry.ExpiresAt.Equal(savedAt.Add(testPolicy.Registered)) || *expiry.TTLSeconds != int64(80*24*time.Hour/time.Second) {
+		t.Fatalf("expires_at = %v, ttl_seconds = %d", expiry.ExpiresAt, *expiry.TTLSeconds)
+	}
+}
+
+func TestBasketKeysDoNotCollideAcrossTenants(t *testing.T) {
+	f := newRepoFixture(t)
+	ctxA := tenant.NewContext(context.Background(), "a")
+	ctxB := tenant.NewContext(context.Background(), "b")
+
+	for ctx, name := range map[context.Context]string{ctxA: "A mug", ctxB: "B mug"} {
+		basket := &model.Basket{UserID: "u1", Items: []model.BasketItem{{ProductID: 1, Name: name, Price: 10, Quantity: 1}}}
+		if err := f.repo.SaveBasket(ctx, basket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range []string{"basket:a:u1", "basket:b:u1", "baskets:a:activity", "baskets:b:activity", "baskets:a:expiry", "baskets:b:expiry"} {
+		if !f.redis.Exists(key) {
+			t.Fatalf("key %s is missing; keys = %v", key, f.redis.Keys())
+		}
+	}
+
+	basket, err := f.repo.GetBasket(ctxA, "u1")
+	if err != nil || basket.Items[0].Name != "A mug" {
+		t.Fatalf("tenant a basket = %+v (%v)", basket, err)
+	}
+
+	// Bir tenant'taki silme ve arşivleme diğerinin sepetine ve index'lerine dokunmaz
+	if err := f.repo.DeleteBasket(ctxA, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	basket, err = f.repo.PeekBasket(ctxB, "u1")
+	if err != nil || basket == nil || basket.Items[0].Name != "B mug" {
+		t.Fatalf("tenant b basket after tenant a delete = %+v (%v)", basket, err)
+	}
+	if expiring, _ := f.repo.ExpiringBaskets(ctxA, f.clock.Now().Add(365*24*time.Hour), 0, 10); len(expiring) != 0 {
+		t.Fatalf("tenant a sees expiring baskets %v", expiring)
+	}
+	if removed, err := f.repo.ArchiveBasket(ctxA, "u1", f.clock.Now().Add(365*24*time.Hour), func(*model.Basket) error { return nil }); err != nil || removed != nil {
+		t.Fatalf("tenant a archived %+v (%v)", removed, err)
+	}
+	if idle, _ := f.repo.IdleBaskets(ctxB, f.clock.Now(), 0, 10); len(idle) != 1 || idle[0].UserID != "u1" {
+		t.Fatalf("tenant b idle baskets = %+v", idle)
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cluster-iac/internal/tenant"
+	"github.com/go-redis/redis/v8"
+)
+
+// tenantMigrationKey anahtarların tenant'lı biçime taşındığını işaretler
+const tenantMigrationKey = "migrations:tenant-keys"
+
+// MigrateTenantKeys tenant öncesinde yazılmış sepet ve liste anahtarlarını
+// (basket:{user_id}, baskets:activity, wishlist:{user_id}:{list_id} ...)
+// Default tenant'ın anahtarlarına taşır. İşaret yoksa henüz tenant'lı anahtar
+// yazılmamıştır, bu yüzden önekle eşleşen tüm anahtarlar eski biçimdedir.
+// Yeniden başlatmada işaret sayesinde tekrar çalışmaz.
+func MigrateTenantKeys(ctx context.Context, client *redis.Client) (int, error) {
+	done, err := client.Exists(ctx, tenantMigrationKey).Result()
+	if err != nil || done > 0 {
+		return 0, err
+	}
+
+	// Taşınan anahtarlar da önekle eşleştiği için önce tüm anahtarlar toplanır
+	type move struct{ key, target string }
+	var moves []move
+	for _, prefix := range []string{"basket:", "wishlist:", "wishlists:", "wishlist-share:"} {
+		iter := client.Scan(ctx, 0, prefix+"*", 500).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			moves = append(moves, move{key: key, target: prefix + tenant.Default + ":" + strings.TrimPrefix(key, prefix)})
+		}
+		if err := iter.Err(); err != nil {
+			return 0, err
+		}
+	}
+	moves = append(moves,
+		move{key: "baskets:activity", target: fmt.Sprintf("baskets:%s:activity", tenant.Default)},
+		move{key: "baskets:expiry", target: fmt.Sprintf("baskets:%s:expiry", tenant.Default)},
+	)
+
+	moved := 0
+	for _, m := range moves {
+		ok, err := renameKey(ctx, client, m.key, m.target)
+		if err != nil {
+			return moved, fmt.Errorf("failed to migrate %s: %w", m.key, err)
+		}
+		if ok {
+			moved++
+		}
+	}
+
+	return moved, client.Set(ctx, tenantMigrationKey, "done", 0).Err()
+}
+
+// renameKey hedef zaten varsa üzerine yazmaz; başka bir replika anahtarı
+// arada taşıdıysa hata sayılmaz
+func renameKey(ctx context.Context, client *redis.Client, key, target string) (bool, error) {
+	ok, err := client.RenameNX(ctx, key, target).Result()
+	if err != nil && strings.Contains(err.Error(), "no such key") {
+		return false, nil
+	}
+	return ok, err
+}
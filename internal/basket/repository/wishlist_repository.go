@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/tenant"
+	"github.com/go-redis/redis/v8"
+)
+
+// Listeler sepetlerin aksine süresiz saklanır; anahtarlar tenant başına ayrılır:
+//
+//	wishlist:{tenant}:{user_id}:{list_id}  liste JSON'u
+//	wishlists:{tenant}:{user_id}           kullanıcının liste id'leri (set)
+//	wishlist-share:{tenant}:{token}        paylaşılan listenin "{user_id}:{list_id}" adresi
+type WishlistRepository interface {
+	GetLists(ctx context.Context, userID string) ([]model.Wishlist, error)
+	// GetList liste yoksa nil döner; varsayılan liste hiç kaydedilmemişse boş olarak oluşturulur
+	GetList(ctx context.Context, userID, listID string) (*model.Wishlist, error)
+	SaveList(ctx context.Context, list *model.Wishlist) error
+	DeleteList(ctx context.Context, list *model.Wishlist) error
+	SetShareToken(ctx context.Context, list *model.Wishlist, token string) error
+	GetSharedList(ctx context.Context, token string) (*model.Wishlist, error)
+}
+
+type wishlistRepository struct {
+	redisClient *redis.Client
+}
+
+func NewWishlistRepository(redisClient *redis.Client) WishlistRepository {
+	return &wishlistRepository{redisClient: redisClient}
+}
+
+func wishlistKey(ctx context.Context, userID, listID string) string {
+	return fmt.Sprintf("wishlist:%s:%s:%s", tenant.ID(ctx), userID, listID)
+}
+
+func wishlistIndexKey(ctx context.Context, userID string) string {
+	return fmt.Sprintf("wishlists:%s:%s", tenant.ID(ctx), userID)
+}
+
+func wishlistShareKey(ctx context.Context, token string) string {
+	return fmt.Sprintf("wishlist-share:%s:%s", tenant.ID(ctx), token)
+}
+
+// GetLists varsayılan listeyi başa, diğerlerini oluşturulma sırasına göre döndürür
+func (r *wishlistRepository) GetLists(ctx context.Context, userID string) ([]model.Wishlist, error) {
+	ids, err := r.redisClient.SMembers(ctx, wishlistIndexKey(ctx, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	lists := []model.Wishlist{}
+	hasDefault := false
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = wishlistKey(ctx, userID, id)
+		}
+
+		values, err := r.redisClient.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				continue // index'te kalmış ama silinmiş liste
+			}
+			var list model.Wishlist
+			if err := json.Unmarshal([]byte(data), &list); err != nil {
+				return nil, err
+			}
+			hasDefault = hasDefault || list.ID == model.SavedForLaterListID
+			lists = append(lists, list)
+		}
+	}
+	if !hasDefault {
+		lists = append(lists, *newSavedForLater(userID))
+	}
+
+	sort.SliceStable(lists, func(i, j int) bool {
+		if (lists[i].ID == model.SavedForLaterListID) != (lists[j].ID == model.SavedForLaterListID) {
+			return lists[i].ID == model.SavedForLaterListID
+		}
+		return lists[i].CreatedAt.Before(lists[j].CreatedAt)
+	})
+	return lists, nil
+}
+
+func (r *wishlistRepository) GetList(ctx context.Context, userID, listID string) (*model.Wishlist, error) {
+	data, err := r.redisClient.Get(ctx, wishlistKey(ctx, userID, listID)).Bytes()
+	if err == redis.Nil {
+		if listID == model.SavedForLaterListID {
+			return newSavedForLater(userID), nil
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list model.Wishlist
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *wishlistRepository) SaveList(ctx context.Context, list *model.Wishlist) error {
+	list.UpdatedAt = time.Now()
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, wishlistKey(ctx, list.UserID, list.ID), data, 0)
+		pipe.SAdd(ctx, wishlistIndexKey(ctx, list.UserID), list.ID)
+		return nil
+	})
+	return err
+}
+
+func (r *wishlistRepository) DeleteList(ctx context.Context, list *model.Wishlist) error {
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, wishlistKey(ctx, list.UserID, list.ID))
+		pipe.SRem(ctx, wishlistIndexKey(ctx, list.UserID), list.ID)
+		if list.ShareToken != "" {
+			pipe.Del(ctx, wishlistShareKey(ctx, list.ShareToken))
+		}
+		return nil
+	})
+	return err
+}
+
+// SetShareToken listenin paylaşım token'ını değiştirir; token boşsa paylaşım kaldırılır
+func (r *wishlistRepository) SetShareToken(ctx context.Context, list *model.Wishlist, token string) error {
+	previous := list.ShareToken
+	list.ShareToken = token
+	list.UpdatedAt = time.Now()
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, wishlistShareKey(ctx, previous))
+		}
+		if token != "" {
+			pipe.Set(ctx, wishlistShareKey(ctx, token), list.UserID+":"+list.ID, 0)
+		}
+		pipe.Set(ctx, wishlistKey(ctx, list.UserID, list.ID), data, 0)
+		pipe.SAdd(ctx, wishlistIndexKey(ctx, list.UserID), list.ID)
+		return nil
+	})
+	return err
+}
+
+// GetSharedList token geçersizse ya da paylaşım kaldırılmışsa nil döner
+func (r *wishlistRepository) GetSharedList(ctx context.Context, token string) (*model.Wishlist, error) {
+	address, err := r.redisClient.Get(ctx, wishlistShareKey(ctx, token)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Liste id'leri ':' içermez, kullanıcı id'si içerebilir
+	separator := strings.LastIndex(address, ":")
+	if separator < 0 {
+		return nil, nil
+	}
+	list, err := r.GetList(ctx, address[:separator], address[separator+1:])
+	if err != nil || list == nil || list.ShareToken != token {
+		return nil, err
+	}
+	return list, nil
+}
+
+func newSavedForLater(userID string) *model.Wishlist {
+	now := time.Now()
+	return &model.Wishlist{
+		ID:        model.SavedForLaterListID,
+		UserID:    userID,
+		Name:      "Saved for later",
+		Items:     []model.WishlistItem{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
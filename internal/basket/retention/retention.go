@@ -0,0 +1,68 @@
+package retention
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/problem"
+)
+
+var ErrUnknownUserClass = problem.New(problem.Invalid, "user class must be one of guest, registered, b2b")
+
+// Policy sepetlerin kullanıcı sınıfına göre ne kadar tutulacağını belirler;
+// sıfır süre sepetin süresiz tutulacağı anlamına gelir
+type Policy struct {
+	Guest        time.Duration
+	Registered   time.Duration
+	B2B          time.Duration
+	DefaultClass model.UserClass
+}
+
+func (p Policy) TTL(class model.UserClass) time.Duration {
+	switch class {
+	case model.UserClassRegistered:
+		return p.Registered
+	case model.UserClassB2B:
+		return p.B2B
+	case model.UserClassGuest:
+		return p.Guest
+	default:
+		return p.TTL(p.DefaultClass)
+	}
+}
+
+// Resolve isteğin sınıfını, yoksa sepette kayıtlı sınıfı, o da yoksa
+// varsayılan sınıfı döndürür
+func (p Policy) Resolve(ctx context.Context, stored model.UserClass) model.UserClass {
+	if class, ok := ClassFromContext(ctx); ok {
+		return class
+	}
+	if stored != "" {
+		return stored
+	}
+	return p.DefaultClass
+}
+
+func ParseClass(value string) (model.UserClass, error) {
+	switch class := model.UserClass(strings.ToLower(strings.TrimSpace(value))); class {
+	case model.UserClassGuest, model.UserClassRegistered, model.UserClassB2B:
+		return class, nil
+	default:
+		return "", ErrUnknownUserClass
+	}
+}
+
+type classKey struct{}
+
+// WithClass isteği yapan kullanıcının sınıfını context'e ekler; repository
+// sepeti kaydederken bu sınıfın saklama süresini uygular
+func WithClass(ctx context.Context, class model.UserClass) context.Context {
+	return context.WithValue(ctx, classKey{}, class)
+}
+
+func ClassFromContext(ctx context.Context) (model.UserClass, bool) {
+	class, ok := ctx.Value(classKey{}).(model.UserClass)
+	return class, ok
+}
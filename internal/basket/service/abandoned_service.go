@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/basket/archive"
+	"cluster-iac/internal/basket/events"
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/tenant"
+)
+
+// Index'ler bu boyutta sayfalar halinde okunur
+const sweepBatchSize = 500
+
+type SweepResult struct {
+	Abandoned int
+	Archived  int
+}
+
+// AbandonedBasketService index'leri tarayarak terk edilmiş sepetler için event
+// üretir ve saklama süresi dolmak üzere olan sepetleri arşive taşır. Sweep
+// context'teki tenant'ın sepetlerini tarar.
+type AbandonedBasketService interface {
+	Sweep(ctx context.Context) (SweepResult, error)
+}
+
+type abandonedBasketService struct {
+	repo         repository.BasketRepository
+	archive      archive.Store
+	publisher    events.Publisher
+	clock        clock.Clock
+	abandonAfter time.Duration
+	archiveLead  time.Duration
+}
+
+// NewAbandonedBasketService archiveLead, sepetin süresi dolmadan ne kadar önce
+// arşivleneceğidir; sweep aralığından büyük olmalıdır
+func NewAbandonedBasketService(repo repository.BasketRepository, store archive.Store, publisher events.Publisher, clk clock.Clock, abandonAfter, archiveLead time.Duration) AbandonedBasketService {
+	return &abandonedBasketService{
+		repo:         repo,
+		archive:      store,
+		publisher:    publisher,
+		clock:        clk,
+		abandonAfter: abandonAfter,
+		archiveLead:  archiveLead,
+	}
+}
+
+func (s *abandonedBasketService) Sweep(ctx context.Context) (SweepResult, error) {
+	var result SweepResult
+	now := s.clock.Now()
+
+	// Önce arşivlenecekler: bunlar terk edildi event'i de almış olmalı, aksi
+	// halde (ör. servis kapalıyken) bildirilmeden silinmezler. Kaldırılan
+	// sepetler index'ten çıktığı için offset yalnızca yerinde kalanlar kadar ilerler.
+	archiveBefore := now.Add(s.archiveLead)
+	var offset int64
+	for {
+		page, err := s.repo.ExpiringBaskets(ctx, archiveBefore, offset, sweepBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, userID := range page {
+			removed, archived, err := s.archiveBasket(ctx, userID, archiveBefore)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to archive basket", "user_id", userID, "error", err)
+			}
+			if archived {
+				result.Archived++
+			}
+			if !removed {
+				offset++
+			}
+		}
+		if len(page) < sweepBatchSize {
+			break
+		}
+	}
+
+	// Bildirilen sepetler aktivite index'inden çıkar
+	offset = 0
+	for {
+		page, err := s.repo.IdleBaskets(ctx, now.Add(-s.abandonAfter), offset, sweepBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, activity := range page {
+			// Okuma saklama süresini yenilemesin diye Peek kullanılır
+			basket, err := s.repo.PeekBasket(ctx, activity.UserID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to load idle basket", "user_id", activity.UserID, "error", err)
+				offset++
+				continue
+			}
+			published, claimed, err := s.notifyAbandoned(ctx, basket, activity)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to publish abandoned basket", "user_id", activity.UserID, "error", err)
+			}
+			if published {
+				result.Abandoned++
+			}
+			if !claimed {
+				offset++
+			}
+		}
+		if len(page) < sweepBatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// archiveBasket sepetin index'lerden kaldırılıp kaldırılmadığını ve arşive
+// yazılıp yazılmadığını ayrı ayrı döndürür; boş sepetler yazılmadan kaldırılır.
+// Event'ler yalnızca sepet Redis'ten silindikten sonra gönderilir; silme
+// iptal olursa sonraki tarama aynı sepet için event üretebilsin diye.
+func (s *abandonedBasketService) archiveBasket(ctx context.Context, userID string, before time.Time) (bool, bool, error) {
+	removed, err := s.repo.ArchiveBasket(ctx, userID, before, func(basket *model.Basket) error {
+		if len(basket.Items) == 0 {
+			return nil
+		}
+		return s.archive.Put(ctx, archive.Record{
+			Tenant:         tenant.ID(ctx),
+			Basket:         *basket,
+			LastActivityAt: basketActivity(basket).LastActivity,
+			ArchivedAt:     s.clock.Now(),
+		})
+	})
+	if err != nil || removed == nil {
+		return false, false, err
+	}
+	basket := removed.Basket
+	if basket == nil || len(basket.Items) == 0 {
+		return true, false, nil
+	}
+
+	// Sepet artık arşivde; yayın hataları silmeyi geri almaz, yalnızca loglanır
+	activity := basketActivity(basket)
+	if removed.Abandoned {
+		if err := s.publisher.Publish(ctx, s.newBasketEvent(ctx, events.BasketAbandoned, basket, activity)); err != nil {
+			slog.ErrorContext(ctx, "failed to publish abandoned basket", "user_id", basket.UserID, "error", err)
+		}
+	}
+	if err := s.publisher.Publish(ctx, s.newBasketEvent(ctx, events.BasketArchived, basket, activity)); err != nil {
+		slog.ErrorContext(ctx, "failed to publish archived basket", "user_id", basket.UserID, "error", err)
+	}
+	return true, true, nil
+}
+
+func basketActivity(basket *model.Basket) repository.BasketActivity {
+	return repository.BasketActivity{UserID: basket.UserID, LastActivity: time.Unix(basket.UpdatedAt.Unix(), 0)}
+}
+
+// notifyAbandoned sepeti aktivite index'inden alarak event'i bir kez gönderir.
+// Yayın başarısız olursa sepet index'e geri konur ve sonraki taramada yeniden
+// denenir; publisher'lardan yalnızca biri başarısız olduysa diğerleri event'i
+// iki kez alabilir. Silinmiş ya da boş sepetler event üretmeden index'ten çıkar.
+func (s *abandonedBasketService) notifyAbandoned(ctx context.Context, basket *model.Basket, activity repository.BasketActivity) (published, claimed bool, err error) {
+	claimed, err = s.repo.MarkAbandoned(ctx, activity)
+	if err != nil || !claimed {
+		return false, claimed, err
+	}
+	if basket == nil || len(basket.Items) == 0 {
+		return false, true, nil
+	}
+
+	if err := s.publisher.Publish(ctx, s.newBasketEvent(ctx, events.BasketAbandoned, basket, activity)); err != nil {
+		if unmarkErr := s.repo.UnmarkAbandoned(ctx, activity); unmarkErr != nil {
+			slog.ErrorContext(ctx, "failed to unmark abandoned basket", "user_id", activity.UserID, "error", unmarkErr)
+		}
+		return false, false, err
+	}
+	return true, true, nil
+}
+
+func (s *abandonedBasketService) newBasketEvent(ctx context.Context, eventType events.EventType, basket *model.Basket, activity repository.BasketActivity) events.Event {
+	return events.Event{
+		Type:           eventType,
+		Tenant:         tenant.ID(ctx),
+		UserID:         basket.UserID,
+		Items:          basket.Items,
+		Total:          basket.Total,
+		LastActivityAt: activity.LastActivity,
+		OccurredAt:     s.clock.Now(),
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/basket/archive"
+	"cluster-iac/internal/basket/events"
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/retention"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/tenant"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+var testPolicy = retention.Policy{
+	Guest:        2 * time.Hour,
+	Registered:   24 * time.Hour,
+	DefaultClass: model.UserClassGuest,
+}
+
+type recordingStore struct {
+	records []archive.Record
+	// onPut kayıt yazıldıktan sonra, sepet silinmeden önce çağrılır
+	onPut func()
+}
+
+func (s *recordingStore) Put(ctx context.Context, record archive.Record) error {
+	s.records = append(s.records, record)
+	if s.onPut != nil {
+		s.onPut()
+	}
+	return nil
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) types() []events.EventType {
+	types := make([]events.EventType, len(p.events))
+	for i, event := range p.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+type abandonedFixture struct {
+	ctx       context.Context
+	redis     *redis.Client
+	clock     *clock.Fake
+	repo      repository.BasketRepository
+	store     *recordingStore
+	publisher *recordingPublisher
+	svc       AbandonedBasketService
+}
+
+func newAbandonedFixture(t *testing.T, abandonAfter time.Duration) *abandonedFixture {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	f := &abandonedFixture{
+		ctx:       tenant.NewContext(context.Background(), "acme"),
+		redis:     client,
+		clock:     clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)),
+		store:     &recordingStore{},
+		publisher: &recordingPublisher{},
+	}
+	f.repo = repository.NewBasketRepository(client, testPolicy, f.clock)
+	f.svc = NewAbandonedBasketService(f.repo, f.store, f.publisher, f.clock, abandonAfter, time.Hour)
+	return f
+}
+
+func (f *abandonedFixture) saveBasket(t *testing.T, userID string) {
+	t.Helper()
+	basket := &model.Basket{UserID: userID, Items: []model.BasketItem{{ProductID: 1, Name: "Mug", Price: 10, Quantity: 2}}, Total: 20}
+	if err := f.repo.SaveBasket(f.ctx, basket); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f *abandonedFixture) sweep(t *testing.T) SweepResult {
+	t.Helper()
+	result, err := f.svc.Sweep(f.ctx)
+	if err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	return result
+}
+
+func assertEventTypes(t *testing.T, publisher *recordingPublisher, want ...events.EventType) {
+	t.Helper()
+	got := publisher.types()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSweepArchivesExpiringBasket(t *testing.T) {
+	f := newAbandonedFixture(t, 24*time.Hour)
+	f.saveBasket(t, "u1")
+
+	f.clock.Advance(90 * time.Minute)
+	if result := f.sweep(t); result.Archived != 1 {
+		t.Fatalf("result = %+v, want one archived basket", result)
+	}
+
+	// Henüz bildirilmemiş sepet arşivlenirken terk edildi event'ini de alır
+	assertEventTypes(t, f.publisher, events.BasketAbandoned, events.BasketArchived)
+	if len(f.store.records) != 1 || f.store.records[0].Tenant != "acme" || f.store.records[0].Basket.UserID != "u1" {
+		t.Fatalf("records = %+v", f.store.records)
+	}
+	if basket, _ := f.repo.PeekBasket(f.ctx, "u1"); basket != nil {
+		t.Fatalf("basket still in Redis: %+v", basket)
+	}
+	if keys := f.redis.Keys(f.ctx, "baskets:acme:archived:*").Val(); len(keys) != 0 {
+		t.Fatalf("archive markers left behind: %v", keys)
+	}
+
+	f.sweep(t)
+	if len(f.store.records) != 1 || len(f.publisher.events) != 2 {
+		t.Fatalf("second sweep repeated side effects: %d records, %v", len(f.store.records), f.publisher.types())
+	}
+}
+
+func TestSweepDoesNotRepeatArchiveAfterAbortedDelete(t *testing.T) {
+	f := newAbandonedFixture(t, 24*time.Hour)
+	f.saveBasket(t, "u1")
+
+	// Sepet arşive yazılırken okunur; TTL yenilendiği için silme iptal olur
+	f.store.onPut = func() {
+		f.store.onPut = nil
+		if _, err := f.repo.GetBasket(f.ctx, "u1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.clock.Advance(90 * time.Minute)
+	if result := f.sweep(t); result.Archived != 0 {
+		t.Fatalf("result = %+v, want no archived basket", result)
+	}
+	if len(f.publisher.events) != 0 {
+		t.Fatalf("events published for a basket that was not deleted: %v", f.publisher.types())
+	}
+	if basket, _ := f.repo.PeekBasket(f.ctx, "u1"); basket == nil {
+		t.Fatal("basket was deleted although the transaction aborted")
+	}
+
+	// Sepetin aynı sürümü süresi yeniden dolarken arşive tekrar yazılmaz
+	f.clock.Advance(90 * time.Minute)
+	if result := f.sweep(t); result.Archived != 1 {
+		t.Fatalf("result = %+v, want one archived basket", result)
+	}
+	if len(f.store.records) != 1 {
+		t.Fatalf("basket archived %d times", len(f.store.records))
+	}
+	assertEventTypes(t, f.publisher, events.BasketAbandoned, events.BasketArchived)
+}
+
+func TestSweepArchivesUpdatedBasketAgain(t *testing.T) {
+	f := newAbandonedFixture(t, 30*time.Minute)
+	f.saveBasket(t, "u1")
+
+	// Arşive yazılırken güncellenen sepet yeni bir sürümdür ve yeniden arşivlenir
+	f.store.onPut = func() {
+		f.store.onPut = nil
+		f.saveBasket(t, "u1")
+	}
+	f.clock.Advance(90 * time.Minute)
+	result := f.sweep(t)
+	if result.Archived != 0 || result.Abandoned != 0 {
+		t.Fatalf("result = %+v, want nothing", result)
+	}
+
+	f.clock.Advance(90 * time.Minute)
+	if result := f.sweep(t); result.Archived != 1 {
+		t.Fatalf("result = %+v, want one archived basket", result)
+	}
+	if len(f.store.records) != 2 || !f.store.records[1].Basket.UpdatedAt.After(f.store.records[0].Basket.UpdatedAt) {
+		t.Fatalf("records = %+v", f.store.records)
+	}
+	assertEventTypes(t, f.publisher, events.BasketAbandoned, events.BasketArchived)
+}
+
+func TestSweepReportsAbandonedOnce(t *testing.T) {
+	f := newAbandonedFixture(t, 30*time.Minute)
+	f.saveBasket(t, "u1")
+
+	f.clock.Advance(45 * time.Minute)
+	if result := f.sweep(t); result.Abandoned != 1 || result.Archived != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	f.clock.Advance(10 * time.Minute)
+	f.sweep(t)
+	assertEventTypes(t, f.publisher, events.BasketAbandoned)
+
+	// Bildirilmiş sepet arşivlenirken terk edildi event'i tekrar gönderilmez
+	f.clock.Advance(time.Hour)
+	if result := f.sweep(t); result.Archived != 1 {
+		t.Fatalf("result = %+v, want one archived basket", result)
+	}
+	assertEventTypes(t, f.publisher, events.BasketAbandoned, events.BasketArchived)
+}
@@ -2,67 +2,294 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/basket/cache"
 	"cluster-iac/internal/basket/model"
 	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/basket/shipping"
+	"cluster-iac/internal/basket/tax"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/problem"
 )
 
+var (
+	ErrProductNotFound = problem.New(problem.NotFound, "product not found")
+	ErrProductDeleted  = problem.New(problem.Gone, "product is no longer available")
+	ErrVariantRequired = problem.New(problem.Invalid, "product has variants, sku_id is required")
+	ErrVariantNotFound = problem.New(problem.NotFound, "variant not found")
+	ErrBasketNotFound  = problem.New(problem.NotFound, "basket not found")
+)
+
+// GetBasketOptions sepetin nasıl sunulacağını belirler
+type GetBasketOptions struct {
+	// Verilirse satır ve toplam vergiler bu bölgeye göre hesaplanır
+	Destination *tax.Destination
+	// Verilirse sepetin para birimiyle aynı olmalıdır; dolu sepet başka bir
+	// para biriminde sunulmaz
+	Currency string
+}
+
+// AddItemOptions yeni satırın snapshot'ının nasıl alınacağını belirler
+type AddItemOptions struct {
+	// Boşsa sepetin (boş sepette varsayılan) para birimi kullanılır
+	Currency string
+	// Dil etiketi ya da Accept-Language listesi; satırın ad ve açıklaması bu
+	// dilde (ya da product servisindeki fallback'inde) saklanır
+	Locale string
+}
+
 type BasketService interface {
-	GetBasket(ctx context.Context, userID string) (*model.Basket, error)
-	AddItem(ctx context.Context, userID string, productID uint, quantity int) error
-	RemoveItem(ctx context.Context, userID string, productID uint) error
-	UpdateItemQuantity(ctx context.Context, userID string, productID uint, quantity int) error
+	GetBasket(ctx context.Context, userID string, opts GetBasketOptions) (*model.Basket, error)
+	AddItem(ctx context.Context, userID string, productID, skuID uint, quantity int, opts AddItemOptions) error
+	RemoveItem(ctx context.Context, userID string, productID, skuID uint) error
+	UpdateItemQuantity(ctx context.Context, userID string, productID, skuID uint, quantity int) error
 	ClearBasket(ctx context.Context, userID string) error
+	GetExpiry(ctx context.Context, userID string) (*model.BasketExpiry, error)
+	GetShippingOptions(ctx context.Context, userID, country string) (*ShippingOptions, error)
+	SelectShipping(ctx context.Context, userID, methodID, country string) (*model.Basket, error)
+	ClearShipping(ctx context.Context, userID string) error
 }
 
 type basketService struct {
-	repo         repository.BasketRepository
-	productClient product.ProductServiceClient
+	repo     repository.BasketRepository
+	products productLookup
+	tax      tax.TaxCalculator
+	shipping *shipping.Catalog
+
+	currencies *currency.Converter
+	// Para birimi istenmeden oluşturulan sepetlerin para birimi
+	defaultCurrency string
 }
 
-func NewBasketService(repo repository.BasketRepository, productClient product.ProductServiceClient) BasketService {
+func NewBasketService(repo repository.BasketRepository, productClient product.ProductServiceClient, productCache cache.ProductCache, taxCalculator tax.TaxCalculator, shippingCatalog *shipping.Catalog, currencies *currency.Converter, defaultCurrency string) BasketService {
 	return &basketService{
-		repo:         repo,
-		productClient: productClient,
+		repo:            repo,
+		products:        productLookup{client: productClient, cache: productCache},
+		tax:             taxCalculator,
+		shipping:        shippingCatalog,
+		currencies:      currencies,
+		defaultCurrency: defaultCurrency,
 	}
 }
 
-func (s *basketService) GetBasket(ctx context.Context, userID string) (*model.Basket, error) {
-	return s.repo.GetBasket(ctx, userID)
+// currencyLocked dolu sepetin para birimini açıkça söyleyen Conflict hatasıdır
+func currencyLocked(code string) error {
+	return problem.New(problem.Conflict, fmt.Sprintf("basket is priced in %s; empty the basket to switch currency", code))
 }
 
-func (s *basketService) AddItem(ctx context.Context, userID string, productID uint, quantity int) error {
-	// Product bilgilerini gRPC ile al
-	productResp, err := s.productClient.GetProduct(ctx, &product.GetProductRequest{
-		Id: uint32(productID),
-	})
+// resolveCurrency sepetin kullanacağı para birimini belirler. Ürün olan
+// sepet kendi para birimine kilitlidir; boş sepet istenen para birimini,
+// istenmediyse varsayılanı kullanır.
+func (s *basketService) resolveCurrency(basket *model.Basket, requested string) (string, error) {
+	if basket != nil && len(basket.Items) > 0 {
+		// Para birimi alanından önce oluşturulan sepetler varsayılan para birimindedir
+		locked := basket.Currency
+		if locked == "" {
+			locked = s.defaultCurrency
+		}
+		if requested != "" && requested != locked {
+			return "", currencyLocked(locked)
+		}
+		return locked, nil
+	}
+	if requested != "" {
+		return requested, nil
+	}
+	return s.defaultCurrency, nil
+}
+
+func (s *basketService) GetBasket(ctx context.Context, userID string, opts GetBasketOptions) (*model.Basket, error) {
+	basket, err := s.repo.GetBasket(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get product: %v", err)
+		return nil, err
+	}
+
+	code, err := s.resolveCurrency(basket, opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+	basket.Currency = code
+
+	s.markUnavailableItems(ctx, basket)
+	if opts.Destination != nil {
+		if err := s.applyTax(ctx, basket, *opts.Destination); err != nil {
+			return nil, err
+		}
+	}
+	s.applyShipping(basket)
+
+	grandTotal := basket.Total
+	if basket.Tax != nil {
+		grandTotal = basket.Tax.Gross
+	}
+	if basket.Shipping != nil && !basket.Shipping.Unavailable {
+		grandTotal += basket.Shipping.Cost
+	}
+	grandTotal = s.currencies.Round(grandTotal, basket.Currency)
+	basket.GrandTotal = &grandTotal
+	return basket, nil
+}
+
+// applyTax satır ve toplam vergileri hesaplar; kullanılamayan item'lar
+// toplama dahil olmadığı için vergilendirilmez
+func (s *basketService) applyTax(ctx context.Context, basket *model.Basket, destination tax.Destination) error {
+	var lines []tax.Line
+	var indexes []int
+	for i, item := range basket.Items {
+		if item.Unavailable {
+			continue
+		}
+		lines = append(lines, tax.Line{TaxClass: item.TaxClass, UnitPrice: item.Price, Quantity: item.Quantity})
+		indexes = append(indexes, i)
+	}
+
+	result, err := s.tax.Calculate(ctx, destination, s.currencies.Decimals(basket.Currency), lines)
+	if err != nil {
+		return err
+	}
+
+	for n, i := range indexes {
+		line := result.Lines[n]
+		basket.Items[i].Tax = &model.ItemTax{Rate: line.Rate, Net: line.Net, Tax: line.Tax, Gross: line.Gross}
+	}
+	basket.Tax = &model.BasketTax{
+		Country:          result.Destination.Country,
+		Region:           result.Destination.Region,
+		PricesIncludeTax: result.PricesIncludeTax,
+		Net:              result.Net,
+		Tax:              result.Tax,
+		Gross:            result.Gross,
+	}
+	return nil
+}
+
+// GetExpiry sepetin ne zaman silineceğini döndürür; sepetin süresini yenilemez
+func (s *basketService) GetExpiry(ctx context.Context, userID string) (*model.BasketExpiry, error) {
+	expiry, err := s.repo.GetExpiry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if expiry == nil {
+		return nil, ErrBasketNotFound
+	}
+	return expiry, nil
+}
+
+// AddItem ürünü sepetin para biriminde fiyatlayarak ve istenen dildeki
+// metniyle ekler. Sepette zaten olan satırın snapshot'ı (ve dili) korunur.
+func (s *basketService) AddItem(ctx context.Context, userID string, productID, skuID uint, quantity int, opts AddItemOptions) error {
+	basket, err := s.repo.PeekBasket(ctx, userID)
+	if err != nil {
+		return err
+	}
+	code, err := s.resolveCurrency(basket, opts.Currency)
+	if err != nil {
+		return err
+	}
+
+	// Product bilgilerini cache'ten ya da gRPC ile al
+	prod, snapshotAt, stale, err := s.products.get(ctx, productID, cache.View{Currency: code, Locale: opts.Locale})
+	if err != nil {
+		return err
 	}
 
 	// Basket item oluştur
 	item := &model.BasketItem{
 		ProductID:   productID,
-		Name:        productResp.Product.Name,
-		Description: productResp.Product.Description,
-		Price:       productResp.Product.Price,
-		ImageURL:    productResp.Product.ImageUrl,
+		Name:        prod.Name,
+		Description: prod.Description,
+		Locale:      prod.Locale,
+		Price:       prod.Price,
+		ImageURL:    prod.ImageUrl,
 		Quantity:    quantity,
+		TaxClass:    prod.TaxClass,
+		WeightKg:    prod.WeightKg,
+		LengthCm:    prod.LengthCm,
+		WidthCm:     prod.WidthCm,
+		HeightCm:    prod.HeightCm,
+		SnapshotAt:  snapshotAt,
+		Stale:       stale,
+	}
+
+	// Varyantlı ürünlerde fiyat, görsel ve attribute'lar SKU'dan gelir
+	if len(prod.Variants) > 0 || skuID != 0 {
+		if skuID == 0 {
+			return ErrVariantRequired
+		}
+		variant := findVariant(prod, skuID)
+		if variant == nil {
+			return ErrVariantNotFound
+		}
+
+		item.SKUID = skuID
+		item.SKU = variant.Sku
+		item.Attributes = variant.Attributes
+		item.Price = variant.Price
+		if variant.ImageUrl != "" {
+			item.ImageURL = variant.ImageUrl
+		}
 	}
 
-	return s.repo.AddItem(ctx, userID, item)
+	// Okuma ile yazma arasında sepet başka para birimiyle doldurulduysa
+	err = s.repo.AddItem(ctx, userID, code, item)
+	if errors.Is(err, repository.ErrCurrencyLocked) {
+		if current, peekErr := s.repo.PeekBasket(ctx, userID); peekErr == nil && current != nil && current.Currency != "" {
+			return currencyLocked(current.Currency)
+		}
+	}
+	return err
 }
 
-func (s *basketService) RemoveItem(ctx context.Context, userID string, productID uint) error {
-	return s.repo.RemoveItem(ctx, userID, productID)
+func (s *basketService) RemoveItem(ctx context.Context, userID string, productID, skuID uint) error {
+	return s.repo.RemoveItem(ctx, userID, productID, skuID)
 }
 
-func (s *basketService) UpdateItemQuantity(ctx context.Context, userID string, productID uint, quantity int) error {
-	return s.repo.UpdateItemQuantity(ctx, userID, productID, quantity)
+func (s *basketService) UpdateItemQuantity(ctx context.Context, userID string, productID, skuID uint, quantity int) error {
+	return s.repo.UpdateItemQuantity(ctx, userID, productID, skuID, quantity)
 }
 
 func (s *basketService) ClearBasket(ctx context.Context, userID string) error {
 	return s.repo.DeleteBasket(ctx, userID)
 }
+
+// markUnavailableItems silinmiş ya da artık var olmayan ürünlere (veya
+// SKU'lara) ait item'ları işaretler ve toplamı yeniden hesaplar. Cache'te güncel
+// kaydı olan ürünler sorgulanmaz; product servisine ulaşılamazsa sepet olduğu
+// gibi döner.
+func (s *basketService) markUnavailableItems(ctx context.Context, basket *model.Basket) {
+	ids := make([]uint, 0, len(basket.Items))
+	for _, item := range basket.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	// Yalnızca varlık kontrol edilir; satırların metni yenilenmez
+	products, gone, err := s.products.getMany(ctx, ids, cache.View{Currency: basket.Currency})
+	if err != nil {
+		return
+	}
+
+	changed := false
+	for i, item := range basket.Items {
+		unavailable := gone[item.ProductID]
+		if prod, ok := products[item.ProductID]; ok && item.SKUID != 0 && findVariant(prod, item.SKUID) == nil {
+			unavailable = true
+		}
+		if unavailable {
+			basket.Items[i].Unavailable = true
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	basket.Total = 0
+	for _, item := range basket.Items {
+		if !item.Unavailable {
+			basket.Total += item.Price * float64(item.Quantity)
+		}
+	}
+}
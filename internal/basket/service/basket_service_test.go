@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/basket/cache"
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/clock"
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/tenant"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+)
+
+// priceClient ürünleri istenen para biriminin fiyatıyla döndürür
+type priceClient struct {
+	product.ProductServiceClient
+	prices map[string]float64
+	// onGet GetProduct yanıt vermeden önce çağrılır
+	onGet func()
+}
+
+func (c *priceClient) product(id uint32, code string) *product.Product {
+	return &product.Product{Id: id, Name: "Mug", Price: c.prices[code]}
+}
+
+func (c *priceClient) GetProduct(ctx context.Context, in *product.GetProductRequest, opts ...grpc.CallOption) (*product.GetProductResponse, error) {
+	if c.onGet != nil {
+		c.onGet()
+	}
+	return &product.GetProductResponse{Product: c.product(in.Id, in.Currency)}, nil
+}
+
+func (c *priceClient) GetProducts(ctx context.Context, in *product.GetProductsRequest, opts ...grpc.CallOption) (*product.GetProductsResponse, error) {
+	resp := &product.GetProductsResponse{}
+	for _, id := range in.Ids {
+		resp.Products = append(resp.Products, c.product(id, in.Currency))
+	}
+	return resp, nil
+}
+
+type currencyFixture struct {
+	ctx    context.Context
+	client *priceClient
+	repo   repository.BasketRepository
+	svc    BasketService
+}
+
+func newCurrencyFixture(t *testing.T) *currencyFixture {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	currencies, err := currency.NewConverter(context.Background(), currency.NewSource(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &currencyFixture{
+		ctx:    tenant.NewContext(context.Background(), "acme"),
+		client: &priceClient{prices: map[string]float64{"TRY": 100, "EUR": 2.76}},
+	}
+	f.repo = repository.NewBasketRepository(client, testPolicy, clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+	// Cache'siz: her istek product servisine gider
+	f.svc = NewBasketService(f.repo, f.client, cache.NewProductCache(1, 0), nil, nil, currencies, "TRY")
+	return f
+}
+
+func assertCurrencyLocked(t *testing.T, err error, locked string) {
+	t.Helper()
+	if !problem.IsKind(err, problem.Conflict) || !strings.Contains(err.Error(), "priced in "+locked) {
+		t.Fatalf("err = %v, want conflict naming %s", err, locked)
+	}
+}
+
+func TestBasketCurrencyIsLockedWhileItHasItems(t *testing.T) {
+	f := newCurrencyFixture(t)
+
+	if err := f.svc.AddItem(f.ctx, "u1", 1, 0, 1, AddItemOptions{Currency: "EUR"}); err != nil {
+		t.Fatal(err)
+	}
+	// Para birimi istenmezse sepetinki kullanılır
+	if err := f.svc.AddItem(f.ctx, "u1", 2, 0, 2, AddItemOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assertCurrencyLocked(t, f.svc.AddItem(f.ctx, "u1", 3, 0, 1, AddItemOptions{Currency: "TRY"}), "EUR")
+
+	basket, err := f.svc.GetBasket(f.ctx, "u1", GetBasketOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basket.Currency != "EUR" || len(basket.Items) != 2 || basket.Items[1].Price != 2.76 || *basket.GrandTotal != 8.28 {
+		t.Fatalf("basket = %s %+v, grand total %v", basket.Currency, basket.Items, *basket.GrandTotal)
+	}
+	_, err = f.svc.GetBasket(f.ctx, "u1", GetBasketOptions{Currency: "TRY"})
+	assertCurrencyLocked(t, err, "EUR")
+
+	// Boşaltılan sepet para birimini değiştirebilir
+	if err := f.svc.ClearBasket(f.ctx, "u1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.AddItem(f.ctx, "u1", 1, 0, 1, AddItemOptions{Currency: "TRY"}); err != nil {
+		t.Fatal(err)
+	}
+	if basket, err := f.svc.GetBasket(f.ctx, "u1", GetBasketOptions{}); err != nil || basket.Currency != "TRY" || basket.Items[0].Price != 100 {
+		t.Fatalf("basket after switching = %+v (%v)", basket, err)
+	}
+}
+
+func TestBasketCurrencyLockSurvivesConcurrentAdd(t *testing.T) {
+	f := newCurrencyFixture(t)
+
+	// Fiyat okunurken sepet başka bir istekle EUR olarak doldurulur
+	f.client.onGet = func() {
+		f.client.onGet = nil
+		if err := f.repo.AddItem(f.ctx, "u1", "EUR", &model.BasketItem{ProductID: 9, Name: "Plate", Price: 5, Quantity: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertCurrencyLocked(t, f.svc.AddItem(f.ctx, "u1", 1, 0, 1, AddItemOptions{Currency: "TRY"}), "EUR")
+
+	basket, err := f.repo.PeekBasket(f.ctx, "u1")
+	if err != nil || basket.Currency != "EUR" || len(basket.Items) != 1 {
+		t.Fatalf("basket = %+v (%v)", basket, err)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/shipping"
+	"cluster-iac/internal/problem"
+)
+
+type ShippingOptions struct {
+	Country    string            `json:"country"`
+	Currency   string            `json:"currency"`
+	OrderValue float64           `json:"order_value"`
+	Options    []shipping.Option `json:"options"`
+}
+
+// GetShippingOptions sepetin güncel içeriğine göre ülkeye gönderilebilecek
+// yöntemleri ve ücretlerini sepetin para biriminde döndürür
+func (s *basketService) GetShippingOptions(ctx context.Context, userID, country string) (*ShippingOptions, error) {
+	basket, err := s.loadForShipping(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.newShipment(basket, country)
+	if err != nil {
+		return nil, err
+	}
+	options, err := s.shipping.Options(shipment)
+	if err != nil {
+		return nil, err
+	}
+	for i := range options {
+		if err := s.localizeOption(&options[i], basket.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ShippingOptions{
+		Country:    strings.ToUpper(strings.TrimSpace(country)),
+		Currency:   basket.Currency,
+		OrderValue: basket.Total,
+		Options:    options,
+	}, nil
+}
+
+// SelectShipping yöntemin sepet ve ülke için uygun olduğunu doğrular ve
+// sepete kaydeder
+func (s *basketService) SelectShipping(ctx context.Context, userID, methodID, country string) (*model.Basket, error) {
+	basket, err := s.loadForShipping(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := s.newShipment(basket, country)
+	if err != nil {
+		return nil, err
+	}
+	option, err := s.shipping.Quote(methodID, shipment)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.localizeOption(option, basket.Currency); err != nil {
+		return nil, err
+	}
+
+	selection := &model.Shipping{
+		MethodID: option.MethodID,
+		Country:  strings.ToUpper(strings.TrimSpace(country)),
+		Name:     option.Name,
+		Carrier:  option.Carrier,
+		Cost:     option.Cost,
+		Free:     option.Free,
+	}
+	if err := s.repo.SetShipping(ctx, userID, selection); err != nil {
+		return nil, err
+	}
+
+	return s.GetBasket(ctx, userID, GetBasketOptions{})
+}
+
+func (s *basketService) ClearShipping(ctx context.Context, userID string) error {
+	return s.repo.SetShipping(ctx, userID, nil)
+}
+
+func (s *basketService) loadForShipping(ctx context.Context, userID string) (*model.Basket, error) {
+	basket, err := s.repo.GetBasket(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if basket.Currency, err = s.resolveCurrency(basket, ""); err != nil {
+		return nil, err
+	}
+
+	s.markUnavailableItems(ctx, basket)
+	return basket, nil
+}
+
+// applyShipping seçili yöntemin ücretini sepetin güncel içeriğiyle yeniden
+// hesaplar; yöntem artık uygun değilse (ör. ağırlık bandı aşıldı, sepet
+// boşaldı ya da ağırlığı olmayan bir satır eklendi) işaretler
+func (s *basketService) applyShipping(basket *model.Basket) {
+	if basket.Shipping == nil {
+		return
+	}
+
+	shipment, err := s.newShipment(basket, basket.Shipping.Country)
+	if err != nil {
+		return
+	}
+	option, err := s.shipping.Quote(basket.Shipping.MethodID, shipment)
+	if errors.Is(err, shipping.ErrMethodNotAvailable) || errors.Is(err, shipping.ErrMethodNotFound) || problem.IsKind(err, problem.Validation) {
+		basket.Shipping.Unavailable = true
+		basket.Shipping.Cost = 0
+		basket.Shipping.Free = false
+		return
+	}
+	if err != nil || s.localizeOption(option, basket.Currency) != nil {
+		return
+	}
+
+	basket.Shipping.Name = option.Name
+	basket.Shipping.Carrier = option.Carrier
+	basket.Shipping.Cost = option.Cost
+	basket.Shipping.Free = option.Free
+	basket.Shipping.Unavailable = false
+}
+
+// newShipment kullanılabilir satırlardan gönderi oluşturur; sipariş tutarı
+// yöntemlerin eşikleriyle karşılaştırılabilsin diye katalog para birimine
+// çevrilen sepet toplamıdır
+func (s *basketService) newShipment(basket *model.Basket, country string) (shipping.Shipment, error) {
+	orderValue, err := s.currencies.Convert(basket.Total, basket.Currency, s.shipping.Currency())
+	if err != nil {
+		return shipping.Shipment{}, err
+	}
+
+	shipment := shipping.Shipment{Country: country, OrderValue: orderValue}
+	for _, item := range basket.Items {
+		if item.Unavailable {
+			continue
+		}
+		shipment.Packages = append(shipment.Packages, shipping.Package{
+			ProductID: item.ProductID,
+			WeightKg:  item.WeightKg,
+			LengthCm:  item.LengthCm,
+			WidthCm:   item.WidthCm,
+			HeightCm:  item.HeightCm,
+			Quantity:  item.Quantity,
+		})
+	}
+	return shipment, nil
+}
+
+// localizeOption katalog para birimindeki tutarları sepetin para birimine çevirir
+func (s *basketService) localizeOption(option *shipping.Option, code string) error {
+	from := s.shipping.Currency()
+	if from == code {
+		return nil
+	}
+
+	cost, err := s.currencies.Convert(option.Cost, from, code)
+	if err != nil {
+		return err
+	}
+	option.Cost = cost
+
+	if option.AmountToFreeShipping != nil {
+		remaining, err := s.currencies.Convert(*option.AmountToFreeShipping, from, code)
+		if err != nil {
+			return err
+		}
+		option.AmountToFreeShipping = &remaining
+	}
+	return nil
+}
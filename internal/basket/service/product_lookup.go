@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/basket/cache"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/tenant"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// productLookup ürün snapshot'larını önce cache'ten, gerekirse product
+// servisinden okur; sepet ve listeler aynı kaynağı kullanır. Fiyatlar
+// view'daki para biriminde, metinler view'daki dilde (ya da fallback'inde)
+// gelir. Tenant context'ten alınır ve gRPC metadata'sıyla iletilir.
+type productLookup struct {
+	client product.ProductServiceClient
+	cache  cache.ProductCache
+}
+
+// get önce cache'e bakar; kayıt yoksa ya da süresi dolmuşsa product
+// servisine gider. Servis erişilemez durumdaysa süresi dolmuş kayıt stale
+// olarak döndürülür.
+func (l productLookup) get(ctx context.Context, productID uint, view cache.View) (*product.Product, time.Time, bool, error) {
+	view.Tenant, _ = tenant.FromContext(ctx)
+	entry, cached := l.cache.Get(productID, view)
+	if cached && !entry.Expired(time.Now()) {
+		return entry.Product, entry.FetchedAt, false, nil
+	}
+
+	productResp, err := l.client.GetProduct(ctx, &product.GetProductRequest{
+		Id:       uint32(productID),
+		Currency: view.Currency,
+		Locale:   view.Locale,
+	})
+	if err != nil {
+		if cached && isUnavailable(err) {
+			return entry.Product, entry.FetchedAt, true, nil
+		}
+		if status.Code(err) == codes.NotFound {
+			l.cache.Invalidate(productID)
+			if notFoundReason(err) == product.ReasonProductDeleted {
+				return nil, time.Time{}, false, ErrProductDeleted
+			}
+			return nil, time.Time{}, false, ErrProductNotFound
+		}
+		// Unavailable/DeadlineExceeded 503/504, diğerleri tipine göre döner
+		return nil, time.Time{}, false, fmt.Errorf("failed to get product: %w", problem.FromGRPC(err))
+	}
+
+	l.cache.Set(productResp.Product, view)
+	return productResp.Product, time.Now(), false, nil
+}
+
+// getMany ürünleri toplu olarak döndürür; gone silinmiş ya da artık var
+// olmayan ürünlerdir. Cache'te güncel kaydı olan ürünler sorgulanmaz.
+func (l productLookup) getMany(ctx context.Context, productIDs []uint, view cache.View) (map[uint]*product.Product, map[uint]bool, error) {
+	view.Tenant, _ = tenant.FromContext(ctx)
+	products := make(map[uint]*product.Product, len(productIDs))
+	gone := make(map[uint]bool)
+	var ids []uint32
+	now := time.Now()
+	for _, id := range productIDs {
+		if _, seen := products[id]; seen {
+			continue
+		}
+		if entry, ok := l.cache.Get(id, view); ok && !entry.Expired(now) {
+			products[id] = entry.Product
+			continue
+		}
+		ids = append(ids, uint32(id))
+	}
+	if len(ids) == 0 {
+		return products, gone, nil
+	}
+
+	resp, err := l.client.GetProducts(ctx, &product.GetProductsRequest{Ids: ids, Currency: view.Currency, Locale: view.Locale})
+	if err != nil {
+		return nil, nil, problem.FromGRPC(err)
+	}
+
+	for _, p := range resp.Products {
+		l.cache.Set(p, view)
+		products[uint(p.Id)] = p
+	}
+	for _, id := range append(resp.DeletedIds, resp.MissingIds...) {
+		gone[uint(id)] = true
+		l.cache.Invalidate(uint(id))
+	}
+	return products, gone, nil
+}
+
+func findVariant(prod *product.Product, skuID uint) *product.ProductVariant {
+	for _, variant := range prod.Variants {
+		if uint(variant.Id) == skuID {
+			return variant
+		}
+	}
+	return nil
+}
+
+// notFoundReason product servisinin NotFound hatasına eklediği ErrorInfo reason'ını döndürür
+func notFoundReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == product.ErrorDomain {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func isUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
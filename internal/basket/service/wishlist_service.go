@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode"
+
+	"cluster-iac/api/proto/product"
+	"cluster-iac/internal/basket/cache"
+	"cluster-iac/internal/basket/model"
+	"cluster-iac/internal/basket/repository"
+	"cluster-iac/internal/problem"
+)
+
+var (
+	ErrListNotFound       = problem.New(problem.NotFound, "list not found")
+	ErrListExists         = problem.New(problem.Duplicate, "a list with this name already exists")
+	ErrInvalidListName    = problem.New(problem.Invalid, "list name must contain at least one letter or digit")
+	ErrBasketItemNotFound = problem.New(problem.NotFound, "item not found in basket")
+	ErrListItemNotFound   = problem.New(problem.NotFound, "item not found in list")
+)
+
+// Fiyat karşılaştırmasında kuruş altı farklar yok sayılır
+const priceDropEpsilon = 0.005
+
+type WishlistService interface {
+	GetLists(ctx context.Context, userID string) ([]model.Wishlist, error)
+	CreateList(ctx context.Context, userID, name string) (*model.Wishlist, error)
+	GetList(ctx context.Context, userID, listID string) (*model.Wishlist, error)
+	DeleteList(ctx context.Context, userID, listID string) error
+	AddItem(ctx context.Context, userID, listID string, productID, skuID uint, quantity int) (*model.Wishlist, error)
+	RemoveItem(ctx context.Context, userID, listID string, productID, skuID uint) error
+	MoveFromBasket(ctx context.Context, userID, listID string, productID, skuID uint) (*model.Wishlist, error)
+	MoveToBasket(ctx context.Context, userID, listID string, productID, skuID uint, locale string) (*model.Basket, error)
+	ShareList(ctx context.Context, userID, listID string) (*model.Wishlist, error)
+	UnshareList(ctx context.Context, userID, listID string) error
+	GetSharedList(ctx context.Context, token string) (*model.Wishlist, error)
+}
+
+type wishlistService struct {
+	repo          repository.WishlistRepository
+	basketRepo    repository.BasketRepository
+	basketService BasketService
+	products      productLookup
+	// Listeye doğrudan eklenen ürünlerin fiyatı bu para biriminde saklanır
+	defaultCurrency string
+}
+
+func NewWishlistService(repo repository.WishlistRepository, basketRepo repository.BasketRepository, basketService BasketService, productClient product.ProductServiceClient, productCache cache.ProductCache, defaultCurrency string) WishlistService {
+	return &wishlistService{
+		repo:            repo,
+		basketRepo:      basketRepo,
+		basketService:   basketService,
+		products:        productLookup{client: productClient, cache: productCache},
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+func (s *wishlistService) GetLists(ctx context.Context, userID string) ([]model.Wishlist, error) {
+	lists, err := s.repo.GetLists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tüm listelerin ürünleri para birimi başına tek GetProducts çağrısıyla çözülür
+	var items []model.WishlistItem
+	for _, list := range lists {
+		items = append(items, list.Items...)
+	}
+	products, gone, err := s.lookup(ctx, items)
+	if err != nil {
+		return lists, nil
+	}
+	for i := range lists {
+		s.applyFlags(&lists[i], products, gone)
+	}
+	return lists, nil
+}
+
+// CreateList adı slug'a çevirerek liste id'si üretir
+func (s *wishlistService) CreateList(ctx context.Context, userID, name string) (*model.Wishlist, error) {
+	name = strings.TrimSpace(name)
+	listID := listSlug(name)
+	if listID == "" {
+		return nil, ErrInvalidListName
+	}
+
+	existing, err := s.repo.GetList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrListExists
+	}
+
+	now := time.Now()
+	list := &model.Wishlist{
+		ID:        listID,
+		UserID:    userID,
+		Name:      name,
+		Items:     []model.WishlistItem{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.SaveList(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *wishlistService) GetList(ctx context.Context, userID, listID string) (*model.Wishlist, error) {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.markFlags(ctx, list)
+	return list, nil
+}
+
+// DeleteList varsayılan listeyi silmek onu boşaltır
+func (s *wishlistService) DeleteList(ctx context.Context, userID, listID string) error {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteList(ctx, list)
+}
+
+// AddItem ürünü product servisinden alınan güncel snapshot ile listeye ekler;
+// aynı satır zaten varsa miktarı artırılır
+func (s *wishlistService) AddItem(ctx context.Context, userID, listID string, productID, skuID uint, quantity int) (*model.Wishlist, error) {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	prod, _, _, err := s.products.get(ctx, productID, cache.View{Currency: s.defaultCurrency})
+	if err != nil {
+		return nil, err
+	}
+
+	item := model.WishlistItem{
+		ProductID:      productID,
+		Name:           prod.Name,
+		ImageURL:       prod.ImageUrl,
+		Quantity:       quantity,
+		PriceWhenAdded: prod.Price,
+		Currency:       prod.Currency,
+		AddedAt:        time.Now(),
+	}
+	if len(prod.Variants) > 0 || skuID != 0 {
+		if skuID == 0 {
+			return nil, ErrVariantRequired
+		}
+		variant := findVariant(prod, skuID)
+		if variant == nil {
+			return nil, ErrVariantNotFound
+		}
+
+		item.SKUID = skuID
+		item.SKU = variant.Sku
+		item.Attributes = variant.Attributes
+		item.PriceWhenAdded = variant.Price
+		if variant.ImageUrl != "" {
+			item.ImageURL = variant.ImageUrl
+		}
+	}
+
+	addToList(list, item)
+	if err := s.repo.SaveList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	s.markFlags(ctx, list)
+	return list, nil
+}
+
+func (s *wishlistService) RemoveItem(ctx context.Context, userID, listID string, productID, skuID uint) error {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if !removeFromList(list, productID, skuID) {
+		return ErrListItemNotFound
+	}
+	return s.repo.SaveList(ctx, list)
+}
+
+// MoveFromBasket sepetteki satırı sepetteki fiyatıyla listeye taşır. Önce
+// listeye yazılır, sonra sepetten silinir; arada hata olursa ürün kaybolmaz.
+func (s *wishlistService) MoveFromBasket(ctx context.Context, userID, listID string, productID, skuID uint) (*model.Wishlist, error) {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	basket, err := s.basketRepo.GetBasket(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var line *model.BasketItem
+	for i := range basket.Items {
+		if basket.Items[i].SameLine(productID, skuID) {
+			line = &basket.Items[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, ErrBasketItemNotFound
+	}
+
+	addToList(list, model.WishlistItem{
+		ProductID:      line.ProductID,
+		SKUID:          line.SKUID,
+		SKU:            line.SKU,
+		Attributes:     line.Attributes,
+		Name:           line.Name,
+		ImageURL:       line.ImageURL,
+		Quantity:       line.Quantity,
+		PriceWhenAdded: line.Price,
+		Currency:       basket.Currency,
+		AddedAt:        time.Now(),
+	})
+	if err := s.repo.SaveList(ctx, list); err != nil {
+		return nil, err
+	}
+	if err := s.basketRepo.RemoveItem(ctx, userID, productID, skuID); err != nil {
+		return nil, err
+	}
+
+	s.markFlags(ctx, list)
+	return list, nil
+}
+
+// MoveToBasket liste satırını sepete ekler; fiyat ve stok sepetin normal
+// ekleme akışıyla güncel üründen alınır. Ürün artık yoksa satır listede kalır.
+func (s *wishlistService) MoveToBasket(ctx context.Context, userID, listID string, productID, skuID uint, locale string) (*model.Basket, error) {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var line *model.WishlistItem
+	for i := range list.Items {
+		if list.Items[i].SameLine(productID, skuID) {
+			line = &list.Items[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, ErrListItemNotFound
+	}
+
+	if err := s.basketService.AddItem(ctx, userID, productID, skuID, line.Quantity, AddItemOptions{Locale: locale}); err != nil {
+		return nil, err
+	}
+	removeFromList(list, productID, skuID)
+	if err := s.repo.SaveList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return s.basketService.GetBasket(ctx, userID, GetBasketOptions{})
+}
+
+// ShareList listeye paylaşım token'ı verir; zaten paylaşılmışsa mevcut token korunur
+func (s *wishlistService) ShareList(ctx context.Context, userID, listID string) (*model.Wishlist, error) {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.ShareToken != "" {
+		return list, nil
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetShareToken(ctx, list, token); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *wishlistService) UnshareList(ctx context.Context, userID, listID string) error {
+	list, err := s.getList(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if list.ShareToken == "" {
+		return nil
+	}
+	return s.repo.SetShareToken(ctx, list, "")
+}
+
+// GetSharedList paylaşılan listeyi sahibinin kimliği ve token olmadan döndürür
+func (s *wishlistService) GetSharedList(ctx context.Context, token string) (*model.Wishlist, error) {
+	list, err := s.repo.GetSharedList(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, ErrListNotFound
+	}
+
+	list.UserID = ""
+	list.ShareToken = ""
+	s.markFlags(ctx, list)
+	return list, nil
+}
+
+func (s *wishlistService) getList(ctx context.Context, userID, listID string) (*model.Wishlist, error) {
+	list, err := s.repo.GetList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, ErrListNotFound
+	}
+	return list, nil
+}
+
+// markFlags liste ürünlerinin güncel fiyat ve stok durumunu hesaplar;
+// product servisine ulaşılamazsa bayraklar boş kalır
+func (s *wishlistService) markFlags(ctx context.Context, list *model.Wishlist) {
+	products, gone, err := s.lookup(ctx, list.Items)
+	if err != nil {
+		return
+	}
+	s.applyFlags(list, products, gone)
+}
+
+// lookup satırların ürünlerini, fiyatlar eklendikleri para biriminde
+// karşılaştırılabilsin diye para birimi başına getirir
+func (s *wishlistService) lookup(ctx context.Context, items []model.WishlistItem) (map[string]map[uint]*product.Product, map[uint]bool, error) {
+	idsByCurrency := make(map[string][]uint)
+	for _, item := range items {
+		code := s.itemCurrency(item)
+		idsByCurrency[code] = append(idsByCurrency[code], item.ProductID)
+	}
+
+	products := make(map[string]map[uint]*product.Product, len(idsByCurrency))
+	gone := make(map[uint]bool)
+	for code, ids := range idsByCurrency {
+		found, missing, err := s.products.getMany(ctx, ids, cache.View{Currency: code})
+		if err != nil {
+			return nil, nil, err
+		}
+		products[code] = found
+		for id := range missing {
+			gone[id] = true
+		}
+	}
+	return products, gone, nil
+}
+
+// itemCurrency para birimi kaydedilmemiş eski satırları varsayılan para
+// biriminde sayar
+func (s *wishlistService) itemCurrency(item model.WishlistItem) string {
+	if item.Currency != "" {
+		return item.Currency
+	}
+	return s.defaultCurrency
+}
+
+// applyFlags satırın ürünü (ve SKU'su) hâlâ varsa ve stokta ise available,
+// güncel fiyat eklendiği andakinden düşükse price_dropped işaretlenir
+func (s *wishlistService) applyFlags(list *model.Wishlist, products map[string]map[uint]*product.Product, gone map[uint]bool) {
+	for i := range list.Items {
+		item := &list.Items[i]
+		available := false
+		dropped := false
+
+		prod, ok := products[s.itemCurrency(*item)][item.ProductID]
+		if !ok || gone[item.ProductID] {
+			item.Available = &available
+			item.PriceDropped = &dropped
+			continue
+		}
+
+		price := prod.Price
+		stock := prod.Stock
+		if item.SKUID != 0 {
+			variant := findVariant(prod, item.SKUID)
+			if variant == nil {
+				item.Available = &available
+				item.PriceDropped = &dropped
+				continue
+			}
+			price = variant.Price
+			stock = variant.Stock
+		}
+
+		available = stock > 0
+		dropped = price < item.PriceWhenAdded-priceDropEpsilon
+		item.CurrentPrice = &price
+		item.Available = &available
+		item.PriceDropped = &dropped
+	}
+}
+
+func addToList(list *model.Wishlist, item model.WishlistItem) {
+	for i, existing := range list.Items {
+		if existing.SameLine(item.ProductID, item.SKUID) {
+			// Fiyat düşüşü ilk eklendiği fiyata göre izlenmeye devam eder
+			list.Items[i].Quantity += item.Quantity
+			return
+		}
+	}
+	list.Items = append(list.Items, item)
+}
+
+func removeFromList(list *model.Wishlist, productID, skuID uint) bool {
+	for i, item := range list.Items {
+		if item.SameLine(productID, skuID) {
+			list.Items = append(list.Items[:i], list.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// listSlug liste adından küçük harf, rakam ve tirelerden oluşan bir id üretir
+func listSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
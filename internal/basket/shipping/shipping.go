@@ -0,0 +1,270 @@
+package shipping
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"cluster-iac/internal/problem"
+)
+
+//go:embed default_methods.json
+var defaultMethods []byte
+
+var (
+	ErrMissingCountry     = problem.New(problem.Invalid, "country is required for shipping")
+	ErrMethodNotFound     = problem.New(problem.NotFound, "shipping method not found")
+	ErrMethodNotAvailable = problem.New(problem.Invalid, "shipping method is not available for this basket and destination")
+)
+
+// Rate ağırlık bandıdır: ücretlendirilen ağırlık MaxWeightKg'a kadar olan
+// gönderiler Cost öder; en büyük bandı aşan gönderiler bu yöntemle gönderilemez
+type Rate struct {
+	MaxWeightKg float64 `json:"max_weight_kg"`
+	Cost        float64 `json:"cost"`
+}
+
+type DeliveryEstimate struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Method bir kargo yöntemi ve kurallarıdır. Countries "*" içerirse tüm
+// ülkelere (ExcludeCountries hariç) gönderilir. Sipariş tutarı
+// [MinOrderValue, MaxOrderValue] aralığında olmalıdır (0 sınırsız);
+// FreeShippingThreshold ve üzeri siparişlerde kargo ücretsizdir.
+type Method struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	Carrier               string   `json:"carrier"`
+	Countries             []string `json:"countries"`
+	ExcludeCountries      []string `json:"exclude_countries,omitempty"`
+	Rates                 []Rate   `json:"rates"`
+	MinOrderValue         float64  `json:"min_order_value,omitempty"`
+	MaxOrderValue         float64  `json:"max_order_value,omitempty"`
+	FreeShippingThreshold float64  `json:"free_shipping_threshold,omitempty"`
+	// Hacimsel ağırlık = en*boy*yükseklik (cm) / VolumetricDivisor; 0 ise yalnızca gerçek ağırlık
+	VolumetricDivisor float64          `json:"volumetric_divisor,omitempty"`
+	MaxLengthCm       float64          `json:"max_length_cm,omitempty"`
+	EstimatedDays     DeliveryEstimate `json:"estimated_days"`
+}
+
+func (m Method) shipsTo(country string) bool {
+	for _, excluded := range m.ExcludeCountries {
+		if strings.EqualFold(excluded, country) {
+			return false
+		}
+	}
+	for _, allowed := range m.Countries {
+		if allowed == "*" || strings.EqualFold(allowed, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// Package sepetteki bir satırın birim ölçüleridir; ProductID yalnızca hata
+// mesajları içindir
+type Package struct {
+	ProductID uint
+	WeightKg  float64
+	LengthCm  float64
+	WidthCm   float64
+	HeightCm  float64
+	Quantity  int
+}
+
+// Shipment kargo ücreti hesaplanacak sepet içeriğidir; OrderValue ücretsiz
+// kargo eşiği ve tutar kuralları için kullanılır
+type Shipment struct {
+	Country    string
+	OrderValue float64
+	Packages   []Package
+}
+
+type Option struct {
+	MethodID           string           `json:"method_id"`
+	Name               string           `json:"name"`
+	Carrier            string           `json:"carrier"`
+	Cost               float64          `json:"cost"`
+	Free               bool             `json:"free"`
+	ChargeableWeightKg float64          `json:"chargeable_weight_kg"`
+	EstimatedDays      DeliveryEstimate `json:"estimated_days"`
+	// Ücretsiz kargo için sepete eklenmesi gereken tutar; eşik yoksa ya da aşıldıysa boş
+	AmountToFreeShipping *float64 `json:"amount_to_free_shipping,omitempty"`
+}
+
+// Catalog tanımlı kargo yöntemlerini tutar ve sepet için seçenekleri hesaplar
+type Catalog struct {
+	currency string
+	methods  []Method
+}
+
+// LoadCatalog path boşsa gömülü varsayılan yöntemleri yükler
+func LoadCatalog(path string) (*Catalog, error) {
+	data := defaultMethods
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var file struct {
+		// Ücretler ve tutar eşikleri bu para birimindedir; boşsa TRY
+		Currency string   `json:"currency"`
+		Methods  []Method `json:"methods"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse shipping methods: %w", err)
+	}
+
+	seen := make(map[string]bool, len(file.Methods))
+	for i := range file.Methods {
+		method := &file.Methods[i]
+		if method.ID == "" || seen[method.ID] {
+			return nil, fmt.Errorf("shipping method id %q is empty or duplicated", method.ID)
+		}
+		seen[method.ID] = true
+		if len(method.Rates) == 0 {
+			return nil, fmt.Errorf("shipping method %q has no rates", method.ID)
+		}
+		sort.Slice(method.Rates, func(a, b int) bool {
+			return method.Rates[a].MaxWeightKg < method.Rates[b].MaxWeightKg
+		})
+	}
+	if file.Currency == "" {
+		file.Currency = "TRY"
+	}
+	return &Catalog{currency: strings.ToUpper(file.Currency), methods: file.Methods}, nil
+}
+
+// Currency ücretlerin ve sipariş tutarı eşiklerinin para birimidir
+func (c *Catalog) Currency() string {
+	return c.currency
+}
+
+// Options gönderiye uygun yöntemleri ucuzdan pahalıya döndürür; boş
+// gönderi için seçenek yoktur
+func (c *Catalog) Options(shipment Shipment) ([]Option, error) {
+	country := strings.ToUpper(strings.TrimSpace(shipment.Country))
+	if country == "" {
+		return nil, ErrMissingCountry
+	}
+	if err := validatePackages(shipment.Packages); err != nil {
+		return nil, err
+	}
+
+	options := []Option{}
+	if len(shipment.Packages) == 0 {
+		return options, nil
+	}
+	for _, method := range c.methods {
+		if option, ok := quote(method, country, shipment); ok {
+			options = append(options, option)
+		}
+	}
+	sort.SliceStable(options, func(a, b int) bool {
+		return options[a].Cost < options[b].Cost
+	})
+	return options, nil
+}
+
+// Quote tek bir yöntemin ücretini hesaplar; yöntem bu gönderi için uygun
+// değilse ErrMethodNotAvailable döner
+func (c *Catalog) Quote(methodID string, shipment Shipment) (*Option, error) {
+	country := strings.ToUpper(strings.TrimSpace(shipment.Country))
+	if country == "" {
+		return nil, ErrMissingCountry
+	}
+	if err := validatePackages(shipment.Packages); err != nil {
+		return nil, err
+	}
+
+	for _, method := range c.methods {
+		if method.ID != methodID {
+			continue
+		}
+		option, ok := quote(method, country, shipment)
+		if !ok {
+			return nil, ErrMethodNotAvailable
+		}
+		return &option, nil
+	}
+	return nil, ErrMethodNotFound
+}
+
+// validatePackages ağırlığı olmayan satırları reddeder; aksi halde en ucuz
+// banda düşerlerdi
+func validatePackages(packages []Package) error {
+	var fields []problem.FieldError
+	for _, pkg := range packages {
+		if pkg.WeightKg <= 0 {
+			fields = append(fields, problem.FieldError{
+				Field:   "weight_kg",
+				Code:    "required",
+				Message: fmt.Sprintf("product %d has no weight", pkg.ProductID),
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return problem.NewValidation(fields...)
+}
+
+func quote(method Method, country string, shipment Shipment) (Option, bool) {
+	if len(shipment.Packages) == 0 || !method.shipsTo(country) {
+		return Option{}, false
+	}
+	if shipment.OrderValue < method.MinOrderValue || (method.MaxOrderValue > 0 && shipment.OrderValue > method.MaxOrderValue) {
+		return Option{}, false
+	}
+
+	weight := 0.0
+	for _, pkg := range shipment.Packages {
+		if method.MaxLengthCm > 0 && math.Max(pkg.LengthCm, math.Max(pkg.WidthCm, pkg.HeightCm)) > method.MaxLengthCm {
+			return Option{}, false
+		}
+		unit := pkg.WeightKg
+		if method.VolumetricDivisor > 0 {
+			unit = math.Max(unit, pkg.LengthCm*pkg.WidthCm*pkg.HeightCm/method.VolumetricDivisor)
+		}
+		weight += unit * float64(pkg.Quantity)
+	}
+
+	var cost float64
+	fits := false
+	for _, rate := range method.Rates {
+		if weight <= rate.MaxWeightKg {
+			cost, fits = rate.Cost, true
+			break
+		}
+	}
+	if !fits {
+		return Option{}, false
+	}
+
+	option := Option{
+		MethodID:           method.ID,
+		Name:               method.Name,
+		Carrier:            method.Carrier,
+		Cost:               cost,
+		ChargeableWeightKg: math.Round(weight*1000) / 1000,
+		EstimatedDays:      method.EstimatedDays,
+	}
+	if method.FreeShippingThreshold > 0 {
+		if shipment.OrderValue >= method.FreeShippingThreshold {
+			option.Cost = 0
+			option.Free = true
+		} else {
+			remaining := math.Round((method.FreeShippingThreshold-shipment.OrderValue)*100) / 100
+			option.AmountToFreeShipping = &remaining
+		}
+	}
+	return option, true
+}
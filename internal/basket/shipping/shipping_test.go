@@ -0,0 +1,56 @@
+package shipping
+
+import (
+	"errors"
+	"testing"
+
+	"cluster-iac/internal/problem"
+)
+
+func loadDefaultCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	catalog, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return catalog
+}
+
+func TestOptionsForEmptyShipment(t *testing.T) {
+	catalog := loadDefaultCatalog(t)
+
+	options, err := catalog.Options(Shipment{Country: "TR", OrderValue: 0})
+	if err != nil || options == nil || len(options) != 0 {
+		t.Fatalf("options = %+v (%v), want none", options, err)
+	}
+	if _, err := catalog.Quote("standard-tr", Shipment{Country: "TR"}); !errors.Is(err, ErrMethodNotAvailable) {
+		t.Fatalf("quote for empty shipment = %v, want ErrMethodNotAvailable", err)
+	}
+}
+
+func TestPackagesWithoutWeightAreRejected(t *testing.T) {
+	catalog := loadDefaultCatalog(t)
+	shipment := Shipment{Country: "TR", OrderValue: 500, Packages: []Package{
+		{ProductID: 1, WeightKg: 0.5, Quantity: 1},
+		{ProductID: 2, WeightKg: 0, Quantity: 3},
+	}}
+
+	_, err := catalog.Options(shipment)
+	var typed *problem.Error
+	if !errors.As(err, &typed) || typed.Kind != problem.Validation || len(typed.Fields) != 1 {
+		t.Fatalf("options err = %v, want one validation error", err)
+	}
+	if field := typed.Fields[0]; field.Field != "weight_kg" || field.Message != "product 2 has no weight" {
+		t.Fatalf("field error = %+v", field)
+	}
+	if _, err := catalog.Quote("standard-tr", shipment); !problem.IsKind(err, problem.Validation) {
+		t.Fatalf("quote err = %v, want validation error", err)
+	}
+
+	// Ağırlığı girilince en ucuz bant yerine gerçek ağırlığın bandı kullanılır
+	shipment.Packages[1].WeightKg = 1
+	option, err := catalog.Quote("standard-tr", shipment)
+	if err != nil || option.Cost != 89.9 || option.ChargeableWeightKg != 3.5 {
+		t.Fatalf("quote = %+v (%v)", option, err)
+	}
+}
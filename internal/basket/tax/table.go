@@ -0,0 +1,234 @@
+package tax
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/problem"
+)
+
+//go:embed default_rates.json
+var defaultRates []byte
+
+var ErrMissingCountry = problem.New(problem.Invalid, "country is required for tax calculation")
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+// RoundingLevel "line" her satırın vergisini yuvarlayıp toplar; "total"
+// yuvarlanmamış satır vergilerini toplar ve yalnızca toplamı yuvarlar
+type RoundingLevel string
+
+const (
+	RoundPerLine  RoundingLevel = "line"
+	RoundPerTotal RoundingLevel = "total"
+)
+
+type Rounding struct {
+	Mode  RoundingMode  `json:"mode"`
+	Level RoundingLevel `json:"level"`
+}
+
+type CountryRates struct {
+	// nil ise tablonun genel ayarı geçerlidir
+	PricesIncludeTax *bool                         `json:"prices_include_tax,omitempty"`
+	Rates            map[string]float64            `json:"rates"`
+	Regions          map[string]map[string]float64 `json:"regions,omitempty"`
+}
+
+// RateTable ülke/bölge ve vergi sınıfına göre oranları tutar
+type RateTable struct {
+	PricesIncludeTax bool                    `json:"prices_include_tax"`
+	DefaultClass     string                  `json:"default_class"`
+	Rounding         Rounding                `json:"rounding"`
+	Countries        map[string]CountryRates `json:"countries"`
+}
+
+// LoadRateTable path boşsa gömülü varsayılan tabloyu yükler
+func LoadRateTable(path string) (*RateTable, error) {
+	data := defaultRates
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var table RateTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("parse tax rates: %w", err)
+	}
+	if err := table.normalize(); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// normalize ülke, bölge ve sınıf anahtarlarını büyük/küçük harf farkından
+// bağımsız hale getirir ve ayarları doğrular
+func (t *RateTable) normalize() error {
+	if t.DefaultClass == "" {
+		t.DefaultClass = "standard"
+	}
+	if t.Rounding.Mode == "" {
+		t.Rounding.Mode = RoundHalfUp
+	}
+	if t.Rounding.Level == "" {
+		t.Rounding.Level = RoundPerLine
+	}
+	if t.Rounding.Mode != RoundHalfUp && t.Rounding.Mode != RoundHalfEven {
+		return fmt.Errorf("unknown tax rounding mode %q", t.Rounding.Mode)
+	}
+	if t.Rounding.Level != RoundPerLine && t.Rounding.Level != RoundPerTotal {
+		return fmt.Errorf("unknown tax rounding level %q", t.Rounding.Level)
+	}
+
+	countries := make(map[string]CountryRates, len(t.Countries))
+	for code, country := range t.Countries {
+		rates, err := normalizeRates(code, country.Rates)
+		if err != nil {
+			return err
+		}
+		country.Rates = rates
+
+		regions := make(map[string]map[string]float64, len(country.Regions))
+		for region, regionRates := range country.Regions {
+			if regions[strings.ToUpper(region)], err = normalizeRates(code+"-"+region, regionRates); err != nil {
+				return err
+			}
+		}
+		country.Regions = regions
+		countries[strings.ToUpper(code)] = country
+	}
+	t.Countries = countries
+	return nil
+}
+
+func normalizeRates(where string, rates map[string]float64) (map[string]float64, error) {
+	normalized := make(map[string]float64, len(rates))
+	for class, rate := range rates {
+		if rate < 0 || rate >= 1 || math.IsNaN(rate) {
+			return nil, fmt.Errorf("tax rate %v for %s/%s must be in [0, 1)", rate, where, class)
+		}
+		normalized[strings.ToLower(class)] = rate
+	}
+	return normalized, nil
+}
+
+type tableCalculator struct {
+	table *RateTable
+}
+
+func NewTableCalculator(table *RateTable) TaxCalculator {
+	return &tableCalculator{table: table}
+}
+
+func (c *tableCalculator) Calculate(ctx context.Context, destination Destination, decimals int, lines []Line) (*Result, error) {
+	destination = destination.Normalize()
+	if destination.Country == "" {
+		return nil, ErrMissingCountry
+	}
+	country, ok := c.table.Countries[destination.Country]
+	if !ok {
+		return nil, problem.New(problem.Invalid, fmt.Sprintf("no tax rates are configured for country %q", destination.Country))
+	}
+
+	round := func(amount float64) float64 {
+		return Round(amount, decimals, c.table.Rounding.Mode)
+	}
+
+	inclusive := c.table.PricesIncludeTax
+	if country.PricesIncludeTax != nil {
+		inclusive = *country.PricesIncludeTax
+	}
+
+	result := &Result{
+		Destination:      destination,
+		PricesIncludeTax: inclusive,
+		Lines:            make([]LineTax, len(lines)),
+	}
+	var exactTax, exactAmount float64
+	for i, line := range lines {
+		class := strings.ToLower(line.TaxClass)
+		if class == "" {
+			class = c.table.DefaultClass
+		}
+		rate := c.rate(country, destination.Region, class)
+
+		amount := line.UnitPrice * float64(line.Quantity)
+		var tax float64
+		if inclusive {
+			// Vergi dahil fiyattan vergi payı çıkarılır
+			tax = amount - amount/(1+rate)
+		} else {
+			tax = amount * rate
+		}
+		exactTax += tax
+		exactAmount += amount
+
+		lineTax := LineTax{TaxClass: class, Rate: rate, Tax: round(tax)}
+		if inclusive {
+			lineTax.Gross = round(amount)
+			lineTax.Net = round(lineTax.Gross - lineTax.Tax)
+		} else {
+			lineTax.Net = round(amount)
+			lineTax.Gross = round(lineTax.Net + lineTax.Tax)
+		}
+		result.Lines[i] = lineTax
+
+		if c.table.Rounding.Level == RoundPerLine {
+			result.Tax += lineTax.Tax
+			result.Net += lineTax.Net
+			result.Gross += lineTax.Gross
+		}
+	}
+
+	if c.table.Rounding.Level == RoundPerTotal {
+		result.Tax = exactTax
+		if inclusive {
+			result.Gross = exactAmount
+			result.Net = round(exactAmount) - round(exactTax)
+		} else {
+			result.Net = exactAmount
+			result.Gross = round(exactAmount) + round(exactTax)
+		}
+	}
+	// Float toplamlarındaki küçük sapmalar da temizlenir
+	result.Tax = round(result.Tax)
+	result.Net = round(result.Net)
+	result.Gross = round(result.Gross)
+	return result, nil
+}
+
+// rate önce bölgenin, sonra ülkenin oranına bakar; sınıf tanımlı değilse
+// varsayılan sınıfın oranı kullanılır
+func (c *tableCalculator) rate(country CountryRates, region, class string) float64 {
+	regionRates := country.Regions[region]
+	for _, candidate := range []string{class, c.table.DefaultClass} {
+		if rate, ok := regionRates[candidate]; ok {
+			return rate
+		}
+		if rate, ok := country.Rates[candidate]; ok {
+			return rate
+		}
+	}
+	return 0
+}
+
+// Round tutarı tablonun yuvarlama kipine göre verilen ondalık basamağa yuvarlar
+func Round(amount float64, places int, mode RoundingMode) float64 {
+	if mode == RoundHalfEven {
+		return currency.RoundHalfEven(amount, places)
+	}
+	return currency.Round(amount, places)
+}
@@ -0,0 +1,53 @@
+package tax
+
+import (
+	"context"
+	"strings"
+)
+
+// Destination vergi oranının seçildiği teslimat bölgesidir; Country ISO
+// 3166-1 alpha-2, Region ülkeye göre eyalet/il kodudur
+type Destination struct {
+	Country string `json:"country"`
+	Region  string `json:"region,omitempty"`
+}
+
+func (d Destination) Normalize() Destination {
+	return Destination{
+		Country: strings.ToUpper(strings.TrimSpace(d.Country)),
+		Region:  strings.ToUpper(strings.TrimSpace(d.Region)),
+	}
+}
+
+// Line vergilendirilecek tek bir sepet satırıdır; UnitPrice katalog fiyatıdır
+// ve fiyatların vergi dahil olup olmadığı hesaplayıcıya bağlıdır
+type Line struct {
+	TaxClass  string
+	UnitPrice float64
+	Quantity  int
+}
+
+type LineTax struct {
+	TaxClass string  `json:"tax_class"`
+	Rate     float64 `json:"rate"`
+	Net      float64 `json:"net"`
+	Tax      float64 `json:"tax"`
+	Gross    float64 `json:"gross"`
+}
+
+// Result satır vergilerini girdi sırasıyla ve toplamları içerir
+type Result struct {
+	Destination      Destination `json:"destination"`
+	PricesIncludeTax bool        `json:"prices_include_tax"`
+	Lines            []LineTax   `json:"-"`
+	Net              float64     `json:"net"`
+	Tax              float64     `json:"tax"`
+	Gross            float64     `json:"gross"`
+}
+
+// TaxCalculator sepet satırlarının vergisini hesaplar; harici bir vergi
+// sağlayıcısı bu arayüzle tablo tabanlı hesaplayıcının yerine geçebilir.
+// Tutarlar sepetin para biriminin basamak sayısına (decimals) yuvarlanır.
+type TaxCalculator interface {
+	Calculate(ctx context.Context, destination Destination, decimals int, lines []Line) (*Result, error)
+}
@@ -0,0 +1,50 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock zamana bağlı kararları (TTL, süre dolumu) test edilebilir kılmak için
+// time.Now yerine kullanılır
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fake elle ilerletilen bir saattir
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
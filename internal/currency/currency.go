@@ -0,0 +1,240 @@
+// Package currency kur tablosunu yükler, tutarları para birimleri arasında
+// çevirir ve para biriminin basamak sayısına göre yuvarlar. Product ve basket
+// servisleri aynı kaynağı kullanır.
+package currency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cluster-iac/internal/problem"
+)
+
+// HeaderName istemcinin tercih ettiği para birimidir; currency query
+// parametresi verilmişse o önceliklidir
+const HeaderName = "Accept-Currency"
+
+var codePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ISO 4217'de 2'den farklı basamaklı yaygın para birimleri; tablodaki
+// decimals alanı bunları ezer
+var isoDecimals = map[string]int{
+	"JPY": 0, "KRW": 0, "CLP": 0, "ISK": 0, "VND": 0,
+	"BHD": 3, "KWD": 3, "JOD": 3, "OMR": 3, "TND": 3,
+}
+
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func unsupported(code string) error {
+	return problem.New(problem.Invalid, fmt.Sprintf("currency %q is not supported", code))
+}
+
+// Converter kaynaktan yüklenen son kur tablosunu tutar; Refresh başarısız
+// olursa önceki tablo kullanılmaya devam eder
+type Converter struct {
+	source Source
+
+	mu       sync.RWMutex
+	table    *Table
+	loadedAt time.Time
+}
+
+func NewConverter(ctx context.Context, source Source) (*Converter, error) {
+	c := &Converter{source: source}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Converter) Refresh(ctx context.Context) error {
+	table, err := c.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.table = table
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// RunRefresher kurları interval aralıklarla yeniden yükler; interval 0 ise
+// başlangıçta yüklenen tablo kullanılır
+func (c *Converter) RunRefresher(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				slog.WarnContext(ctx, "failed to refresh exchange rates, keeping previous table", "error", err)
+			}
+		}
+	}
+}
+
+// Rates GET /currencies yanıtıdır
+type Rates struct {
+	Base     string             `json:"base"`
+	Date     string             `json:"date,omitempty"`
+	LoadedAt time.Time          `json:"loaded_at"`
+	Rates    map[string]float64 `json:"rates"`
+	Decimals map[string]int     `json:"decimals"`
+}
+
+func (c *Converter) Rates() Rates {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rates := Rates{
+		Base:     c.table.Base,
+		Date:     c.table.Date,
+		LoadedAt: c.loadedAt,
+		Rates:    make(map[string]float64, len(c.table.Rates)),
+		Decimals: make(map[string]int, len(c.table.Rates)),
+	}
+	for code, rate := range c.table.Rates {
+		rates.Rates[code] = rate
+		rates.Decimals[code] = c.decimals(code)
+	}
+	return rates
+}
+
+// Currencies desteklenen para birimlerini alfabetik sırayla döndürür
+func (c *Converter) Currencies() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	codes := make([]string, 0, len(c.table.Rates))
+	for code := range c.table.Rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func (c *Converter) Supports(code string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.table.Rates[code]
+	return ok
+}
+
+// Decimals para biriminin ondalık basamak sayısıdır
+func (c *Converter) Decimals(code string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.decimals(code)
+}
+
+func (c *Converter) decimals(code string) int {
+	if places, ok := c.table.Decimals[code]; ok {
+		return places
+	}
+	if places, ok := isoDecimals[code]; ok {
+		return places
+	}
+	return 2
+}
+
+// Round tutarı para biriminin basamak sayısına yarım yukarı yuvarlar
+func (c *Converter) Round(amount float64, code string) float64 {
+	return Round(amount, c.Decimals(code))
+}
+
+// Rate from'un 1 biriminin to cinsinden karşılığıdır
+func (c *Converter) Rate(from, to string) (float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	fromRate, ok := c.table.Rates[from]
+	if !ok {
+		return 0, unsupported(from)
+	}
+	toRate, ok := c.table.Rates[to]
+	if !ok {
+		return 0, unsupported(to)
+	}
+	return toRate / fromRate, nil
+}
+
+// Convert tutarı from'dan to'ya çevirir ve to'nun basamak sayısına yuvarlar
+func (c *Converter) Convert(amount float64, from, to string) (float64, error) {
+	if from == to {
+		return c.Round(amount, to), nil
+	}
+
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return c.Round(amount*rate, to), nil
+}
+
+// Negotiate istenen para birimini çözer: currency parametresi verilmişse
+// desteklenmesi gerekir; Accept-Currency virgülle ayrılmış bir liste
+// olabilir ve desteklenen ilk değer seçilir. İkisi de yoksa "" döner.
+func (c *Converter) Negotiate(query, header string) (string, error) {
+	if query != "" {
+		code := Normalize(query)
+		if !c.Supports(code) {
+			return "", unsupported(code)
+		}
+		return code, nil
+	}
+	if strings.TrimSpace(header) == "" {
+		return "", nil
+	}
+
+	for _, part := range strings.Split(header, ",") {
+		code, _, _ := strings.Cut(part, ";")
+		if code = Normalize(code); c.Supports(code) {
+			return code, nil
+		}
+	}
+	return "", unsupported(strings.TrimSpace(header))
+}
+
+// Round tutarı places basamağa yarım yukarı yuvarlar
+func Round(amount float64, places int) float64 {
+	return roundScaled(amount, places, math.Round)
+}
+
+// RoundHalfEven tutarı places basamağa yarım çifte (banker) yuvarlar
+func RoundHalfEven(amount float64, places int) float64 {
+	return roundScaled(amount, places, math.RoundToEven)
+}
+
+// roundScaled tutarı 10^places ile ölçekleyip round ile yuvarlar. Kayan
+// nokta hatalarının (ör. 1.005*100 = 100.49999...) yanlış yöne
+// yuvarlamaması için ölçeklenen değer önce 6 basamağa indirgenir.
+func roundScaled(amount float64, places int, round func(float64) float64) float64 {
+	scale := math.Pow(10, float64(places))
+	scaled, _ := strconv.ParseFloat(strconv.FormatFloat(amount*scale, 'f', 6, 64), 64)
+	return round(scaled) / scale
+}
+
+// ValidCode kodun üç büyük harfli ISO 4217 biçiminde olup olmadığını söyler
+func ValidCode(code string) bool {
+	return codePattern.MatchString(code)
+}
@@ -0,0 +1,115 @@
+package currency
+
+import (
+	"context"
+	"testing"
+
+	"cluster-iac/internal/problem"
+)
+
+func newTestConverter(t *testing.T) *Converter {
+	t.Helper()
+	source := staticSource(`{"base": "EUR", "rates": {"EUR": 1, "TRY": 36, "USD": 1.1, "JPY": 160, "KWD": 0.33, "XAU": 0.0004}, "decimals": {"XAU": 4}}`)
+	c, err := NewConverter(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRound(t *testing.T) {
+	for _, tc := range []struct {
+		amount   float64
+		places   int
+		halfUp   float64
+		halfEven float64
+	}{
+		// 1.005*100 = 100.49999...; ölçeklenen değer 6 basamağa indirgenmese
+		// 1.00'a yuvarlanırdı
+		{1.005, 2, 1.01, 1.00},
+		{2.675, 2, 2.68, 2.68},
+		{0.125, 2, 0.13, 0.12},
+		{-0.125, 2, -0.13, -0.12},
+		{2.5, 0, 3, 2},
+		{1234.5675, 3, 1234.568, 1234.568},
+	} {
+		if got := Round(tc.amount, tc.places); got != tc.halfUp {
+			t.Errorf("Round(%v, %d) = %v, want %v", tc.amount, tc.places, got, tc.halfUp)
+		}
+		if got := RoundHalfEven(tc.amount, tc.places); got != tc.halfEven {
+			t.Errorf("RoundHalfEven(%v, %d) = %v, want %v", tc.amount, tc.places, got, tc.halfEven)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	c := newTestConverter(t)
+	for _, tc := range []struct {
+		amount   float64
+		from, to string
+		want     float64
+	}{
+		{100, "EUR", "TRY", 3600},
+		{3600, "TRY", "EUR", 100},
+		{10, "USD", "TRY", 327.27},
+		{10, "EUR", "JPY", 1600},
+		{9.99, "USD", "JPY", 1453},
+		{10, "EUR", "KWD", 3.3},
+		{1000, "EUR", "XAU", 0.4},
+		// Aynı para biriminde yalnızca yuvarlanır
+		{19.999, "TRY", "TRY", 20},
+	} {
+		got, err := c.Convert(tc.amount, tc.from, tc.to)
+		if err != nil || got != tc.want {
+			t.Errorf("Convert(%v, %s, %s) = %v (%v), want %v", tc.amount, tc.from, tc.to, got, err, tc.want)
+		}
+	}
+
+	if _, err := c.Convert(10, "EUR", "GBP"); !problem.IsKind(err, problem.Invalid) {
+		t.Fatalf("unsupported target = %v, want invalid", err)
+	}
+	if places := c.Decimals("KWD"); places != 3 {
+		t.Fatalf("KWD decimals = %d", places)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	c := newTestConverter(t)
+	for _, tc := range []struct {
+		query, header string
+		want          string
+	}{
+		{"", "", ""},
+		{" usd ", "TRY", "USD"},
+		{"", "usd", "USD"},
+		{"", "CHF, gbp;q=0.9, TRY;q=0.5", "TRY"},
+		{"", "  ", ""},
+	} {
+		got, err := c.Negotiate(tc.query, tc.header)
+		if err != nil || got != tc.want {
+			t.Errorf("Negotiate(%q, %q) = %q (%v), want %q", tc.query, tc.header, got, err, tc.want)
+		}
+	}
+
+	// Parametre verilmişse desteklenmesi gerekir; başlıktaki değerlere düşülmez
+	for _, tc := range []struct{ query, header string }{
+		{"GBP", "TRY"},
+		{"", "CHF, GBP"},
+	} {
+		if _, err := c.Negotiate(tc.query, tc.header); !problem.IsKind(err, problem.Invalid) {
+			t.Errorf("Negotiate(%q, %q) = %v, want invalid", tc.query, tc.header, err)
+		}
+	}
+}
+
+func TestRefreshKeepsPreviousTableOnError(t *testing.T) {
+	c := newTestConverter(t)
+	c.source = fileSource(t.TempDir() + "/missing.json")
+
+	if err := c.Refresh(context.Background()); err == nil {
+		t.Fatal("refresh from a missing file succeeded")
+	}
+	if got, err := c.Convert(1, "EUR", "TRY"); err != nil || got != 36 {
+		t.Fatalf("after failed refresh = %v (%v)", got, err)
+	}
+}
@@ -0,0 +1,133 @@
+package currency
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+//go:embed default_rates.json
+var defaultRates []byte
+
+// Table bir kaynaktan okunan kurlardır. Rates, Base'in 1 biriminin her para
+// birimindeki karşılığıdır; Decimals ISO 4217 basamak sayısını ezmek içindir.
+type Table struct {
+	Base     string             `json:"base"`
+	Date     string             `json:"date,omitempty"`
+	Rates    map[string]float64 `json:"rates"`
+	Decimals map[string]int     `json:"decimals,omitempty"`
+}
+
+// Source kur tablosunu yükler; dosya, HTTP ya da başka bir sağlayıcı olabilir
+type Source interface {
+	Load(ctx context.Context) (*Table, error)
+}
+
+// NewSource location'a göre kaynak seçer: boşsa gömülü tablo, http(s) ile
+// başlıyorsa URL, aksi halde dosya yolu
+func NewSource(location string) Source {
+	switch {
+	case location == "":
+		return staticSource(defaultRates)
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return &httpSource{url: location, client: &http.Client{Timeout: 10 * time.Second}}
+	default:
+		return fileSource(location)
+	}
+}
+
+type staticSource []byte
+
+func (s staticSource) Load(ctx context.Context) (*Table, error) {
+	return parseTable(s)
+}
+
+type fileSource string
+
+func (s fileSource) Load(ctx context.Context) (*Table, error) {
+	data, err := os.ReadFile(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	return parseTable(data)
+}
+
+type httpSource struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSource) Load(ctx context.Context) (*Table, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch exchange rates: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	return parseTable(data)
+}
+
+func parseTable(data []byte) (*Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("invalid exchange rate table: %w", err)
+	}
+	if err := table.normalize(); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (t *Table) normalize() error {
+	t.Base = Normalize(t.Base)
+	if !codePattern.MatchString(t.Base) {
+		return fmt.Errorf("invalid exchange rate table: base currency %q is not an ISO 4217 code", t.Base)
+	}
+
+	rates := make(map[string]float64, len(t.Rates)+1)
+	for code, rate := range t.Rates {
+		code = Normalize(code)
+		if !codePattern.MatchString(code) {
+			return fmt.Errorf("invalid exchange rate table: %q is not an ISO 4217 code", code)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("invalid exchange rate table: rate for %s must be positive", code)
+		}
+		rates[code] = rate
+	}
+	if rate, ok := rates[t.Base]; ok && rate != 1 {
+		return fmt.Errorf("invalid exchange rate table: rate for base currency %s must be 1", t.Base)
+	}
+	rates[t.Base] = 1
+	t.Rates = rates
+
+	decimals := make(map[string]int, len(t.Decimals))
+	for code, places := range t.Decimals {
+		if places < 0 || places > 4 {
+			return fmt.Errorf("invalid exchange rate table: decimals for %s must be between 0 and 4", code)
+		}
+		decimals[Normalize(code)] = places
+	}
+	t.Decimals = decimals
+	return nil
+}
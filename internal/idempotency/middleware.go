@@ -0,0 +1,285 @@
+// Package idempotency Gin servisleri için Idempotency-Key desteği sağlar:
+// aynı anahtarla tekrarlanan yazma istekleri handler'ı yeniden çalıştırmaz,
+// ilk isteğin Redis'te saklanan yanıtı döndürülür.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+type Options struct {
+	// Anahtarlar servis bazında ayrılır (ör. "idempotency:basket:"); istek
+	// context'inde tenant varsa prefix'e tenant da eklenir
+	Prefix string
+	// Tamamlanan yanıtın saklanma süresi
+	TTL time.Duration
+	// İşlenmekte olan isteğin kilidi; süreç çökerse bu süre sonunda anahtar
+	// yeniden kullanılabilir. En uzun istek süresinden büyük olmalı.
+	LockTTL time.Duration
+	// Parmak izi için gövdenin bellekte tutulacağı üst sınır; daha büyük
+	// gövdeler (ör. toplu içe aktarma) geçici dosyaya alınır
+	MaxMemoryBytes int64
+	// Anahtarlı isteklerde okunacak gövdenin üst sınırı; aşan istekler
+	// diske yazılmadan 413 alır. Gövde handler'dan önce okunduğu için
+	// route'ların kendi sınırlarının en büyüğü olmalıdır.
+	MaxBodyBytes int64
+}
+
+type state string
+
+const (
+	stateInProgress state = "in_progress"
+	stateCompleted  state = "completed"
+)
+
+// record Redis'te anahtar başına saklanan JSON'dur
+type record struct {
+	State       state       `json:"state"`
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Yanıtla birlikte saklanmayan başlıklar
+var skippedHeaders = map[string]bool{
+	"Content-Length": true,
+	"Date":           true,
+}
+
+// Middleware POST, PUT, PATCH ve DELETE isteklerinde Idempotency-Key
+// başlığını uygular; başlık yoksa istek olduğu gibi işlenir. Anahtar ilk
+// kez görüldüğünde kilitlenir ve handler çalışır; 5xx olmayan yanıt TTL
+// boyunca saklanıp tekrarlarda aynen döndürülür. Aynı anahtar farklı bir
+// istekle (method, path, query, gövde) gelirse ya da ilk istek hâlâ
+// işleniyorsa 409 döner.
+func Middleware(client redis.Cmdable, opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" || !mutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			writeProblem(c, problem.New(problem.Invalid, "Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		if c.Request.Body != nil && opts.MaxBodyBytes > 0 {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, opts.MaxBodyBytes)
+		}
+		fingerprint, cleanup, err := fingerprintRequest(c.Request, opts.MaxMemoryBytes)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(c, problem.New(problem.TooLarge, fmt.Sprintf("request body exceeds %d bytes", opts.MaxBodyBytes)))
+			return
+		}
+		if err != nil {
+			writeProblem(c, problem.Wrap(problem.Invalid, err))
+			return
+		}
+		defer cleanup()
+
+		ctx := c.Request.Context()
+		redisKey := opts.Prefix + key
+		if id, ok := tenant.FromContext(ctx); ok {
+			redisKey = opts.Prefix + id + ":" + key
+		}
+		acquired, existing, err := acquire(ctx, client, redisKey, fingerprint, opts.LockTTL)
+		if err != nil {
+			writeProblem(c, err)
+			return
+		}
+		if !acquired {
+			respondExisting(c, existing, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Sunucu hataları saklanmaz; istemci aynı anahtarla yeniden deneyebilir.
+		// İstek iptal edilmiş olsa da sonuç yazılmalı, bu yüzden ayrı context.
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if recorder.Status() >= http.StatusInternalServerError {
+			client.Del(storeCtx, redisKey)
+			return
+		}
+
+		header := make(http.Header)
+		for name, values := range recorder.Header() {
+			if !skippedHeaders[name] {
+				header[name] = values
+			}
+		}
+		completed, err := json.Marshal(record{
+			State:       stateCompleted,
+			Fingerprint: fingerprint,
+			Status:      recorder.Status(),
+			Header:      header,
+			Body:        recorder.body.Bytes(),
+		})
+		if err == nil {
+			err = client.Set(storeCtx, redisKey, completed, opts.TTL).Err()
+		}
+		if err != nil {
+			// Kilit LockTTL sonunda düşer; bu arada gelen tekrarlar 409 alır
+			c.Error(err)
+		}
+	}
+}
+
+// acquire anahtarı işlenmekte olarak kilitler; anahtar zaten varsa mevcut
+// kaydı döndürür. Kayıt iki komut arasında süresi dolup silinmişse yeniden
+// dener.
+func acquire(ctx context.Context, client redis.Cmdable, key, fingerprint string, lockTTL time.Duration) (bool, *record, error) {
+	lock, err := json.Marshal(record{State: stateInProgress, Fingerprint: fingerprint})
+	if err != nil {
+		return false, nil, err
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		ok, err := client.SetNX(ctx, key, lock, lockTTL).Result()
+		if err != nil {
+			return false, nil, err
+		}
+		if ok {
+			return true, nil, nil
+		}
+
+		data, err := client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return false, nil, err
+		}
+
+		var existing record
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return false, nil, err
+		}
+		return false, &existing, nil
+	}
+	return false, nil, problem.New(problem.Conflict, "Idempotency-Key could not be locked, retry the request")
+}
+
+func respondExisting(c *gin.Context, existing *record, fingerprint string) {
+	switch {
+	case existing.Fingerprint != fingerprint:
+		writeProblem(c, problem.New(problem.Conflict, "Idempotency-Key has already been used with a different request"))
+	case existing.State != stateCompleted:
+		c.Header("Retry-After", "1")
+		writeProblem(c, problem.New(problem.Conflict, "a request with this Idempotency-Key is still in progress"))
+	default:
+		for name, values := range existing.Header {
+			c.Writer.Header()[name] = values
+		}
+		c.Header(HeaderReplayed, "true")
+		c.Status(existing.Status)
+		_, _ = c.Writer.Write(existing.Body)
+		c.Abort()
+	}
+}
+
+// fingerprintRequest method, path, query ve gövdenin SHA-256 özetini
+// hesaplar ve gövdeyi handler için yeniden okunabilir hale getirir
+func fingerprintRequest(r *http.Request, maxMemory int64) (string, func(), error) {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+
+	noop := func() {}
+	if r.Body == nil || r.Body == http.NoBody {
+		return hex.EncodeToString(hash.Sum(nil)), noop, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxMemory+1))
+	if err != nil {
+		return "", noop, err
+	}
+	if int64(len(data)) <= maxMemory {
+		hash.Write(data)
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		return hex.EncodeToString(hash.Sum(nil)), noop, nil
+	}
+
+	file, err := os.CreateTemp("", "idempotency-body-*")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+
+	writer := io.MultiWriter(file, hash)
+	if _, err := writer.Write(data); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if _, err := io.Copy(writer, r.Body); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+
+	r.Body = io.NopCloser(file)
+	return hex.EncodeToString(hash.Sum(nil)), cleanup, nil
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func writeProblem(c *gin.Context, err error) {
+	problem.Write(c.Writer, c.Request, err)
+	c.Abort()
+}
+
+// responseRecorder yanıtı istemciye yazarken bir kopyasını saklar
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
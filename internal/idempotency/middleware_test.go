@@ -0,0 +1,168 @@
+package idempotency
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+type idempotencyFixture struct {
+	router *gin.Engine
+	calls  atomic.Int32
+	// entered/release ayarlanırsa handler girişte bildirir ve release'i bekler
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newIdempotencyFixture(t *testing.T, opts Options) *idempotencyFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	f := &idempotencyFixture{router: gin.New()}
+	f.router.Use(Middleware(client, opts))
+	f.router.POST("/items", func(c *gin.Context) {
+		n := f.calls.Add(1)
+		if f.entered != nil {
+			f.entered <- struct{}{}
+			<-f.release
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Header("X-Call", strconv.Itoa(int(n)))
+		c.String(http.StatusCreated, "created %d bytes", len(body))
+	})
+	return f
+}
+
+var testOptions = Options{
+	Prefix:         "idempotency:test:",
+	TTL:            time.Hour,
+	LockTTL:        time.Minute,
+	MaxMemoryBytes: 16,
+	MaxBodyBytes:   1024,
+}
+
+func (f *idempotencyFixture) post(key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReplaysCompletedResponse(t *testing.T) {
+	f := newIdempotencyFixture(t, testOptions)
+
+	first := f.post("k1", `{"quantity":1}`)
+	if first.Code != http.StatusCreated || first.Header().Get(HeaderReplayed) != "" {
+		t.Fatalf("first = %d %v", first.Code, first.Header())
+	}
+
+	again := f.post("k1", `{"quantity":1}`)
+	if again.Code != http.StatusCreated || again.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", again.Code, again.Body.String(), first.Code, first.Body.String())
+	}
+	if again.Header().Get(HeaderReplayed) != "true" || again.Header().Get("X-Call") != "1" {
+		t.Fatalf("replay headers = %v", again.Header())
+	}
+	if calls := f.calls.Load(); calls != 1 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+
+	// Anahtarsız istek her seferinde çalışır
+	f.post("", `{"quantity":1}`)
+	f.post("", `{"quantity":1}`)
+	if calls := f.calls.Load(); calls != 3 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+}
+
+func TestRejectsKeyReusedWithDifferentPayload(t *testing.T) {
+	f := newIdempotencyFixture(t, testOptions)
+	f.post("k1", `{"quantity":1}`)
+
+	// Bellekte tutulan ve geçici dosyaya alınan gövdeler aynı şekilde karşılaştırılır
+	for _, body := range []string{`{"quantity":2}`, strings.Repeat("x", 100)} {
+		rec := f.post("k1", body)
+		if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "different request") {
+			t.Fatalf("reuse with %q = %d %s", body, rec.Code, rec.Body.String())
+		}
+	}
+	if calls := f.calls.Load(); calls != 1 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+}
+
+func TestSpooledBodyReachesHandler(t *testing.T) {
+	f := newIdempotencyFixture(t, testOptions)
+	body := strings.Repeat("x", 500)
+
+	first := f.post("big", body)
+	if first.Code != http.StatusCreated || first.Body.String() != "created 500 bytes" {
+		t.Fatalf("first = %d %q", first.Code, first.Body.String())
+	}
+	if again := f.post("big", body); again.Header().Get(HeaderReplayed) != "true" {
+		t.Fatalf("replay = %d %v", again.Code, again.Header())
+	}
+}
+
+func TestRejectsRequestWhileFirstIsInFlight(t *testing.T) {
+	f := newIdempotencyFixture(t, testOptions)
+	f.entered = make(chan struct{})
+	f.release = make(chan struct{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- f.post("k1", `{"quantity":1}`) }()
+	<-f.entered
+
+	rec := f.post("k1", `{"quantity":1}`)
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") != "1" || !strings.Contains(rec.Body.String(), "in progress") {
+		t.Fatalf("concurrent retry = %d %v %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+
+	close(f.release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first = %d", first.Code)
+	}
+	f.entered = nil
+	if again := f.post("k1", `{"quantity":1}`); again.Header().Get(HeaderReplayed) != "true" {
+		t.Fatalf("retry after completion = %d %v", again.Code, again.Header())
+	}
+	if calls := f.calls.Load(); calls != 1 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+}
+
+func TestRejectsOversizedBodyBeforeSpooling(t *testing.T) {
+	f := newIdempotencyFixture(t, testOptions)
+
+	rec := f.post("huge", strings.Repeat("x", 2000))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body = %d %s", rec.Code, rec.Body.String())
+	}
+	if calls := f.calls.Load(); calls != 0 {
+		t.Fatalf("handler ran %d times", calls)
+	}
+
+	// Reddedilen istek anahtarı kilitlemez
+	if rec := f.post("huge", "small"); rec.Code != http.StatusCreated {
+		t.Fatalf("retry with a smaller body = %d %s", rec.Code, rec.Body.String())
+	}
+}
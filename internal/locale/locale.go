@@ -0,0 +1,217 @@
+// Package locale dil etiketlerini normalize eder, Accept-Language başlığını
+// çözer ve bir isteğin hangi dillerde, hangi sırayla karşılanacağını
+// (fallback zinciri) belirler.
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cluster-iac/internal/problem"
+)
+
+// HeaderName istemcinin tercih ettiği dillerdir; locale query parametresi
+// verilmişse o önceliklidir
+const HeaderName = "Accept-Language"
+
+// dil[-Script][-BÖLGE], ör. tr, en-GB, zh-Hant-TW, es-419
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// Normalize etiketi BCP 47 yazımına getirir: dil küçük, script baş harfi
+// büyük, bölge büyük harf; "_" ayırıcı olarak kabul edilir
+func Normalize(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToUpper(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Valid etiketin desteklenen biçimde olup olmadığını söyler
+func Valid(tag string) bool {
+	return tagPattern.MatchString(tag)
+}
+
+// parent etiketin son alt etiketini atar; en-GB -> en, en -> ""
+func parent(tag string) string {
+	if i := strings.LastIndex(tag, "-"); i > 0 {
+		return tag[:i]
+	}
+	return ""
+}
+
+// ParseAcceptLanguage başlıktaki dilleri q değerine göre (eşitlerde yazılış
+// sırasıyla) döndürür; q=0, "*" ve geçersiz etiketler atlanır
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = Normalize(tag)
+		if !Valid(tag) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// Chain bir isteğin denenecek dilleridir; son eleman her zaman katalog
+// varsayılan dilidir
+type Chain []string
+
+// Primary en çok tercih edilen desteklenen dildir
+func (c Chain) Primary() string {
+	return c[0]
+}
+
+// Resolver katalogun varsayılan dilini, desteklenen dilleri ve açıkça
+// tanımlanmış fallback'leri (ör. de-AT -> de-DE) tutar
+type Resolver struct {
+	defaultLocale string
+	supported     []string
+	fallbacks     map[string]string
+}
+
+// NewResolver supported listesine varsayılan dili de ekler; fallbacks
+// "de-AT:de-DE,pt-BR:pt-PT" biçimindedir
+func NewResolver(defaultLocale string, supported []string, fallbacks string) (*Resolver, error) {
+	r := &Resolver{defaultLocale: Normalize(defaultLocale), fallbacks: make(map[string]string)}
+	if !Valid(r.defaultLocale) {
+		return nil, fmt.Errorf("invalid default locale %q", defaultLocale)
+	}
+
+	seen := map[string]bool{r.defaultLocale: true}
+	r.supported = []string{r.defaultLocale}
+	for _, tag := range supported {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		tag = Normalize(tag)
+		if !Valid(tag) {
+			return nil, fmt.Errorf("invalid locale %q", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			r.supported = append(r.supported, tag)
+		}
+	}
+
+	for _, pair := range strings.Split(fallbacks, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, ":")
+		from, to = Normalize(from), Normalize(to)
+		if !ok || !Valid(from) || !Valid(to) || from == to {
+			return nil, fmt.Errorf("invalid locale fallback %q", pair)
+		}
+		r.fallbacks[from] = to
+	}
+	return r, nil
+}
+
+func (r *Resolver) Default() string {
+	return r.defaultLocale
+}
+
+// Supported varsayılan dil başta olmak üzere desteklenen dillerdir
+func (r *Resolver) Supported() []string {
+	return append([]string(nil), r.supported...)
+}
+
+func (r *Resolver) Supports(tag string) bool {
+	for _, supported := range r.supported {
+		if supported == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Check etiketi normalize eder ve desteklenmiyorsa Invalid hatası döndürür
+func (r *Resolver) Check(tag string) (string, error) {
+	normalized := Normalize(tag)
+	if !r.Supports(normalized) {
+		return "", problem.New(problem.Invalid, fmt.Sprintf("locale %q is not supported", tag))
+	}
+	return normalized, nil
+}
+
+// Resolve istenen dilleri fallback zincirine çevirir. locale parametresi
+// verilmişse desteklenmesi gerekir ve başlık yok sayılır; Accept-Language
+// yalnızca tercihtir, desteklenmeyen diller atlanır. Her dil için önce
+// tanımlı fallback'ler, sonra üst etiketler denenir (de-AT -> de-DE -> de);
+// zincir varsayılan dille biter.
+func (r *Resolver) Resolve(query, header string) (Chain, error) {
+	var requested []string
+	if query != "" {
+		tag, err := r.Check(query)
+		if err != nil {
+			return nil, err
+		}
+		requested = []string{tag}
+	} else {
+		requested = ParseAcceptLanguage(header)
+	}
+
+	var chain Chain
+	seen := make(map[string]bool)
+	for _, tag := range requested {
+		chain = r.expand(tag, chain, seen)
+	}
+	if !seen[r.defaultLocale] {
+		chain = append(chain, r.defaultLocale)
+	}
+	return chain, nil
+}
+
+// expand etiketi, tanımlı fallback'lerini ve üst etiketlerini zincire
+// ekler; seen döngüsel fallback tanımlarında da sonlanmayı sağlar
+func (r *Resolver) expand(tag string, chain Chain, seen map[string]bool) Chain {
+	for ; tag != ""; tag = parent(tag) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		if r.Supports(tag) {
+			chain = append(chain, tag)
+		}
+		if to, ok := r.fallbacks[tag]; ok {
+			chain = r.expand(to, chain, seen)
+		}
+	}
+	return chain
+}
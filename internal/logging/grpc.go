@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor context'teki istek kimliğini giden çağrının metadata'sına yazar
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoing(ctx context.Context) context.Context {
+	if id := RequestID(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, id)
+	}
+	return ctx
+}
+
+// UnaryServerInterceptor çağrının istek kimliğini metadata'dan alır (yoksa
+// üretir), context'e koyar ve çağrı bitince bir satır yazar
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = incoming(ctx)
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor stream'ler için satırı stream kapanınca yazar
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := incoming(stream.Context())
+		slog.DebugContext(ctx, "grpc stream opened", "method", info.FullMethod)
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func incoming(ctx context.Context) context.Context {
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey); len(values) > 0 {
+		id = values[0]
+	}
+	return WithRequestID(ctx, EnsureRequestID(id))
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	}
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		attrs = append(attrs, "error", err)
+		level = slog.LevelError
+	default:
+		attrs = append(attrs, "error", err)
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "grpc call", attrs...)
+}
+
+// contextStream handler'a istek kimliği eklenmiş context'i verir
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
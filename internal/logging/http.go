@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"cluster-iac/internal/problem"
+)
+
+// Middleware isteğin kimliğini X-Request-ID başlığından alır (yoksa üretir),
+// context'e ve yanıt başlığına koyar ve istek bitince bir erişim satırı
+// yazar. Başlıklar yalnızca DEBUG seviyesinde, hassas olanlar gizlenerek
+// loglanır. Handler'daki panic'ler loglanıp 500 olarak yanıtlanır.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := EnsureRequestID(r.Header.Get(RequestIDHeader))
+		r.Header.Set(RequestIDHeader, id)
+		w.Header().Set(RequestIDHeader, id)
+		r = r.WithContext(WithRequestID(r.Context(), id))
+
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				slog.ErrorContext(r.Context(), "panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+				if rec.status == 0 {
+					problem.Write(rec, r, problem.New(problem.Internal, "an unexpected error occurred"))
+				}
+			}
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			attrs := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"bytes", rec.bytes,
+				"remote_addr", r.RemoteAddr,
+			}
+			if slog.Default().Enabled(r.Context(), slog.LevelDebug) {
+				attrs = append(attrs, "headers", RedactHeaders(r.Header))
+			}
+			slog.Log(r.Context(), accessLevel(status), "request", attrs...)
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// accessLevel 5xx yanıtları ERROR, 4xx yanıtları WARN seviyesinde loglar
+func accessLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// statusRecorder yanıtın durum kodunu ve boyutunu erişim satırı için tutar
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush dışa aktarım gibi akış yanıtlarının tamponlanmaması içindir
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,163 @@
+// Package logging servislerin ortak JSON log kurulumudur. Log satırları
+// log/slog ile stdout'a yazılır; context'te istek kimliği ya da tenant varsa
+// her satıra eklenir. Seviye çalışırken admin uç noktasından değiştirilebilir.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/tenant"
+)
+
+const (
+	// RequestIDHeader gateway'den servislere iletilen istek kimliği başlığıdır
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMetadataKey gRPC çağrılarında istek kimliğini taşıyan metadata anahtarıdır
+	RequestIDMetadataKey = "x-request-id"
+)
+
+// Setup varsayılan logger'ı service alanlı bir JSON logger yapar ve seviyeyi
+// değiştirmek için kullanılan LevelVar'ı döndürür. log paketiyle yazılan
+// satırlar da aynı handler'dan INFO seviyesinde geçer.
+func Setup(service string, level slog.Level) *slog.LevelVar {
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(level)
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: levelVar})
+	slog.SetDefault(slog.New(contextHandler{handler}).With("service", service))
+	return levelVar
+}
+
+// ParseLevel "debug", "info", "warn" ya da "error" değerini çözer; boş değer info'dur
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if strings.TrimSpace(value) == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", value)
+	}
+	return level, nil
+}
+
+// contextHandler istek kimliğini ve tenant'ı context'ten satıra ekler
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if id, ok := tenant.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("tenant", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID context'teki istek kimliğini, yoksa boş string döndürür
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Başlıkta ve log satırlarında güvenle taşınabilen kimlikler
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// EnsureRequestID gelen kimliği geçerliyse olduğu gibi kullanır, değilse
+// yeni bir kimlik üretir
+func EnsureRequestID(value string) string {
+	if requestIDPattern.MatchString(value) {
+		return value
+	}
+	return NewRequestID()
+}
+
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Değeri loglanmayan başlıklar
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+	"x-csrf-token":        true,
+}
+
+// RedactHeaders başlıkları log'a yazılacak biçime getirir; hassas başlıkların
+// değeri "[REDACTED]" olur
+func RedactHeaders(headers map[string][]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		if sensitiveHeaders[strings.ToLower(name)] {
+			redacted[name] = "[REDACTED]"
+			continue
+		}
+		redacted[name] = strings.Join(values, ", ")
+	}
+	return redacted
+}
+
+// LevelHandler log seviyesini okur (GET) ve değiştirir (PUT {"level": "debug"});
+// değişiklik yeniden başlatmaya kadar geçerlidir
+func LevelHandler(levelVar *slog.LevelVar) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req struct {
+				Level string `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Level == "" {
+				problem.Write(w, r, problem.New(problem.Invalid, `body must be {"level": "debug|info|warn|error"}`))
+				return
+			}
+			level, err := ParseLevel(req.Level)
+			if err != nil {
+				problem.Write(w, r, problem.Wrap(problem.Invalid, err))
+				return
+			}
+			if previous := levelVar.Level(); previous != level {
+				levelVar.Set(level)
+				slog.WarnContext(r.Context(), "log level changed", "from", previous.String(), "to", level.String())
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"level": levelVar.Level().String()})
+	})
+}
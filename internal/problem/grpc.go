@@ -0,0 +1,122 @@
+package problem
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var kindCode = map[Kind]codes.Code{
+	Invalid:              codes.InvalidArgument,
+	Validation:           codes.InvalidArgument,
+	NotFound:             codes.NotFound,
+	Gone:                 codes.NotFound,
+	Conflict:             codes.FailedPrecondition,
+	Duplicate:            codes.AlreadyExists,
+	PreconditionFailed:   codes.Aborted,
+	PreconditionRequired: codes.FailedPrecondition,
+	TooLarge:             codes.InvalidArgument,
+	UnsupportedMedia:     codes.InvalidArgument,
+	TooManyRequests:      codes.ResourceExhausted,
+	Unavailable:          codes.Unavailable,
+	Timeout:              codes.DeadlineExceeded,
+	BadGateway:           codes.Unavailable,
+	Internal:             codes.Internal,
+}
+
+// GRPCCode kind'ın gRPC karşılığını döndürür
+func (k Kind) GRPCCode() codes.Code {
+	if code, ok := kindCode[k]; ok {
+		return code
+	}
+	return codes.Internal
+}
+
+// ToGRPC err'i gRPC status hatasına çevirir. Validation ihlalleri
+// google.rpc.BadRequest detayı olarak eklenir; fieldPrefix alan adlarını
+// istek mesajındaki yola çevirir (ör. "product.").
+func ToGRPC(err error, fieldPrefix string) error {
+	p := From(err)
+	st := status.New(KindOf(err).GRPCCode(), p.Detail)
+	if len(p.Errors) == 0 {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range p.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldPrefix + field.Field,
+			Description: field.Message,
+		})
+	}
+
+	detailed, detailErr := st.WithDetails(badRequest)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// FromGRPC başka bir servisten dönen gRPC hatasını tipli hataya çevirir;
+// BadRequest detayları alan hatalarına dönüşür
+func FromGRPC(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	kind := Internal
+	switch st.Code() {
+	case codes.InvalidArgument:
+		kind = Invalid
+	case codes.NotFound:
+		kind = NotFound
+	case codes.AlreadyExists:
+		kind = Duplicate
+	case codes.FailedPrecondition:
+		kind = Conflict
+	case codes.Aborted:
+		kind = PreconditionFailed
+	case codes.ResourceExhausted:
+		kind = TooManyRequests
+	case codes.Unavailable:
+		kind = Unavailable
+	case codes.DeadlineExceeded, codes.Canceled:
+		kind = Timeout
+	}
+
+	typed := &Error{Kind: kind, Detail: st.Message(), Err: err}
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		typed.Kind = Validation
+		for _, violation := range badRequest.FieldViolations {
+			typed.Fields = append(typed.Fields, FieldError{
+				Field:   violation.Field,
+				Code:    "invalid",
+				Message: violation.Description,
+			})
+		}
+	}
+
+	// Karşı servisin iç hata ve bağlantı mesajları istemciye aynen aktarılmaz
+	switch kind {
+	case Unavailable:
+		typed.Detail = "upstream service is unavailable"
+	case Timeout:
+		typed.Detail = "upstream service did not respond in time"
+	case Internal:
+		typed.Detail = "upstream service error"
+	}
+	return typed
+}
+
+// IsKind err zincirinde verilen kind'da tipli bir hata olup olmadığını söyler
+func IsKind(err error, kind Kind) bool {
+	var typed *Error
+	return errors.As(err, &typed) && typed.Kind == kind
+}
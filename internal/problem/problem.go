@@ -0,0 +1,216 @@
+// Package problem servisler arasında ortak hata modelini tanımlar: servis
+// katmanları tipli hatalar (Kind) döndürür, HTTP tarafında bunlar RFC 7807
+// application/problem+json gövdesine, gRPC tarafında status kodlarına çevrilir.
+package problem
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ContentType = "application/problem+json"
+
+// Kind hatanın sınıfıdır; problem type URI'sinin son parçası olarak da kullanılır
+type Kind string
+
+const (
+	Invalid              Kind = "invalid"
+	Validation           Kind = "validation"
+	NotFound             Kind = "not-found"
+	Gone                 Kind = "gone"
+	Conflict             Kind = "conflict"
+	Duplicate            Kind = "duplicate"
+	PreconditionFailed   Kind = "precondition-failed"
+	PreconditionRequired Kind = "precondition-required"
+	TooLarge             Kind = "too-large"
+	UnsupportedMedia     Kind = "unsupported-media-type"
+	TooManyRequests      Kind = "too-many-requests"
+	Unavailable          Kind = "unavailable"
+	Timeout              Kind = "timeout"
+	BadGateway           Kind = "bad-gateway"
+	Internal             Kind = "internal"
+)
+
+var kindStatus = map[Kind]int{
+	Invalid:              http.StatusBadRequest,
+	Validation:           http.StatusUnprocessableEntity,
+	NotFound:             http.StatusNotFound,
+	Gone:                 http.StatusGone,
+	Conflict:             http.StatusConflict,
+	Duplicate:            http.StatusConflict,
+	PreconditionFailed:   http.StatusPreconditionFailed,
+	PreconditionRequired: http.StatusPreconditionRequired,
+	TooLarge:             http.StatusRequestEntityTooLarge,
+	UnsupportedMedia:     http.StatusUnsupportedMediaType,
+	TooManyRequests:      http.StatusTooManyRequests,
+	Unavailable:          http.StatusServiceUnavailable,
+	Timeout:              http.StatusGatewayTimeout,
+	BadGateway:           http.StatusBadGateway,
+	Internal:             http.StatusInternalServerError,
+}
+
+// HTTPStatus kind'ın HTTP karşılığını döndürür; bilinmeyen kind 500'dür
+func (k Kind) HTTPStatus() int {
+	if code, ok := kindStatus[k]; ok {
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+// TypeURI problem gövdesindeki "type" alanıdır
+func (k Kind) TypeURI() string {
+	return "urn:cluster-iac:problem:" + string(k)
+}
+
+// FieldError tek bir alanın ihlal ettiği kuralı taşır; Field JSON alan adıdır
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error servis katmanlarının döndürdüğü tipli hatadır. Sentinel olarak
+// tanımlandığında errors.Is pointer eşitliğiyle çalışır; fmt.Errorf("%w")
+// ile sarıldığında kind korunur.
+type Error struct {
+	Kind   Kind
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+func New(kind Kind, detail string) *Error {
+	return &Error{Kind: kind, Detail: detail}
+}
+
+// Wrap err'i verilen kind ile sınıflandırır; detail err'in mesajıdır
+func Wrap(kind Kind, err error) *Error {
+	return &Error{Kind: kind, Detail: err.Error(), Err: err}
+}
+
+// NewValidation alan ihlallerini tek bir Validation hatasında toplar
+func NewValidation(fields ...FieldError) *Error {
+	return &Error{Kind: Validation, Detail: "validation failed", Fields: fields}
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Detail
+	}
+
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return e.Detail + ": " + strings.Join(messages, "; ")
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf err zincirindeki tipli hatanın kind'ını döndürür. Tipsiz hatalardan
+// bağlantı ve zaman aşımı hataları Unavailable/Timeout, geri kalanı Internal
+// sayılır.
+func KindOf(err error) Kind {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed.Kind
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return Unavailable
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return Unavailable
+	case codes.DeadlineExceeded:
+		return Timeout
+	}
+	return Internal
+}
+
+// Problem RFC 7807 gövdesidir; Errors validation ihlallerini taşıyan uzantıdır
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// For kind için detail'li bir problem oluşturur
+func For(kind Kind, detail string) Problem {
+	return Problem{
+		Type:   kind.TypeURI(),
+		Title:  http.StatusText(kind.HTTPStatus()),
+		Status: kind.HTTPStatus(),
+		Detail: detail,
+	}
+}
+
+// ForStatus kind'ı olmayan durumlar (ör. framework'ün kendi 404/405
+// yanıtları) için RFC 7807'deki "about:blank" tipiyle problem oluşturur
+func ForStatus(status int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// From err'i probleme çevirir. Tipsiz hataların mesajı iç detay sızdırmaması
+// için istemciye gönderilmez.
+func From(err error) Problem {
+	kind := KindOf(err)
+
+	var typed *Error
+	if errors.As(err, &typed) {
+		p := For(kind, err.Error())
+		if len(typed.Fields) > 0 {
+			p.Detail = typed.Detail
+			p.Errors = typed.Fields
+		}
+		return p
+	}
+
+	switch kind {
+	case Unavailable:
+		return For(kind, "a dependency is temporarily unavailable")
+	case Timeout:
+		return For(kind, "a dependency did not respond in time")
+	default:
+		return For(Internal, "an unexpected error occurred")
+	}
+}
+
+// Write err'i problem+json olarak yazar; instance isteğin path'idir.
+// Tipli olmayan hatalar loglanır.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := From(err)
+	if p.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
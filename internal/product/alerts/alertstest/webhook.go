@@ -0,0 +1,65 @@
+// Package alertstest stok uyarısı gönderimini test etmek için yardımcılardır
+package alertstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"cluster-iac/internal/product/alerts"
+)
+
+// WebhookStub gelen uyarıları kaydeden yerel bir HTTP sunucusudur; webhook
+// gönderimini gerçek bir uç nokta olmadan test etmek için kullanılır
+type WebhookStub struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	alerts []alerts.Alert
+	status int
+}
+
+func NewWebhookStub() *WebhookStub {
+	stub := &WebhookStub{status: http.StatusNoContent}
+	stub.server = httptest.NewServer(http.HandlerFunc(stub.handle))
+	return stub
+}
+
+func (s *WebhookStub) URL() string {
+	return s.server.URL
+}
+
+// SetStatus sonraki isteklere dönülecek status kodunu ayarlar (hata senaryoları için)
+func (s *WebhookStub) SetStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *WebhookStub) Alerts() []alerts.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]alerts.Alert(nil), s.alerts...)
+}
+
+func (s *WebhookStub) Close() {
+	s.server.Close()
+}
+
+func (s *WebhookStub) handle(w http.ResponseWriter, r *http.Request) {
+	var alert alerts.Alert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	status := s.status
+	if status < 300 {
+		s.alerts = append(s.alerts, alert)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(status)
+}
@@ -0,0 +1,92 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cluster-iac/internal/product/events"
+)
+
+type Alert struct {
+	Type        events.EventType `json:"type"`
+	Tenant      string           `json:"tenant"`
+	ProductID   uint             `json:"product_id"`
+	ProductName string           `json:"product_name"`
+	Stock       int              `json:"stock"`
+	Threshold   int              `json:"reorder_threshold"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+}
+
+// Notifier stok uyarılarını dış bir kanala iletir
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	slog.WarnContext(ctx, "stock alert",
+		"type", alert.Type, "product_id", alert.ProductID, "product_name", alert.ProductName,
+		"stock", alert.Stock, "threshold", alert.Threshold)
+	return nil
+}
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiNotifier uyarıyı tüm notifier'lara gönderir; biri başarısız olsa da
+// diğerleri denenir
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
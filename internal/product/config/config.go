@@ -2,6 +2,10 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +18,50 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	ServerPort string
+	// Çöp kutusundaki ürünlerin kalıcı silinmeden önce tutulacağı süre
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+	// Boş bırakılırsa stok uyarıları yalnızca loglanır
+	StockAlertWebhookURL    string
+	StockAlertSweepInterval time.Duration
+	// Planlı fiyat değişikliklerinin kontrol edilme sıklığı
+	PriceSchedulerInterval time.Duration
+	// Toplu içe aktarma dosyalarının işlenene kadar tutulduğu dizin
+	ImportDir       string
+	ImportBatchSize int
+	ImportMaxBytes  int64
+	// Ürün görselleri: BlobStore "local" ya da "s3"
+	BlobStore           string
+	MediaDir            string
+	MediaBaseURL        string
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	S3PublicURL         string
+	ImageMaxBytes       int64
+	ImageMaxPixels      int
+	ImageMaxPerProduct  int
+	ThumbnailSize       int
+	ImageWorkerInterval time.Duration
+	// Idempotency-Key yanıtları için Redis; RedisAddr boşsa devre dışı
+	RedisAddr          string
+	RedisPassword      string
+	IdempotencyTTL     time.Duration
+	IdempotencyLockTTL time.Duration
+	// Kur kaynağı: boşsa gömülü tablo, http(s) URL ya da dosya yolu
+	ExchangeRatesSource          string
+	ExchangeRatesRefreshInterval time.Duration
+	// Name/Description varsayılan dildedir; diğer desteklenen diller için
+	// çeviri tutulur. Fallbacks "de-AT:de-DE" biçiminde virgülle ayrılır.
+	DefaultLocale    string
+	SupportedLocales []string
+	LocaleFallbacks  string
+	// "default" tenant'ına ek olarak sunulan tenant'lar, virgülle ayrılır
+	Tenants string
+	// Başlangıç log seviyesi; çalışırken /admin/log-level ile değiştirilebilir
+	LogLevel string
 }
 
 func LoadConfig() (*Config, error) {
@@ -28,5 +76,68 @@ func LoadConfig() (*Config, error) {
 		DBName:     os.Getenv("DB_NAME"),
 		DBSSLMode:  os.Getenv("DB_SSLMODE"),
 		ServerPort: os.Getenv("SERVER_PORT"),
+
+		TrashRetention:     getEnvDuration("PRODUCT_TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvDuration("PRODUCT_TRASH_PURGE_INTERVAL", time.Hour),
+
+		StockAlertWebhookURL:    os.Getenv("STOCK_ALERT_WEBHOOK_URL"),
+		StockAlertSweepInterval: getEnvDuration("STOCK_ALERT_SWEEP_INTERVAL", 5*time.Minute),
+
+		PriceSchedulerInterval: getEnvDuration("PRICE_SCHEDULER_INTERVAL", time.Minute),
+
+		ImportDir:       getEnv("IMPORT_DIR", filepath.Join(os.TempDir(), "product-imports")),
+		ImportBatchSize: getEnvInt("IMPORT_BATCH_SIZE", 500),
+		ImportMaxBytes:  int64(getEnvInt("IMPORT_MAX_BYTES", 50<<20)),
+
+		BlobStore:           getEnv("BLOB_STORE", "local"),
+		MediaDir:            getEnv("MEDIA_DIR", "data/media"),
+		MediaBaseURL:        getEnv("MEDIA_BASE_URL", "http://localhost:8082/media"),
+		S3Endpoint:          os.Getenv("S3_ENDPOINT"),
+		S3Region:            getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:            os.Getenv("S3_BUCKET"),
+		S3AccessKey:         os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:         os.Getenv("S3_SECRET_KEY"),
+		S3PublicURL:         os.Getenv("S3_PUBLIC_URL"),
+		ImageMaxBytes:       int64(getEnvInt("IMAGE_MAX_BYTES", 10<<20)),
+		ImageMaxPixels:      getEnvInt("IMAGE_MAX_PIXELS", 40_000_000),
+		ImageMaxPerProduct:  getEnvInt("IMAGE_MAX_PER_PRODUCT", 20),
+		ThumbnailSize:       getEnvInt("THUMBNAIL_SIZE", 320),
+		ImageWorkerInterval: getEnvDuration("IMAGE_WORKER_INTERVAL", time.Minute),
+
+		RedisAddr:          os.Getenv("REDIS_ADDR"),
+		RedisPassword:      os.Getenv("REDIS_PASSWORD"),
+		IdempotencyTTL:     getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLockTTL: getEnvDuration("IDEMPOTENCY_LOCK_TTL", time.Minute),
+
+		ExchangeRatesSource:          os.Getenv("EXCHANGE_RATES_SOURCE"),
+		ExchangeRatesRefreshInterval: getEnvDuration("EXCHANGE_RATES_REFRESH_INTERVAL", time.Hour),
+
+		DefaultLocale:    getEnv("DEFAULT_LOCALE", "tr"),
+		SupportedLocales: strings.Split(getEnv("SUPPORTED_LOCALES", "tr,en,de"), ","),
+		LocaleFallbacks:  os.Getenv("LOCALE_FALLBACKS"),
+
+		Tenants:  os.Getenv("TENANTS"),
+		LogLevel: os.Getenv("LOG_LEVEL"),
 	}, nil
 }
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
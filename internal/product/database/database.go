@@ -2,9 +2,11 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 
 	"cluster-iac/internal/product/config"
+	"cluster-iac/internal/product/model"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -15,13 +17,13 @@ func ConnectDB(cfg *config.Config) error {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := Open(postgres.Open(dsn))
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %v", err)
+		return err
 	}
 
 	DB = db
-	log.Println("Database connected successfully")
+	slog.Info("database connected")
 
 	// AutoMigrate ile tabloları oluştur
 	err = AutoMigrate()
@@ -32,13 +34,270 @@ func ConnectDB(cfg *config.Config) error {
 	return nil
 }
 
+// Open bağlantıyı açar ve tenant kapsamını kaydeder; TenantID alanı olan
+// modellerin sorguları context'teki tenant'la sınırlanır
+func Open(dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: newSlogLogger()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if err := db.Use(tenantScope{}); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %v", err)
+	}
+	return db, nil
+}
+
 func AutoMigrate() error {
+	// Category, Product'tan önce oluşmalı (foreign key)
+	err := DB.AutoMigrate(&Category{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate Category table: %v", err)
+	}
+
 	// Product modelini migrate et
-	err := DB.AutoMigrate(&Product{})
+	err = DB.AutoMigrate(&Product{})
 	if err != nil {
 		return fmt.Errorf("failed to migrate Product table: %v", err)
 	}
 
-	log.Println("Database migration completed successfully")
+	err = DB.AutoMigrate(&ProductOption{}, &ProductVariant{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate variant tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&Warehouse{}, &StockLevel{}, &InventoryMovement{}, &StockAlertState{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate inventory tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&PriceChange{}, &ScheduledPrice{}, &PriceListEntry{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate price tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&ProductTranslation{}, &CategoryTranslation{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate translation tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&ProductImage{}, &BlobDeletion{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate image tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&ImportJob{}, &ImportRowResult{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate import tables: %v", err)
+	}
+
+	err = DB.AutoMigrate(&AuditEntry{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate audit table: %v", err)
+	}
+
+	err = protectAuditEntries(DB)
+	if err != nil {
+		return fmt.Errorf("failed to protect audit table: %v", err)
+	}
+
+	err = dropGlobalUniqueIndexes(DB)
+	if err != nil {
+		return fmt.Errorf("failed to migrate unique indexes: %v", err)
+	}
+
+	err = migrateCategoryStrings(DB)
+	if err != nil {
+		return fmt.Errorf("failed to migrate product categories: %v", err)
+	}
+
+	err = migrateLegacyStock(DB)
+	if err != nil {
+		return fmt.Errorf("failed to migrate product stock: %v", err)
+	}
+
+	err = migrateInitialPrices(DB)
+	if err != nil {
+		return fmt.Errorf("failed to seed price history: %v", err)
+	}
+
+	slog.Info("database migration completed")
+	return nil
+}
+
+// protectAuditEntries denetim tablosunu veritabanı seviyesinde yalnızca
+// eklemeye açık hale getirir; uygulamadaki bir hata ya da elle çalıştırılan
+// bir sorgu kayıtları değiştiremez ve silemez
+func protectAuditEntries(db *gorm.DB) error {
+	return db.Exec(`
+		CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_entries is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries;
+		CREATE TRIGGER audit_entries_append_only
+			BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_entries
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_entries_append_only();
+	`).Error
+}
+
+// dropGlobalUniqueIndexes tenant öncesindeki tek kolonlu unique index'leri
+// kaldırır; yerlerini tenant_id ile birlikte tanımlanan index'ler alır ve
+// aynı slug, SKU ya da depo kodu farklı tenant'larda kullanılabilir
+func dropGlobalUniqueIndexes(db *gorm.DB) error {
+	indexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&Product{}, "idx_products_external_id"},
+		{&Category{}, "idx_categories_slug"},
+		{&ProductVariant{}, "idx_product_variants_sku"},
+		{&Warehouse{}, "idx_warehouses_code"},
+	}
+
+	for _, index := range indexes {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+		slog.Info("dropped global unique index", "index", index.name)
+	}
+	return nil
+}
+
+// migrateCategoryStrings eski serbest metin products.category kolonunu
+// categories tablosuna taşır. Aynı slug'a düşen yazımlar (ör. "Elektronik" ve
+// "elektronik ") tek kategoride birleşir; slug'a çevrilemeyen adlar (ör.
+// yalnızca ASCII dışı harfler) boş olmayan category-<n> slug'ı alır ve adları
+// korunur. Dönüşüm bitince kolon kaldırılır.
+func migrateCategoryStrings(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("products", "category") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var names []string
+		err := tx.Table("products").
+			Where("category IS NOT NULL AND category <> ''").
+			Distinct().
+			Order("category").
+			Pluck("category", &names).Error
+		if err != nil {
+			return err
+		}
+
+		generated := 0
+		for _, name := range names {
+			slug := model.Slugify(name)
+			if slug == "" {
+				if slug, err = unusedCategorySlug(tx, &generated); err != nil {
+					return err
+				}
+				slog.Warn("legacy category name has no slug, using a generated one", "category", name, "slug", slug)
+			}
+
+			var category Category
+			err := tx.Where(Category{Slug: slug}).
+				Attrs(Category{Name: strings.TrimSpace(name)}).
+				FirstOrCreate(&category).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Table("products").
+				Where("category = ?", name).
+				Update("category_id", category.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		slog.Info("migrated legacy category names to categories table", "count", len(names), "generated_slugs", generated)
+		return tx.Exec("ALTER TABLE products DROP COLUMN category").Error
+	})
+}
+
+// unusedCategorySlug henüz kullanılmayan bir sonraki category-<n> slug'ını
+// döndürür; n kaldığı yerden devam eder
+func unusedCategorySlug(tx *gorm.DB, n *int) (string, error) {
+	for {
+		*n++
+		slug := fmt.Sprintf("category-%d", *n)
+		var count int64
+		if err := tx.Model(&Category{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+	}
+}
+
+// migrateLegacyStock depo modeli öncesindeki products.stock değerlerini,
+// henüz hiç depo tanımlı değilse oluşturulan MAIN deposuna receive hareketi
+// olarak taşır. Böylece türetilmiş stok ilk açılışta sıfırlanmaz.
+func migrateLegacyStock(db *gorm.DB) error {
+	var warehouses int64
+	if err := db.Model(&Warehouse{}).Count(&warehouses).Error; err != nil {
+		return err
+	}
+	if warehouses > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		warehouse := Warehouse{Code: "MAIN", Name: "Main warehouse", Active: true}
+		if err := tx.Create(&warehouse).Error; err != nil {
+			return err
+		}
+
+		var products []Product
+		err := tx.Unscoped().Select("id", "stock").Where("stock > 0").Find(&products).Error
+		if err != nil {
+			return err
+		}
+
+		for _, p := range products {
+			level := StockLevel{ProductID: p.ID, WarehouseID: warehouse.ID, Quantity: p.Stock}
+			if err := tx.Create(&level).Error; err != nil {
+				return err
+			}
+
+			movement := InventoryMovement{
+				Type:          model.MovementReceive,
+				ProductID:     p.ID,
+				ToWarehouseID: &warehouse.ID,
+				Quantity:      p.Stock,
+				Reason:        "migrated from products.stock",
+				Actor:         "system",
+			}
+			if err := tx.Create(&movement).Error; err != nil {
+				return err
+			}
+		}
+
+		slog.Info("migrated legacy product stock", "count", len(products), "warehouse", warehouse.Code)
+		return nil
+	})
+}
+
+// migrateInitialPrices fiyat geçmişi tutulmaya başlamadan önce oluşturulmuş
+// ürünler için mevcut fiyatı oluşturulma zamanıyla "initial" kayıt olarak yazar
+func migrateInitialPrices(db *gorm.DB) error {
+	result := db.Exec(`
+		INSERT INTO price_changes (product_id, new_price, source, effective_at)
+		SELECT p.id, p.price, ?, p.created_at
+		FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM price_changes pc WHERE pc.product_id = p.id)`, model.PriceChangeInitial)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		slog.Info("seeded price history", "count", result.RowsAffected)
+	}
 	return nil
 }
@@ -0,0 +1,68 @@
+package database_test
+
+import (
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+)
+
+func TestMigrateCategoryStringsKeepsNamesWithoutSlug(t *testing.T) {
+	db := databasetest.Open(t)
+	if err := db.Exec("ALTER TABLE products ADD COLUMN category text").Error; err != nil {
+		t.Fatal(err)
+	}
+	// Elle oluşturulmuş category-1 üretilen slug'larla çakışmaz
+	if err := db.Create(&model.Category{Name: "Misc", Slug: "category-1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	legacy := map[string]string{"Phone": "Elektronik", "Cable": "elektronik ", "Tea": "日本", "Star": "★", "Plain": ""}
+	for name, category := range legacy {
+		err := db.Exec("INSERT INTO products (tenant_id, name, price, currency, category) VALUES ('default', ?, 1, 'TRY', ?)", name, category).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := database.MigrateCategoryStrings(db); err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasColumn("products", "category") {
+		t.Fatal("legacy category column was not dropped")
+	}
+
+	var rows []struct {
+		Name string
+		Slug *string
+		Cat  *string
+	}
+	err := db.Table("products").
+		Select("products.name, categories.slug, categories.name AS cat").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Scan(&rows).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if row.Slug != nil {
+			got[row.Name] = *row.Slug + "/" + *row.Cat
+		}
+	}
+	// Adlar sıralı işlenir: "★" "日本"dan önce gelir
+	want := map[string]string{
+		"Phone": "elektronik/Elektronik",
+		"Cable": "elektronik/Elektronik",
+		"Star":  "category-2/★",
+		"Tea":   "category-3/日本",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("categories = %v, want %v", got, want)
+	}
+	for name, category := range want {
+		if got[name] != category {
+			t.Fatalf("%s category = %q, want %q (all: %v)", name, got[name], category, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package databasetest repository testleri için tenant kapsamı kayıtlı,
+// geçici bir SQLite veritabanı açar
+package databasetest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Open tabloları Postgres'e özgü migration'lar (trigger'lar, veri taşıma)
+// olmadan oluşturur ve test süresince database.DB'yi bu bağlantıya çevirir;
+// böylece database.ForTenant da aynı veritabanını kullanır
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(sqlite.Open(filepath.Join(t.TempDir(), "product.db") + "?_busy_timeout=5000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(
+		&model.Category{}, &model.Product{},
+		&model.ProductOption{}, &model.ProductVariant{},
+		&model.Warehouse{}, &model.StockLevel{}, &model.InventoryMovement{}, &model.StockAlertState{},
+		&model.PriceChange{}, &model.ScheduledPrice{}, &model.PriceListEntry{},
+		&model.ProductTranslation{}, &model.CategoryTranslation{},
+		&model.ProductImage{}, &model.BlobDeletion{},
+		&model.ImportJob{}, &model.ImportRowResult{},
+		&model.AuditEntry{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -0,0 +1,4 @@
+package database
+
+// MigrateCategoryStrings database_test paketindeki testler içindir
+var MigrateCategoryStrings = migrateCategoryStrings
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Bu süreyi aşan sorgular WARN seviyesinde loglanır
+const slowQueryThreshold = 200 * time.Millisecond
+
+// slogLogger GORM'un loglarını servisin JSON logger'ına yazar; böylece sorgu
+// satırları da istek kimliğini ve tenant'ı taşır. Hatalar (kayıt bulunamadı
+// hariç) ERROR, yavaş sorgular WARN, diğer sorgular yalnızca DEBUG
+// seviyesinde loglanır.
+type slogLogger struct {
+	level gormlogger.LogLevel
+}
+
+func newSlogLogger() gormlogger.Interface {
+	return slogLogger{level: gormlogger.Warn}
+}
+
+func (l slogLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return slogLogger{level: level}
+}
+
+func (l slogLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l slogLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l slogLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "query failed", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	case slog.Default().Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		slog.DebugContext(ctx, "query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
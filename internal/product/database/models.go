@@ -6,3 +6,37 @@ import (
 
 // Product modelini database package'ında kullanabilmek için
 type Product = model.Product
+
+type Category = model.Category
+
+type ProductOption = model.ProductOption
+
+type ProductVariant = model.ProductVariant
+
+type Warehouse = model.Warehouse
+
+type StockLevel = model.StockLevel
+
+type InventoryMovement = model.InventoryMovement
+
+type StockAlertState = model.StockAlertState
+
+type PriceChange = model.PriceChange
+
+type ScheduledPrice = model.ScheduledPrice
+
+type PriceListEntry = model.PriceListEntry
+
+type ProductTranslation = model.ProductTranslation
+
+type CategoryTranslation = model.CategoryTranslation
+
+type ImportJob = model.ImportJob
+
+type ImportRowResult = model.ImportRowResult
+
+type ProductImage = model.ProductImage
+
+type BlobDeletion = model.BlobDeletion
+
+type AuditEntry = model.AuditEntry
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"reflect"
+
+	"cluster-iac/internal/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantScope TenantID alanı olan modellerde her sorguya, güncellemeye ve
+// silmeye context'teki tenant koşulunu ekler, oluşturulan kayıtlara tenant'ı
+// yazar. Böylece repository'ler tenant'ı ayrıca filtrelemez; yeni bir sorgu
+// da kapsam dışında kalamaz. Context'te tenant yoksa (migration'lar) sorgular
+// kapsamsız çalışır. Raw/Exec SQL'e koşul eklenemez, onlar tenant'ı kendisi
+// filtrelemelidir.
+type tenantScope struct{}
+
+// Aynı statement birden fazla kez çalıştırıldığında (ör. Count ardından
+// Find) koşul tekrar eklenmesin diye işaret
+const tenantScopedClause = "tenant_scoped"
+
+func (tenantScope) Name() string {
+	return "tenant_scope"
+}
+
+func (tenantScope) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", stampTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeTenantWrite); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenantWrite)
+}
+
+// ForTenant tüm sorguları id tenant'ıyla sınırlanan bir bağlantı döndürür
+func ForTenant(id string) *gorm.DB {
+	return DB.WithContext(tenant.NewContext(context.Background(), id))
+}
+
+func tenantField(stmt *gorm.Statement) bool {
+	return stmt.Schema != nil && stmt.Schema.LookUpField("TenantID") != nil
+}
+
+func scopeTenant(db *gorm.DB) {
+	stmt := db.Statement
+	id, ok := tenant.FromContext(stmt.Context)
+	if !ok || !tenantField(stmt) {
+		return
+	}
+	if _, scoped := stmt.Clauses[tenantScopedClause]; scoped {
+		return
+	}
+
+	stmt.Clauses[tenantScopedClause] = clause.Clause{}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: id},
+	}})
+}
+
+// scopeTenantWrite GORM'un koşulsuz toplu güncelleme ve silme kontrolünü
+// korur: ne WHERE ne de birincil anahtar varsa koşul eklenmez, GORM
+// ErrMissingWhereClause döner
+func scopeTenantWrite(db *gorm.DB) {
+	if _, ok := db.Statement.Clauses["WHERE"]; !ok && !db.AllowGlobalUpdate && !hasPrimaryKey(db.Statement) {
+		return
+	}
+	scopeTenant(db)
+}
+
+func hasPrimaryKey(stmt *gorm.Statement) bool {
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return false
+	}
+
+	field := stmt.Schema.PrioritizedPrimaryField
+	switch value := stmt.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if _, zero := field.ValueOf(stmt.Context, reflect.Indirect(value.Index(i))); !zero {
+				return true
+			}
+		}
+	case reflect.Struct:
+		_, zero := field.ValueOf(stmt.Context, value)
+		return !zero
+	}
+	return false
+}
+
+// stampTenant istemcinin gönderdiği değeri de ezer; kayıt başka bir tenant'a yazılamaz
+func stampTenant(db *gorm.DB) {
+	stmt := db.Statement
+	id, ok := tenant.FromContext(stmt.Context)
+	if !ok || !tenantField(stmt) {
+		return
+	}
+
+	field := stmt.Schema.LookUpField("TenantID")
+	switch value := stmt.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := field.Set(stmt.Context, reflect.Indirect(value.Index(i)), id); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(stmt.Context, value, id); err != nil {
+			db.AddError(err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+func createProduct(t *testing.T, repo repository.ProductRepository, name string) *model.Product {
+	t.Helper()
+	product := &model.Product{Name: name, Price: 10, Currency: "TRY"}
+	if err := repo.Create(product, model.Actor{Name: "test"}); err != nil {
+		t.Fatalf("Create %s: %v", name, err)
+	}
+	return product
+}
+
+func TestTenantCannotReachAnotherTenantsProducts(t *testing.T) {
+	databasetest.Open(t)
+	repoA := repository.NewProductRepository(database.ForTenant("a"))
+	repoB := repository.NewProductRepository(database.ForTenant("b"))
+
+	mine := createProduct(t, repoA, "A mug")
+	theirs := createProduct(t, repoB, "B mug")
+	if mine.TenantID != "a" || theirs.TenantID != "b" {
+		t.Fatalf("tenants = %q, %q", mine.TenantID, theirs.TenantID)
+	}
+
+	// Listeleme
+	products, err := repoA.GetAll()
+	if err != nil || len(products) != 1 || products[0].ID != mine.ID {
+		t.Fatalf("tenant a lists %+v (%v)", products, err)
+	}
+
+	// Okuma
+	if _, err := repoA.GetByID(theirs.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("tenant a GetByID of tenant b's product = %v", err)
+	}
+	if _, err := repoA.GetByIDWithDeleted(theirs.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("tenant a GetByIDWithDeleted of tenant b's product = %v", err)
+	}
+
+	// Güncelleme
+	hijacked := *theirs
+	hijacked.Name = "stolen"
+	if err := repoA.Update(&hijacked, theirs.Version, model.Actor{Name: "a"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("tenant a Update of tenant b's product = %v", err)
+	}
+
+	// Silme
+	if err := repoA.Delete(theirs.ID, theirs.Version, model.Actor{Name: "a"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("tenant a Delete of tenant b's product = %v", err)
+	}
+	if err := repoA.Purge(theirs.ID, model.Actor{Name: "a"}); err == nil {
+		t.Fatal("tenant a purged tenant b's product")
+	}
+
+	got, err := repoB.GetByID(theirs.ID)
+	if err != nil || got.Name != "B mug" || got.Version != theirs.Version {
+		t.Fatalf("tenant b's product after tenant a's attempts = %+v (%v)", got, err)
+	}
+	entries, err := repository.NewAuditRepository(database.ForTenant("b")).List(model.AuditFilter{EntityID: theirs.ID})
+	if err != nil || len(entries) != 1 || entries[0].Action != model.AuditCreate {
+		t.Fatalf("tenant b's audit trail = %+v (%v)", entries, err)
+	}
+}
+
+func TestTenantScopeOnPlainQueries(t *testing.T) {
+	databasetest.Open(t)
+	dbA, dbB := database.ForTenant("a"), database.ForTenant("b")
+
+	// İstemcinin verdiği tenant ezilir
+	forged := model.Product{TenantID: "b", Name: "forged", Price: 1, Currency: "TRY"}
+	if err := dbA.Create(&forged).Error; err != nil {
+		t.Fatal(err)
+	}
+	if forged.TenantID != "a" {
+		t.Fatalf("created product belongs to %q", forged.TenantID)
+	}
+	theirs := model.Product{Name: "B mug", Price: 1, Currency: "TRY"}
+	if err := dbB.Create(&theirs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	if err := dbA.Model(&model.Product{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("tenant a counts %d products (%v)", count, err)
+	}
+	if err := dbA.Model(&model.Product{}).Where("id = ?", theirs.ID).Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("tenant a counts tenant b's product: %d (%v)", count, err)
+	}
+
+	result := dbA.Model(&model.Product{}).Where("id = ?", theirs.ID).Update("name", "stolen")
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Fatalf("tenant a update by condition = %d rows (%v)", result.RowsAffected, result.Error)
+	}
+	result = dbA.Model(&theirs).Update("name", "stolen")
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Fatalf("tenant a update by primary key = %d rows (%v)", result.RowsAffected, result.Error)
+	}
+	result = dbA.Unscoped().Delete(&model.Product{}, theirs.ID)
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Fatalf("tenant a delete = %d rows (%v)", result.RowsAffected, result.Error)
+	}
+
+	// Koşulsuz toplu yazma engeli tenant koşuluyla aşılmaz
+	if err := dbA.Model(&model.Product{}).Update("name", "all").Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Fatalf("global update = %v, want ErrMissingWhereClause", err)
+	}
+
+	// Tenant'sız bağlantı (migration'lar) kapsamsızdır
+	var names []string
+	if err := database.DB.Model(&model.Product{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[1] != "B mug" {
+		t.Fatalf("unscoped names = %v", names)
+	}
+}
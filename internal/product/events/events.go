@@ -0,0 +1,98 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	ProductCreated  EventType = "product.created"
+	ProductUpdated  EventType = "product.updated"
+	ProductDeleted  EventType = "product.deleted"
+	ProductRestored EventType = "product.restored"
+	ProductPurged   EventType = "product.purged"
+	// Envanter hareketi ya da depo aktifliği satılabilir stoğu değiştirdiğinde
+	ProductStockChanged EventType = "product.stock_changed"
+	// Stok, yeniden sipariş eşiğinin altına düştüğünde ya da tükendiğinde
+	LowStock   EventType = "inventory.low_stock"
+	OutOfStock EventType = "inventory.out_of_stock"
+)
+
+type Event struct {
+	Type       EventType `json:"type"`
+	Tenant     string    `json:"tenant"`
+	ProductID  uint      `json:"product_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// ForTenant publisher'a giden event'lere tenant'ı yazar; tüm tenant'lar tek
+// Bus'ı paylaşır, aboneler Tenant alanına göre süzer
+func ForTenant(publisher Publisher, tenant string) Publisher {
+	return tenantPublisher{publisher: publisher, tenant: tenant}
+}
+
+type tenantPublisher struct {
+	publisher Publisher
+	tenant    string
+}
+
+func (p tenantPublisher) Publish(event Event) {
+	event.Tenant = p.tenant
+	p.publisher.Publish(event)
+}
+
+// Bus process içi basit bir pub/sub; yavaş abonelere ait event'ler düşürülür,
+// publish eden taraf hiçbir zaman bloklanmaz.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan Event
+	nextID      int
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[int]chan Event)}
+}
+
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, buffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			close(ch)
+			b.mu.Unlock()
+		})
+	}
+
+	return ch, unsubscribe
+}
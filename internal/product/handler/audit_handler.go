@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cluster-iac/internal/logging"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// requestActor değişikliği yapan isteğin aktörüdür; kimlik X-Actor
+// başlığından, istek kimliği logging middleware'inin context'inden alınır.
+// Gateway istemcinin X-Actor başlığını siler ve doğrulanan API anahtarının
+// adını yazar.
+func requestActor(c *gin.Context) model.Actor {
+	return model.Actor{
+		Name:      strings.TrimSpace(c.GetHeader("X-Actor")),
+		Source:    model.AuditSourceHTTP,
+		RequestID: logging.RequestID(c.Request.Context()),
+	}
+}
+
+// ListEntries ?entity=product&id=1 ile bir kaydın geçmişini, filtre
+// verilmezse tüm kayıtları yeniden eskiye döndürür. Sonraki sayfa için
+// yanıttaki next_before ?before= olarak gönderilir.
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	filter := model.AuditFilter{
+		Entity:    model.AuditEntity(c.Query("entity")),
+		Action:    model.AuditAction(c.Query("action")),
+		Actor:     c.Query("actor"),
+		Source:    model.AuditSource(c.Query("source")),
+		RequestID: c.Query("request_id"),
+	}
+
+	for param, target := range map[string]*uint{"id": &filter.EntityID, "before": &filter.Before} {
+		if value := c.Query(param); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				writeProblem(c, problem.New(problem.Invalid, "Invalid "+param))
+				return
+			}
+			*target = uint(parsed)
+		}
+	}
+	for param, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeProblem(c, problem.New(problem.Invalid, param+" must be an RFC3339 timestamp"))
+				return
+			}
+			*target = &parsed
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			writeProblem(c, problem.New(problem.Invalid, "limit must be a positive number"))
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	page, err := h.auditService.ListEntries(filter)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService    service.CategoryService
+	translationService service.TranslationService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService, translationService service.TranslationService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService:    categoryService,
+		translationService: translationService,
+	}
+}
+
+// requestedLocales ProductHandler'daki gibi istenen dilleri çözer
+func (h *CategoryHandler) requestedLocales(c *gin.Context) (locale.Chain, bool) {
+	c.Writer.Header().Add("Vary", locale.HeaderName)
+
+	chain, err := h.translationService.Resolve(c.Query("locale"), c.GetHeader(locale.HeaderName))
+	if err != nil {
+		writeProblem(c, err)
+		return nil, false
+	}
+	return chain, true
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var category model.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	category.ID = 0
+	if err := h.categoryService.CreateCategory(&category); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	chain, ok := h.requestedLocales(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(uint(id))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+	categories := []model.Category{*category}
+	if err := h.translationService.LocalizeCategories(categories, chain); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Header("Content-Language", categories[0].Locale)
+	c.JSON(http.StatusOK, categories[0])
+}
+
+func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
+	chain, ok := h.requestedLocales(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryService.GetAllCategories()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if err := h.translationService.LocalizeCategories(categories, chain); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	chain, ok := h.requestedLocales(c)
+	if !ok {
+		return
+	}
+
+	tree, err := h.categoryService.GetCategoryTree()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if err := h.translationService.LocalizeTree(tree, chain); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	var category model.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	category.ID = uint(id)
+	if err := h.categoryService.UpdateCategory(&category); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	updated, err := h.categoryService.GetCategoryByID(uint(id))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(uint(id)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
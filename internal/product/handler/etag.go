@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errIfMatchMissing = errors.New("If-Match header is required")
+	errIfMatchInvalid = errors.New("If-Match header must be a single strong ETag or *")
+)
+
+func productETag(version uint) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// ifMatchVersion If-Match header'ındaki versiyonu döndürür; "*" için wildcard true olur
+func ifMatchVersion(c *gin.Context) (version uint, wildcard bool, err error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, false, errIfMatchMissing
+	}
+	if header == "*" {
+		return 0, true, nil
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false, errIfMatchInvalid
+	}
+
+	parsed, err := strconv.ParseUint(header[1:len(header)-1], 10, 32)
+	if err != nil {
+		return 0, false, errIfMatchInvalid
+	}
+	return uint(parsed), false, nil
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cluster-iac/internal/product/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var exportColumns = []string{
+	"id", "external_id", "name", "description", "price", "currency", "stock", "reorder_threshold",
+	"tax_class", "weight_kg", "length_cm", "width_cm", "height_cm",
+	"category_id", "category", "image_url", "version", "created_at", "updated_at",
+}
+
+// exportFlushEvery CSV ve NDJSON çıktısının kaç satırda bir istemciye gönderileceği
+const exportFlushEvery = 500
+
+type exportWriter interface {
+	WriteRow(row *model.ProductExportRow) error
+	Close() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer) (exportWriter, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv": {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		newWriter:   newCSVExportWriter,
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		extension:   "ndjson",
+		newWriter:   newNDJSONExportWriter,
+	},
+	"xlsx": {
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		extension:   "xlsx",
+		newWriter:   newXLSXExportWriter,
+	},
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+	out    io.Writer
+	rows   int
+}
+
+func newCSVExportWriter(w io.Writer) (exportWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{writer: writer, out: w}, nil
+}
+
+func (e *csvExportWriter) WriteRow(row *model.ProductExportRow) error {
+	record := []string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		stringValue(row.ExternalID),
+		row.Name,
+		row.Description,
+		strconv.FormatFloat(row.Price, 'f', -1, 64),
+		row.Currency,
+		strconv.Itoa(row.Stock),
+		strconv.Itoa(row.ReorderThreshold),
+		row.TaxClass,
+		strconv.FormatFloat(row.WeightKg, 'f', -1, 64),
+		strconv.FormatFloat(row.LengthCm, 'f', -1, 64),
+		strconv.FormatFloat(row.WidthCm, 'f', -1, 64),
+		strconv.FormatFloat(row.HeightCm, 'f', -1, 64),
+		"",
+		stringValue(row.Category),
+		row.ImageURL,
+		strconv.FormatUint(uint64(row.Version), 10),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if row.CategoryID != nil {
+		record[13] = strconv.FormatUint(uint64(*row.CategoryID), 10)
+	}
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		e.writer.Flush()
+		flush(e.out)
+	}
+	return e.writer.Error()
+}
+
+func (e *csvExportWriter) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+	out     io.Writer
+	rows    int
+}
+
+func newNDJSONExportWriter(w io.Writer) (exportWriter, error) {
+	return &ndjsonExportWriter{encoder: json.NewEncoder(w), out: w}, nil
+}
+
+func (e *ndjsonExportWriter) WriteRow(row *model.ProductExportRow) error {
+	if err := e.encoder.Encode(row); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		flush(e.out)
+	}
+	return nil
+}
+
+func (e *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// xlsxExportWriter satırları excelize'ın stream writer'ına yazar; excelize
+// satırları geçici dosyada biriktirir, çalışma kitabı Close'da gönderilir
+type xlsxExportWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer) (exportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	header := make([]interface{}, len(exportColumns))
+	for i, column := range exportColumns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxExportWriter{file: file, stream: stream, out: w, row: 1}, nil
+}
+
+func (e *xlsxExportWriter) WriteRow(row *model.ProductExportRow) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+
+	var categoryID interface{}
+	if row.CategoryID != nil {
+		categoryID = *row.CategoryID
+	}
+	return e.stream.SetRow(cell, []interface{}{
+		row.ID,
+		stringValue(row.ExternalID),
+		row.Name,
+		row.Description,
+		row.Price,
+		row.Currency,
+		row.Stock,
+		row.ReorderThreshold,
+		row.TaxClass,
+		row.WeightKg,
+		row.LengthCm,
+		row.WidthCm,
+		row.HeightCm,
+		categoryID,
+		stringValue(row.Category),
+		row.ImageURL,
+		row.Version,
+		row.CreatedAt.UTC(),
+		row.UpdatedAt.UTC(),
+	})
+}
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := e.file.WriteTo(e.out)
+	return err
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	imageService  service.ImageService
+	maxBytes      int64
+	maxPerRequest int
+}
+
+func NewImageHandler(imageService service.ImageService, maxBytes int64, maxPerRequest int) *ImageHandler {
+	return &ImageHandler{
+		imageService:  imageService,
+		maxBytes:      maxBytes,
+		maxPerRequest: maxPerRequest,
+	}
+}
+
+// MaxRequestBytes bir yükleme isteğinin gövde sınırıdır; multipart sınırları
+// ve form alanları için 1MB pay bırakılır
+func (h *ImageHandler) MaxRequestBytes() int64 {
+	return h.maxBytes*int64(h.maxPerRequest) + 1<<20
+}
+
+type imageOrderRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
+
+// UploadImages multipart "images" (birden çok) ya da "file" alanlarındaki
+// görselleri gönderildikleri sırayla ürünün görsellerinin sonuna ekler.
+// Tür, istemcinin bildirdiği Content-Type'a değil dosya içeriğine göre belirlenir.
+func (h *ImageHandler) UploadImages(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxRequestBytes())
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeProblem(c, problem.New(problem.TooLarge, "Upload is too large"))
+			return
+		}
+		writeProblem(c, problem.New(problem.Invalid, "Multipart form with images is required"))
+		return
+	}
+
+	headers := append(form.File["images"], form.File["file"]...)
+	if len(headers) == 0 {
+		writeProblem(c, problem.New(problem.Invalid, "No images uploaded"))
+		return
+	}
+	if len(headers) > h.maxPerRequest {
+		writeProblem(c, problem.New(problem.Invalid, fmt.Sprintf("At most %d images can be uploaded at once", h.maxPerRequest)))
+		return
+	}
+
+	files := make([][]byte, 0, len(headers))
+	for _, header := range headers {
+		if header.Size > h.maxBytes {
+			writeProblem(c, problem.New(problem.TooLarge, fmt.Sprintf("%s exceeds %d bytes", header.Filename, h.maxBytes)))
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			writeProblem(c, problem.Wrap(problem.Invalid, err))
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, h.maxBytes+1))
+		file.Close()
+		if err != nil {
+			writeProblem(c, problem.Wrap(problem.Invalid, err))
+			return
+		}
+		files = append(files, data)
+	}
+
+	images, err := h.imageService.Upload(c.Request.Context(), id, files, requestActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, images)
+}
+
+func (h *ImageHandler) GetImages(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.GetImages(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// ReorderImages görsel id'lerini yeni sırasıyla alır; ilk görsel ana görsel olur
+func (h *ImageHandler) ReorderImages(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req imageOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	images, err := h.imageService.Reorder(id, req.ImageIDs, requestActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+func (h *ImageHandler) DeleteImage(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	imageID, ok := parseUintParam(c, "image_id")
+	if !ok {
+		return
+	}
+
+	if err := h.imageService.Delete(id, imageID, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
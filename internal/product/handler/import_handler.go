@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+	maxBytes      int64
+}
+
+func NewImportHandler(importService service.ImportService, maxBytes int64) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		maxBytes:      maxBytes,
+	}
+}
+
+// MaxRequestBytes içe aktarma dosyasının gövde sınırıdır (IMPORT_MAX_BYTES)
+func (h *ImportHandler) MaxRequestBytes() int64 {
+	return h.maxBytes
+}
+
+// ImportProducts dosyayı multipart "file" alanından ya da doğrudan gövdeden
+// alır. Format ?format= ile verilmezse dosya uzantısından veya Content-Type'tan
+// çıkarılır. İş arka planda çalışır; ilerleme GET /imports/:id ile izlenir.
+func (h *ImportHandler) ImportProducts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes)
+
+	dryRun := c.Query("dry_run") == "true"
+	format := model.ImportFormat(strings.ToLower(c.Query("format")))
+
+	var src io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			h.writeUploadError(c, err)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			writeProblem(c, problem.Wrap(problem.Invalid, err))
+			return
+		}
+		defer file.Close()
+
+		src = file
+		if format == "" {
+			format = importFormatFromName(fileHeader.Filename)
+		}
+		if format == "" {
+			format = importFormatFromMediaType(fileHeader.Header.Get("Content-Type"))
+		}
+	} else if format == "" {
+		format = importFormatFromMediaType(c.ContentType())
+	}
+
+	if format == "" {
+		writeProblem(c, problem.New(problem.Invalid, "format must be csv or ndjson"))
+		return
+	}
+
+	job, err := h.importService.StartImport(format, dryRun, src, requestActor(c))
+	if err != nil {
+		h.writeUploadError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/imports/%d", job.ID))
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.importService.GetJob(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportRows oluşturulan, güncellenen ve reddedilen satırları döndürür;
+// dry-run işlerde neyin değişeceğini gösterir
+func (h *ImportHandler) GetImportRows(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	rows, err := h.importService.GetRowResults(id, model.ImportAction(c.Query("action")))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+func (h *ImportHandler) GetImportErrors(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.importService.GetJob(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, id))
+	c.Status(http.StatusOK)
+	if err := h.importService.WriteErrorReport(id, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+func (h *ImportHandler) writeUploadError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		writeProblem(c, problem.New(problem.TooLarge, fmt.Sprintf("Import file exceeds %d bytes", h.maxBytes)))
+	case errors.Is(err, http.ErrMissingFile):
+		writeProblem(c, problem.New(problem.Invalid, "Multipart upload requires a file field"))
+	default:
+		writeProblem(c, err)
+	}
+}
+
+func importFormatFromName(name string) model.ImportFormat {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return model.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return model.ImportFormatNDJSON
+	}
+	return ""
+}
+
+func importFormatFromMediaType(contentType string) model.ImportFormat {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return model.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return model.ImportFormatNDJSON
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+}
+
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+type warehouseRequest struct {
+	Code    string `json:"code"`
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+	Active  *bool  `json:"active"`
+}
+
+type movementRequest struct {
+	Type            model.MovementType `json:"type" binding:"required"`
+	ProductID       uint               `json:"product_id" binding:"required"`
+	FromWarehouseID *uint              `json:"from_warehouse_id"`
+	ToWarehouseID   *uint              `json:"to_warehouse_id"`
+	Quantity        int                `json:"quantity" binding:"required"`
+	Reason          string             `json:"reason" binding:"required"`
+	Actor           string             `json:"actor"`
+}
+
+func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+	if req.Code == "" {
+		writeProblem(c, problem.New(problem.Invalid, "Warehouse code is required"))
+		return
+	}
+
+	warehouse := req.toModel()
+	if err := h.inventoryService.CreateWarehouse(warehouse); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+func (h *InventoryHandler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.inventoryService.GetWarehouses()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouses)
+}
+
+func (h *InventoryHandler) GetWarehouseByID(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	warehouse, err := h.inventoryService.GetWarehouseByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+func (h *InventoryHandler) UpdateWarehouse(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	warehouse := req.toModel()
+	warehouse.ID = id
+	if err := h.inventoryService.UpdateWarehouse(warehouse); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	updated, err := h.inventoryService.GetWarehouseByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *InventoryHandler) GetProductInventory(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	inventory, err := h.inventoryService.GetProductInventory(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inventory)
+}
+
+// RecordMovement actor gövdede verilmezse X-Actor header'ından alınır
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	var req movementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+	if req.Actor == "" {
+		req.Actor = c.GetHeader("X-Actor")
+	}
+
+	movement := &model.InventoryMovement{
+		Type:            req.Type,
+		ProductID:       req.ProductID,
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		Quantity:        req.Quantity,
+		Reason:          req.Reason,
+		Actor:           req.Actor,
+	}
+	if err := h.inventoryService.RecordMovement(movement); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movement)
+}
+
+func (h *InventoryHandler) GetMovements(c *gin.Context) {
+	var filter repository.MovementFilter
+	for param, target := range map[string]*uint{"product_id": &filter.ProductID, "warehouse_id": &filter.WarehouseID} {
+		if value := c.Query(param); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				writeProblem(c, problem.New(problem.Invalid, "Invalid "+param))
+				return
+			}
+			*target = uint(parsed)
+		}
+	}
+	filter.Type = model.MovementType(c.Query("type"))
+
+	filter.Limit = 100
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 || parsed > 1000 {
+			writeProblem(c, problem.New(problem.Invalid, "limit must be between 1 and 1000"))
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	movements, err := h.inventoryService.GetMovements(filter)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+func (r warehouseRequest) toModel() *model.Warehouse {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return &model.Warehouse{
+		Code:    r.Code,
+		Name:    r.Name,
+		Address: r.Address,
+		Active:  active,
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var errPatchNotObject = errors.New("merge patch document must be a JSON object")
+
+// applyMergePatch RFC 7396 JSON Merge Patch uygular: null alanı siler,
+// nesneler özyinelemeli birleştirilir, diğer değerler olduğu gibi yazılır.
+func applyMergePatch(target, patch []byte) ([]byte, error) {
+	var targetDoc, patchDoc interface{}
+	if err := json.Unmarshal(target, &targetDoc); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, err
+	}
+	if _, ok := patchDoc.(map[string]interface{}); !ok {
+		return nil, errPatchNotObject
+	}
+
+	return json.Marshal(mergeValue(targetDoc, patchDoc))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+	return targetObj
+}
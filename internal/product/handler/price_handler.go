@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceHandler struct {
+	priceService service.PriceService
+}
+
+func NewPriceHandler(priceService service.PriceService) *PriceHandler {
+	return &PriceHandler{priceService: priceService}
+}
+
+type scheduledPriceRequest struct {
+	Price    float64    `json:"price" binding:"required"`
+	StartsAt time.Time  `json:"starts_at" binding:"required"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Reason   string     `json:"reason"`
+}
+
+// GetPrices fiyat geçmişini döndürür; since (RFC3339) verilirse geçmiş o
+// andan itibaren filtrelenir
+func (h *PriceHandler) GetPrices(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var since *time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeProblem(c, problem.New(problem.Invalid, "since must be an RFC3339 timestamp"))
+			return
+		}
+		since = &parsed
+	}
+
+	timeline, err := h.priceService.GetTimeline(id, since)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func (h *PriceHandler) CreateSchedule(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req scheduledPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	schedule := &model.ScheduledPrice{
+		ProductID: id,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Reason:    req.Reason,
+	}
+	if err := h.priceService.SchedulePrice(schedule); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *PriceHandler) CancelSchedule(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	scheduleID, ok := parseUintParam(c, "schedule_id")
+	if !ok {
+		return
+	}
+
+	if err := h.priceService.CancelSchedule(id, scheduleID, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled price cancelled"})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingHandler struct {
+	pricingService service.PricingService
+}
+
+func NewPricingHandler(pricingService service.PricingService) *PricingHandler {
+	return &PricingHandler{pricingService: pricingService}
+}
+
+// GetCurrencies desteklenen para birimlerini ve güncel kurları döndürür
+func (h *PricingHandler) GetCurrencies(c *gin.Context) {
+	c.JSON(http.StatusOK, h.pricingService.Rates())
+}
+
+func (h *PricingHandler) GetPriceList(c *gin.Context) {
+	entries, err := h.pricingService.GetPriceList(c.Param("currency"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *PricingHandler) GetProductPriceLists(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := h.pricingService.GetProductPriceLists(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+type listPriceRequest struct {
+	Price     *float64 `json:"price" binding:"required"`
+	VariantID uint     `json:"variant_id"`
+}
+
+func (h *PricingHandler) SetListPrice(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req listPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	entry := &model.PriceListEntry{
+		Currency:  c.Param("currency"),
+		ProductID: id,
+		VariantID: req.VariantID,
+		Price:     *req.Price,
+	}
+	if err := h.pricingService.SetListPrice(entry, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteListPrice liste fiyatını kaldırır; variant_id verilmezse ürünün
+// kendi liste fiyatı silinir
+func (h *PricingHandler) DeleteListPrice(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var variantID uint64
+	if value := c.Query("variant_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			writeProblem(c, problem.New(problem.Invalid, "variant_id must be a positive integer"))
+			return
+		}
+		variantID = parsed
+	}
+
+	if err := h.pricingService.DeleteListPrice(c.Param("currency"), id, uint(variantID), requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List price removed"})
+}
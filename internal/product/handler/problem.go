@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"cluster-iac/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeProblem hatayı application/problem+json olarak yazar; status servis
+// katmanının döndürdüğü hata tipinden gelir
+func writeProblem(c *gin.Context, err error) {
+	problem.Write(c.Writer, c.Request, err)
+	c.Abort()
+}
+
+// writeBindError gövdedeki tip uyuşmazlıklarını (ör. price için string) alan
+// hatası olarak, bozuk JSON'u 400 olarak raporlar
+func writeBindError(c *gin.Context, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		writeProblem(c, problem.NewValidation(problem.FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: "must be a " + typeErr.Type.String(),
+		}))
+		return
+	}
+
+	writeProblem(c, problem.Wrap(problem.Invalid, err))
+}
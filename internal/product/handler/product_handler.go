@@ -1,35 +1,80 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/problem"
 	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
 	"cluster-iac/internal/product/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ProductHandler struct {
-	productService service.ProductService
+	productService     service.ProductService
+	pricingService     service.PricingService
+	translationService service.TranslationService
+	trashRetention     time.Duration
 }
 
-func NewProductHandler(productService service.ProductService) *ProductHandler {
-	return &ProductHandler{productService: productService}
+func NewProductHandler(productService service.ProductService, pricingService service.PricingService, translationService service.TranslationService, trashRetention time.Duration) *ProductHandler {
+	return &ProductHandler{
+		productService:     productService,
+		pricingService:     pricingService,
+		translationService: translationService,
+		trashRetention:     trashRetention,
+	}
+}
+
+// requestedCurrency currency parametresini ya da Accept-Currency başlığını
+// çözer; ikisi de yoksa fiyatlar yalnızca ürünün para biriminde döner
+func (h *ProductHandler) requestedCurrency(c *gin.Context) (string, bool) {
+	c.Writer.Header().Add("Vary", currency.HeaderName)
+
+	code, err := h.pricingService.Negotiate(c.Query("currency"), c.GetHeader(currency.HeaderName))
+	if err != nil {
+		writeProblem(c, err)
+		return "", false
+	}
+	return code, true
+}
+
+// requestedLocales locale parametresini ya da Accept-Language başlığını
+// fallback zincirine çevirir; ikisi de yoksa zincir yalnızca varsayılan dildir
+func (h *ProductHandler) requestedLocales(c *gin.Context) (locale.Chain, bool) {
+	c.Writer.Header().Add("Vary", locale.HeaderName)
+
+	chain, err := h.translationService.Resolve(c.Query("locale"), c.GetHeader(locale.HeaderName))
+	if err != nil {
+		writeProblem(c, err)
+		return nil, false
+	}
+	return chain, true
 }
 
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var product model.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
-	if err := h.productService.CreateProduct(&product); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.CreateProduct(&product, requestActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusCreated, product)
 }
 
@@ -37,23 +82,59 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	code, ok := h.requestedCurrency(c)
+	if !ok {
+		return
+	}
+	chain, ok := h.requestedLocales(c)
+	if !ok {
 		return
 	}
 
 	product, err := h.productService.GetProductByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		writeProblem(c, err)
+		return
+	}
+	if err := h.pricingService.Present(product, code); err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if err := h.translationService.Localize(product, chain); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
+	c.Header("Content-Language", product.Locale)
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusOK, product)
 }
 
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
+	code, ok := h.requestedCurrency(c)
+	if !ok {
+		return
+	}
+	chain, ok := h.requestedLocales(c)
+	if !ok {
+		return
+	}
+
 	products, err := h.productService.GetAllProducts()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
+		return
+	}
+	if err := h.pricingService.PresentAll(products, code); err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if err := h.translationService.LocalizeAll(products, chain); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
@@ -64,22 +145,114 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	expectedVersion, ok := h.expectedVersion(c, uint(id))
+	if !ok {
 		return
 	}
 
 	var product model.Product
 	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
 	product.ID = uint(id)
-	if err := h.productService.UpdateProduct(&product); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// external_id gönderilmezse kayıtlı değer korunur; aksi halde sonraki
+	// içe aktarma ürünü eşleştiremez ve kopyasını oluşturur. Silmek için
+	// PATCH ile null gönderilir.
+	if product.ExternalID == nil {
+		current, err := h.productService.GetProductByID(product.ID)
+		if err != nil {
+			writeProblem(c, err)
+			return
+		}
+		product.ExternalID = current.ExternalID
+	}
+	if err := h.productService.UpdateProduct(&product, expectedVersion, requestActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
+	c.Header("ETag", productETag(product.Version))
+	c.JSON(http.StatusOK, product)
+}
+
+// PatchProduct gövdeyi JSON Merge Patch (RFC 7396) olarak uygular; yalnızca
+// gönderilen alanlar değişir. If-Match opsiyoneldir, gönderilmezse okunan
+// versiyon üzerinden koşullu güncelleme yapılır.
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		writeProblem(c, problem.New(problem.UnsupportedMedia, "Content-Type must be application/merge-patch+json"))
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	current, err := h.productService.GetProductByID(uint(id))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if c.GetHeader("If-Match") != "" {
+		version, wildcard, err := ifMatchVersion(c)
+		if err != nil {
+			writeProblem(c, problem.Wrap(problem.Invalid, err))
+			return
+		}
+		if !wildcard && version != current.Version {
+			writeProblem(c, repository.ErrVersionConflict)
+			return
+		}
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	patchedJSON, err := applyMergePatch(currentJSON, patch)
+	if err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	var product model.Product
+	if err := json.Unmarshal(patchedJSON, &product); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Sunucu tarafından yönetilen alanlar patch ile değiştirilemez
+	product.ID = current.ID
+	product.CreatedAt = current.CreatedAt
+	product.UpdatedAt = current.UpdatedAt
+	product.DeletedAt = current.DeletedAt
+	product.Category = nil
+
+	if err := h.productService.UpdateProduct(&product, current.Version, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusOK, product)
 }
 
@@ -87,30 +260,203 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	expectedVersion, ok := h.expectedVersion(c, uint(id))
+	if !ok {
 		return
 	}
 
-	if err := h.productService.DeleteProduct(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.productService.DeleteProduct(uint(id), expectedVersion, requestActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
 
+// expectedVersion If-Match header'ını zorunlu tutar; "*" gönderilmişse
+// ürünün mevcut versiyonunu kullanır
+func (h *ProductHandler) expectedVersion(c *gin.Context, id uint) (uint, bool) {
+	version, wildcard, err := ifMatchVersion(c)
+	if errors.Is(err, errIfMatchMissing) {
+		writeProblem(c, problem.Wrap(problem.PreconditionRequired, err))
+		return 0, false
+	}
+	if err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return 0, false
+	}
+	if !wildcard {
+		return version, true
+	}
+
+	current, err := h.productService.GetProductByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return 0, false
+	}
+	return current.Version, true
+}
+
 func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 	category := c.Query("category")
 	if category == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Category parameter is required"})
+		writeProblem(c, problem.New(problem.Invalid, "Category parameter is required"))
 		return
 	}
 
-	products, err := h.productService.GetProductsByCategory(category)
+	includeDescendants, _ := strconv.ParseBool(c.Query("include_descendants"))
+
+	code, ok := h.requestedCurrency(c)
+	if !ok {
+		return
+	}
+	chain, ok := h.requestedLocales(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.productService.GetProductsByCategory(category, includeDescendants)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
+		return
+	}
+	if err := h.pricingService.PresentAll(products, code); err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if err := h.translationService.LocalizeAll(products, chain); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, products)
 }
+
+// ExportProducts ürünleri format'a göre (csv, ndjson, xlsx) veritabanından
+// satır satır okuyarak akıtır. category/include_descendants kategori
+// listelemesiyle aynıdır; updated_since (RFC3339) artımlı dışa aktarım içindir.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	formatName := c.DefaultQuery("format", "csv")
+	format, ok := exportFormats[formatName]
+	if !ok {
+		writeProblem(c, problem.New(problem.Invalid, "format must be csv, ndjson or xlsx"))
+		return
+	}
+
+	filter := model.ProductExportFilter{CategorySlug: c.Query("category")}
+	filter.IncludeDescendants, _ = strconv.ParseBool(c.Query("include_descendants"))
+	if value := c.Query("updated_since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeProblem(c, problem.New(problem.Invalid, "updated_since must be an RFC3339 timestamp"))
+			return
+		}
+		filter.UpdatedSince = &since
+	}
+
+	// Writer ilk satırda oluşturulur; böylece kategori bulunamazsa hâlâ JSON hata dönebilir
+	var writer exportWriter
+	err := h.productService.ExportProducts(filter, func(row *model.ProductExportRow) error {
+		if writer == nil {
+			var err error
+			if writer, err = h.startExport(c, format); err != nil {
+				return err
+			}
+		}
+		return writer.WriteRow(row)
+	})
+	if err == nil && writer == nil {
+		writer, err = h.startExport(c, format)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		if c.Writer.Written() {
+			// Gövde yazılmaya başlandıktan sonra durum kodu değiştirilemez; bağlantıyı kesmekle yetin
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		writeProblem(c, err)
+	}
+}
+
+func (h *ProductHandler) startExport(c *gin.Context, format exportFormat) (exportWriter, error) {
+	filename := fmt.Sprintf("products-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format.extension)
+	c.Header("Content-Type", format.contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	return format.newWriter(c.Writer)
+}
+
+func (h *ProductHandler) GetTrash(c *gin.Context) {
+	products, err := h.productService.GetTrash()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	product, err := h.productService.RestoreProduct(uint(id), requestActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(product.Version))
+	c.JSON(http.StatusOK, product)
+}
+
+// PurgeProduct çöp kutusundaki bir ürünü retention süresini beklemeden kalıcı olarak siler
+func (h *ProductHandler) PurgeProduct(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return
+	}
+
+	if err := h.productService.PurgeProduct(uint(id), requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product purged successfully"})
+}
+
+// PurgeTrash retention politikasını hemen uygular; older_than ile varsayılan süre ezilebilir
+func (h *ProductHandler) PurgeTrash(c *gin.Context) {
+	retention := h.trashRetention
+	if olderThan := c.Query("older_than"); olderThan != "" {
+		parsed, err := time.ParseDuration(olderThan)
+		if err != nil || parsed < 0 {
+			writeProblem(c, problem.New(problem.Invalid, "Invalid older_than duration"))
+			return
+		}
+		retention = parsed
+	}
+
+	purged, err := h.productService.PurgeExpired(retention, requestActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purged": purged, "older_than": retention.String()})
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type productFixture struct {
+	repo   repository.ProductRepository
+	router *gin.Engine
+}
+
+func newProductFixture(t *testing.T) *productFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	databasetest.Open(t)
+	db := database.ForTenant("acme")
+
+	currencies, err := currency.NewConverter(context.Background(), currency.NewSource(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := repository.NewProductRepository(db)
+	productService := service.NewProductService(repo, repository.NewCategoryRepository(db), currencies, nil)
+	h := NewProductHandler(productService, nil, nil, 0)
+
+	router := gin.New()
+	router.PUT("/products/:id", h.UpdateProduct)
+	router.PATCH("/products/:id", h.PatchProduct)
+	return &productFixture{repo: repo, router: router}
+}
+
+func (f *productFixture) create(t *testing.T, product *model.Product) *model.Product {
+	t.Helper()
+	if err := f.repo.Create(product, model.Actor{Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	return product
+}
+
+func (f *productFixture) send(t *testing.T, method string, product *model.Product, contentType, body string) (*httptest.ResponseRecorder, *model.Product) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/products/"+strconv.FormatUint(uint64(product.ID), 10), strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("If-Match", productETag(product.Version))
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec, nil
+	}
+
+	stored, err := f.repo.GetByID(product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec, stored
+}
+
+func TestPutKeepsStoredExternalID(t *testing.T) {
+	f := newProductFixture(t)
+	externalID := "ERP-1"
+	product := f.create(t, &model.Product{ExternalID: &externalID, Name: "Mug", Price: 10, Currency: "TRY"})
+
+	rec, stored := f.send(t, http.MethodPut, product, "application/json", `{"name": "Big mug", "price": 12, "currency": "TRY"}`)
+	if stored == nil {
+		t.Fatalf("PUT = %d %s", rec.Code, rec.Body.String())
+	}
+	if stored.ExternalID == nil || *stored.ExternalID != "ERP-1" || stored.Name != "Big mug" {
+		t.Fatalf("after PUT without external_id: external_id = %v, name = %q", stored.ExternalID, stored.Name)
+	}
+
+	rec, stored = f.send(t, http.MethodPut, stored, "application/json", `{"external_id": "ERP-2", "name": "Big mug", "price": 12, "currency": "TRY"}`)
+	if stored == nil || stored.ExternalID == nil || *stored.ExternalID != "ERP-2" {
+		t.Fatalf("PUT with external_id = %d %s", rec.Code, rec.Body.String())
+	}
+
+	// PATCH null ile silinebilir
+	rec, stored = f.send(t, http.MethodPatch, stored, "application/merge-patch+json", `{"external_id": null}`)
+	if stored == nil || stored.ExternalID != nil {
+		t.Fatalf("PATCH external_id null = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPutKeepsStoredCurrency(t *testing.T) {
+	f := newProductFixture(t)
+	product := f.create(t, &model.Product{Name: "Mug", Price: 10, Currency: "USD"})
+
+	rec, stored := f.send(t, http.MethodPut, product, "application/json", `{"name": "Mug", "price": 12}`)
+	if stored == nil || stored.Currency != "USD" || stored.Price != 12 {
+		t.Fatalf("PUT without currency = %d %s", rec.Code, rec.Body.String())
+	}
+
+	// JPY basamak kuralı gönderilen para birimine göre uygulanır
+	rec, _ = f.send(t, http.MethodPut, stored, "application/json", `{"name": "Mug", "price": 12.5, "currency": "JPY"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("PUT JPY with decimals = %d %s", rec.Code, rec.Body.String())
+	}
+	rec, stored = f.send(t, http.MethodPut, stored, "application/json", `{"name": "Mug", "price": 1800, "currency": "jpy"}`)
+	if stored == nil || stored.Currency != "JPY" {
+		t.Fatalf("PUT with currency = %d %s", rec.Code, rec.Body.String())
+	}
+}
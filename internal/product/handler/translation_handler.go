@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService service.TranslationService
+}
+
+func NewTranslationHandler(translationService service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{translationService: translationService}
+}
+
+func (h *TranslationHandler) GetProductTranslations(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationService.GetProductTranslations(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+type productTranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (h *TranslationHandler) SetProductTranslation(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req productTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	translation := &model.ProductTranslation{
+		ProductID:   id,
+		Locale:      c.Param("locale"),
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.translationService.SetProductTranslation(translation, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteProductTranslation(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteProductTranslation(id, c.Param("locale"), requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+func (h *TranslationHandler) GetCategoryTranslations(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationService.GetCategoryTranslations(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+type categoryTranslationRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *TranslationHandler) SetCategoryTranslation(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req categoryTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	translation := &model.CategoryTranslation{
+		CategoryID: id,
+		Locale:     c.Param("locale"),
+		Name:       req.Name,
+	}
+	if err := h.translationService.SetCategoryTranslation(translation); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteCategoryTranslation(c *gin.Context) {
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteCategoryTranslation(id, c.Param("locale")); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
+
+// GetCompleteness desteklenen dillerin çeviri oranlarını döndürür; locale
+// verilirse yalnızca o dil raporlanır
+func (h *TranslationHandler) GetCompleteness(c *gin.Context) {
+	report, err := h.translationService.Completeness(c.Query("locale"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetMissing bir dilde çevirisi olmayan ürünleri (entity=category ile
+// kategorileri) listeler
+func (h *TranslationHandler) GetMissing(c *gin.Context) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeProblem(c, problem.New(problem.Invalid, "limit must be a positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	missing, err := h.translationService.Missing(c.Param("locale"), c.Query("entity"), limit)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, missing)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariantHandler struct {
+	variantService service.VariantService
+}
+
+func NewVariantHandler(variantService service.VariantService) *VariantHandler {
+	return &VariantHandler{variantService: variantService}
+}
+
+type variantRequest struct {
+	SKU        string            `json:"sku" binding:"required"`
+	Attributes map[string]string `json:"attributes" binding:"required"`
+	Price      float64           `json:"price" binding:"min=0"`
+	Stock      int               `json:"stock" binding:"min=0"`
+	ImageURL   string            `json:"image_url"`
+}
+
+func (h *VariantHandler) GetOptions(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	options, err := h.variantService.GetOptions(productID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *VariantHandler) ReplaceOptions(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var options []model.ProductOption
+	if err := c.ShouldBindJSON(&options); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	if err := h.variantService.ReplaceOptions(productID, options, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *VariantHandler) GetVariants(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	variants, err := h.variantService.GetVariants(productID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variants)
+}
+
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req variantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	variant := req.toModel(productID)
+	if err := h.variantService.CreateVariant(variant, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseUintParam(c, "variant_id")
+	if !ok {
+		return
+	}
+
+	var req variantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeProblem(c, problem.Wrap(problem.Invalid, err))
+		return
+	}
+
+	variant := req.toModel(productID)
+	variant.ID = variantID
+	if err := h.variantService.UpdateVariant(variant, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	productID, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseUintParam(c, "variant_id")
+	if !ok {
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(productID, variantID, requestActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variant deleted successfully"})
+}
+
+func (r variantRequest) toModel(productID uint) *model.ProductVariant {
+	return &model.ProductVariant{
+		ProductID:  productID,
+		SKU:        r.SKU,
+		Attributes: r.Attributes,
+		Price:      r.Price,
+		Stock:      r.Stock,
+		ImageURL:   r.ImageURL,
+	}
+}
+
+func parseUintParam(c *gin.Context, name string) (uint, bool) {
+	value, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		writeProblem(c, problem.New(problem.Invalid, "Invalid ID"))
+		return 0, false
+	}
+	return uint(value), true
+}
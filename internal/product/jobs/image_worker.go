@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/product/service"
+)
+
+// RunImageWorker yüklenen görsellerin küçük görsellerini kuyruktan gelen
+// sırayla üretir. Her interval'de kuyruğa giremeyen bekleyen görselleri işler
+// ve silinen görsellerin blob'larını store'dan temizler.
+func RunImageWorker(ctx context.Context, imageService service.ImageService, queue <-chan uint, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case imageID := <-queue:
+			if err := imageService.GenerateThumbnail(ctx, imageID); err != nil {
+				slog.ErrorContext(ctx, "thumbnail generation failed", "image_id", imageID, "error", err)
+			}
+		case <-ticker.C:
+			if err := imageService.ProcessPendingThumbnails(ctx); err != nil {
+				slog.ErrorContext(ctx, "pending thumbnail run failed", "error", err)
+			}
+			if err := imageService.CollectGarbage(ctx); err != nil {
+				slog.ErrorContext(ctx, "blob garbage collection failed", "error", err)
+			}
+		}
+	}
+}
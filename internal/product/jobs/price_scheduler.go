@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/product/service"
+)
+
+// RunPriceScheduler planlı fiyat değişikliklerini her interval'de uygular ve
+// süresi dolanları geri alır; ctx iptal edilene kadar çalışır.
+func RunPriceScheduler(ctx context.Context, priceService service.PriceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			changed, err := priceService.ApplySchedules(now)
+			if err != nil {
+				slog.ErrorContext(ctx, "price scheduler run failed", "error", err)
+				continue
+			}
+			if changed > 0 {
+				slog.InfoContext(ctx, "price scheduler changed product prices", "count", changed)
+			}
+		}
+	}
+}
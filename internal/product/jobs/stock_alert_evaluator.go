@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/service"
+)
+
+// RunStockAlertEvaluator tenant'ın stok değiştiren event'lerinde ilgili ürünü
+// değerlendirir. Bus yavaş abonelere ait event'leri düşürebildiği için her
+// interval'de tüm ürünler ayrıca taranır.
+func RunStockAlertEvaluator(ctx context.Context, bus *events.Bus, tenant string, alertService service.StockAlertService, interval time.Duration) {
+	eventCh, unsubscribe := bus.Subscribe(256)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			if evt.Tenant != tenant {
+				continue
+			}
+			switch evt.Type {
+			case events.ProductStockChanged, events.ProductUpdated, events.ProductRestored,
+				events.ProductDeleted, events.ProductPurged:
+				if err := alertService.Evaluate(ctx, evt.ProductID); err != nil {
+					slog.ErrorContext(ctx, "stock alert evaluation failed", "product_id", evt.ProductID, "error", err)
+				}
+			}
+		case <-ticker.C:
+			if err := alertService.EvaluateAll(ctx); err != nil {
+				slog.ErrorContext(ctx, "stock alert sweep failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/service"
+)
+
+// RunTrashPurger retention süresini aşan silinmiş ürünleri her interval'de
+// kalıcı olarak temizler; ctx iptal edilene kadar çalışır.
+func RunTrashPurger(ctx context.Context, productService service.ProductService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := productService.PurgeExpired(retention, model.SystemActor("trash-purger"))
+			if err != nil {
+				slog.ErrorContext(ctx, "trash purge failed", "error", err)
+				continue
+			}
+			if purged > 0 {
+				slog.InfoContext(ctx, "purged expired products from trash", "count", purged, "retention", retention.String())
+			}
+		}
+	}
+}
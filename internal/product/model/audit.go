@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityProduct AuditEntity = "product"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+)
+
+type AuditSource string
+
+const (
+	AuditSourceHTTP   AuditSource = "http"
+	AuditSourceGRPC   AuditSource = "grpc"
+	AuditSourceSystem AuditSource = "system"
+)
+
+// Actor bir değişikliği kimin, hangi kanaldan ve hangi istekle yaptığıdır.
+// Arka plan işlerinde Name işin adıdır ve RequestID boştur.
+type Actor struct {
+	Name      string
+	Source    AuditSource
+	RequestID string
+}
+
+// SystemActor arka plan işlerinin yaptığı değişiklikler içindir
+func SystemActor(job string) Actor {
+	return Actor{Name: job, Source: AuditSourceSystem}
+}
+
+// AuditChange bir alanın değişiklikten önceki ve sonraki değeridir
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditEntry denetim kaydının değiştirilemez bir satırıdır ve değişikliği
+// yapan transaction içinde yazılır. Changes yalnızca değişen alanları
+// taşır. Ürün kalıcı silinse de kayıtlar korunur; bu yüzden products
+// tablosuna foreign key tanımlanmaz.
+type AuditEntry struct {
+	ID        uint                   `json:"id" gorm:"primaryKey"`
+	TenantID  string                 `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Entity    AuditEntity            `json:"entity" gorm:"size:32;not null;index:idx_audit_entity"`
+	EntityID  uint                   `json:"entity_id" gorm:"not null;index:idx_audit_entity"`
+	Action    AuditAction            `json:"action" gorm:"size:16;not null"`
+	Actor     string                 `json:"actor" gorm:"not null;index"`
+	Source    AuditSource            `json:"source" gorm:"size:16;not null"`
+	RequestID string                 `json:"request_id,omitempty" gorm:"index"`
+	Changes   map[string]AuditChange `json:"changes" gorm:"serializer:json;not null"`
+	CreatedAt time.Time              `json:"created_at" gorm:"index"`
+}
+
+// AuditFilter GET /audit sorgusudur. Kayıtlar yeniden eskiye sıralanır;
+// Before verilirse yalnızca o id'den önceki kayıtlar döner.
+type AuditFilter struct {
+	Entity    AuditEntity
+	EntityID  uint
+	Action    AuditAction
+	Actor     string
+	Source    AuditSource
+	RequestID string
+	Since     *time.Time
+	Until     *time.Time
+	Before    uint
+	Limit     int
+}
+
+// AuditPage bir sayfa kayıttır; NextBefore sonraki sayfa için Before
+// değeridir, son sayfada nil'dir
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextBefore *uint        `json:"next_before"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Name katalog varsayılan dilindedir; okumada çevrildiğinde Locale
+// kullanılan dili gösterir
+type Category struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TenantID  string    `json:"-" gorm:"size:64;not null;default:'default';uniqueIndex:idx_category_tenant_slug"`
+	Name      string    `json:"name" gorm:"not null"`
+	Locale    string    `json:"locale,omitempty" gorm:"-"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex:idx_category_tenant_slug"`
+	ParentID  *uint     `json:"parent_id" gorm:"index"`
+	Parent    *Category `json:"-" gorm:"foreignKey:ParentID"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryTreeNode /categories/tree cevabındaki bir düğüm; TotalProductCount
+// alt kategorilerdeki ürünleri de içerir
+type CategoryTreeNode struct {
+	ID                uint                `json:"id"`
+	Name              string              `json:"name"`
+	Locale            string              `json:"locale,omitempty"`
+	Slug              string              `json:"slug"`
+	Position          int                 `json:"position"`
+	ProductCount      int64               `json:"product_count"`
+	TotalProductCount int64               `json:"total_product_count"`
+	Children          []*CategoryTreeNode `json:"children"`
+}
+
+var slugReplacer = strings.NewReplacer(
+	"ç", "c", "Ç", "c",
+	"ğ", "g", "Ğ", "g",
+	"ı", "i", "İ", "i",
+	"ö", "o", "Ö", "o",
+	"ş", "s", "Ş", "s",
+	"ü", "u", "Ü", "u",
+)
+
+// Slugify kategori adından URL'de kullanılabilecek bir slug üretir
+func Slugify(name string) string {
+	name = strings.ToLower(slugReplacer.Replace(strings.TrimSpace(name)))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
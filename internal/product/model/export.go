@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// ProductExportFilter listeleme uç noktalarıyla aynı filtreleri taşır;
+// UpdatedSince artımlı dışa aktarım içindir
+type ProductExportFilter struct {
+	CategorySlug       string
+	IncludeDescendants bool
+	UpdatedSince       *time.Time
+}
+
+// ProductExportRow dışa aktarılan düz ürün satırıdır; kategori slug'ı join ile gelir
+type ProductExportRow struct {
+	ID               uint      `json:"id"`
+	ExternalID       *string   `json:"external_id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Price            float64   `json:"price"`
+	Currency         string    `json:"currency"`
+	Stock            int       `json:"stock"`
+	ReorderThreshold int       `json:"reorder_threshold"`
+	TaxClass         string    `json:"tax_class"`
+	WeightKg         float64   `json:"weight_kg"`
+	LengthCm         float64   `json:"length_cm"`
+	WidthCm          float64   `json:"width_cm"`
+	HeightCm         float64   `json:"height_cm"`
+	CategoryID       *uint     `json:"category_id"`
+	Category         *string   `json:"category"`
+	ImageURL         string    `json:"image_url"`
+	Version          uint      `json:"version"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
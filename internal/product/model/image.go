@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+type ThumbnailStatus string
+
+const (
+	ThumbnailPending ThumbnailStatus = "pending"
+	ThumbnailReady   ThumbnailStatus = "ready"
+	ThumbnailFailed  ThumbnailStatus = "failed"
+)
+
+// ProductImage blob store'a yüklenmiş bir ürün görselidir. Position'ı en küçük
+// olan görsel ana görseldir ve URL'si Product.ImageURL'e yazılır.
+type ProductImage struct {
+	ID              uint            `json:"id" gorm:"primaryKey"`
+	TenantID        string          `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID       uint            `json:"product_id" gorm:"not null;index"`
+	Position        int             `json:"position" gorm:"not null;default:0"`
+	Key             string          `json:"-" gorm:"not null;uniqueIndex"`
+	URL             string          `json:"url" gorm:"not null"`
+	ContentType     string          `json:"content_type" gorm:"not null"`
+	Size            int64           `json:"size" gorm:"not null"`
+	Width           int             `json:"width"`
+	Height          int             `json:"height"`
+	ThumbnailKey    string          `json:"-"`
+	ThumbnailURL    string          `json:"thumbnail_url,omitempty"`
+	ThumbnailStatus ThumbnailStatus `json:"thumbnail_status" gorm:"not null;index"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// BlobDeletion silinmesi gereken bir blob anahtarıdır. Görsel satırları
+// silinirken aynı transaction içinde yazılır, blob'lar arka planda silinir;
+// böylece veritabanı ile blob store arasında tutarsızlık kalmaz.
+type BlobDeletion struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  string `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Key       string `gorm:"not null"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"time"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+type ImportAction string
+
+const (
+	ImportActionCreate    ImportAction = "create"
+	ImportActionUpdate    ImportAction = "update"
+	ImportActionUnchanged ImportAction = "unchanged"
+	ImportActionRejected  ImportAction = "rejected"
+)
+
+// ImportJob bir toplu içe aktarma dosyasının asenkron işlenme durumudur.
+// DryRun işlerde satırlar aynı şekilde uygulanır ama transaction'lar geri
+// alınır; sayaçlar gerçek bir çalıştırmada ne olacağını gösterir. Actor ve
+// RequestID dosyayı yükleyen isteğindir ve işin denetim kayıtlarına yazılır.
+type ImportJob struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	TenantID      string       `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Format        ImportFormat `json:"format" gorm:"not null"`
+	DryRun        bool         `json:"dry_run" gorm:"not null"`
+	Status        ImportStatus `json:"status" gorm:"not null;index"`
+	TotalRows     int          `json:"total_rows" gorm:"not null;default:0"`
+	ProcessedRows int          `json:"processed_rows" gorm:"not null;default:0"`
+	Created       int          `json:"created" gorm:"not null;default:0"`
+	Updated       int          `json:"updated" gorm:"not null;default:0"`
+	Unchanged     int          `json:"unchanged" gorm:"not null;default:0"`
+	Rejected      int          `json:"rejected" gorm:"not null;default:0"`
+	Error         string       `json:"error,omitempty"`
+	Actor         string       `json:"actor"`
+	RequestID     string       `json:"request_id,omitempty"`
+	FilePath      string       `json:"-"`
+	CreatedAt     time.Time    `json:"created_at"`
+	StartedAt     *time.Time   `json:"started_at,omitempty"`
+	FinishedAt    *time.Time   `json:"finished_at,omitempty"`
+}
+
+// ImportRowResult oluşturulan, güncellenen ya da reddedilen bir satırın
+// sonucudur; değişmeyen satırlar yalnızca sayaçta tutulur. Raw, hata
+// raporunda satırın kendisini geri verebilmek için saklanır.
+type ImportRowResult struct {
+	ID        uint         `json:"-" gorm:"primaryKey"`
+	TenantID  string       `json:"-" gorm:"size:64;not null;default:'default';index"`
+	JobID     uint         `json:"-" gorm:"not null;index:idx_import_row_job"`
+	Line      int          `json:"line" gorm:"not null;index:idx_import_row_job"`
+	Key       string       `json:"key"`
+	Action    ImportAction `json:"action" gorm:"not null"`
+	ProductID *uint        `json:"product_id,omitempty"`
+	Message   string       `json:"message,omitempty"`
+	Raw       string       `json:"raw,omitempty"`
+}
+
+// ImportRecord dosyadaki bir satırın ayrıştırılmış halidir. nil alanlar
+// güncellemede mevcut değeri korur; yeni ürün için name ve price zorunludur.
+type ImportRecord struct {
+	Line             int
+	Raw              string
+	ExternalID       string
+	SKU              string
+	Name             *string
+	Description      *string
+	Price            *float64
+	Currency         *string
+	ReorderThreshold *int
+	TaxClass         *string
+	WeightKg         *float64
+	LengthCm         *float64
+	WidthCm          *float64
+	HeightCm         *float64
+	CategoryID       *uint
+	CategorySlug     string
+	ImageURL         *string
+}
+
+// Key satırın eşleştirme anahtarını raporlar için döndürür
+func (r ImportRecord) Key() string {
+	if r.ExternalID != "" {
+		return "external_id:" + r.ExternalID
+	}
+	if r.SKU != "" {
+		return "sku:" + r.SKU
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+)
+
+type MovementType string
+
+const (
+	MovementReceive  MovementType = "receive"
+	MovementShip     MovementType = "ship"
+	MovementAdjust   MovementType = "adjust"
+	MovementTransfer MovementType = "transfer"
+)
+
+// Pasif (Active=false) depolardaki stok satılabilir stoğa dahil edilmez
+type Warehouse struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TenantID  string    `json:"-" gorm:"size:64;not null;default:'default';uniqueIndex:idx_warehouse_tenant_code"`
+	Code      string    `json:"code" gorm:"not null;uniqueIndex:idx_warehouse_tenant_code"`
+	Name      string    `json:"name" gorm:"not null"`
+	Address   string    `json:"address"`
+	Active    bool      `json:"active" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type StockLevel struct {
+	ID          uint       `json:"-" gorm:"primaryKey"`
+	TenantID    string     `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID   uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_stock_level_location"`
+	WarehouseID uint       `json:"warehouse_id" gorm:"not null;uniqueIndex:idx_stock_level_location"`
+	Warehouse   *Warehouse `json:"warehouse,omitempty" gorm:"foreignKey:WarehouseID"`
+	Quantity    int        `json:"quantity" gorm:"not null;default:0"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// InventoryMovement stok hareketleri defterinin (ledger) değiştirilemez bir
+// satırıdır. Quantity receive/ship/transfer için pozitif miktar, adjust için
+// işaretli farktır. Ürün kalıcı silinse de defter korunur; bu yüzden
+// products tablosuna foreign key tanımlanmaz.
+type InventoryMovement struct {
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	TenantID        string       `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Type            MovementType `json:"type" gorm:"not null;index"`
+	ProductID       uint         `json:"product_id" gorm:"not null;index"`
+	FromWarehouseID *uint        `json:"from_warehouse_id,omitempty" gorm:"index"`
+	ToWarehouseID   *uint        `json:"to_warehouse_id,omitempty" gorm:"index"`
+	Quantity        int          `json:"quantity" gorm:"not null"`
+	Reason          string       `json:"reason" gorm:"not null"`
+	Actor           string       `json:"actor" gorm:"not null"`
+	CreatedAt       time.Time    `json:"created_at" gorm:"index"`
+}
+
+type StockAlertLevel string
+
+const (
+	StockAlertOK         StockAlertLevel = "ok"
+	StockAlertLow        StockAlertLevel = "low"
+	StockAlertOutOfStock StockAlertLevel = "out_of_stock"
+)
+
+// StockAlertState bir ürün için en son bildirilen stok seviyesini tutar;
+// seviye değişmedikçe aynı uyarı tekrar gönderilmez
+type StockAlertState struct {
+	ProductID uint            `json:"product_id" gorm:"primaryKey;autoIncrement:false"`
+	TenantID  string          `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Level     StockAlertLevel `json:"level" gorm:"not null"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// ProductInventory bir ürünün depo bazında stok dökümü
+type ProductInventory struct {
+	ProductID       uint         `json:"product_id"`
+	AvailableToSell int          `json:"available_to_sell"`
+	Levels          []StockLevel `json:"levels"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"time"
+)
+
+type PriceChangeSource string
+
+const (
+	PriceChangeInitial       PriceChangeSource = "initial"
+	PriceChangeManual        PriceChangeSource = "manual"
+	PriceChangeScheduled     PriceChangeSource = "scheduled"
+	PriceChangeScheduleEnded PriceChangeSource = "schedule_ended"
+	PriceChangeImport        PriceChangeSource = "import"
+)
+
+// PriceChange bir ürünün fiyat geçmişindeki değiştirilemez bir satırdır.
+// Ürün kalıcı silinince geçmişi de silinir.
+type PriceChange struct {
+	ID               uint              `json:"id" gorm:"primaryKey"`
+	TenantID         string            `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID        uint              `json:"product_id" gorm:"not null;index:idx_price_change_product"`
+	OldPrice         *float64          `json:"old_price"`
+	NewPrice         float64           `json:"new_price" gorm:"not null"`
+	Source           PriceChangeSource `json:"source" gorm:"not null"`
+	ScheduledPriceID *uint             `json:"scheduled_price_id,omitempty"`
+	EffectiveAt      time.Time         `json:"effective_at" gorm:"not null;index:idx_price_change_product"`
+}
+
+type ScheduledPriceStatus string
+
+const (
+	ScheduledPricePending   ScheduledPriceStatus = "pending"
+	ScheduledPriceActive    ScheduledPriceStatus = "active"
+	ScheduledPriceCompleted ScheduledPriceStatus = "completed"
+	ScheduledPriceCancelled ScheduledPriceStatus = "cancelled"
+)
+
+// ScheduledPrice StartsAt'te uygulanan ve EndsAt'te (verilmişse) önceki fiyata
+// geri dönen planlı fiyat değişikliğidir. OriginalPrice uygulama anındaki
+// fiyattır; bitişte fiyat arada elle değiştirilmediyse buna dönülür.
+type ScheduledPrice struct {
+	ID            uint                 `json:"id" gorm:"primaryKey"`
+	TenantID      string               `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID     uint                 `json:"product_id" gorm:"not null;index"`
+	Price         float64              `json:"price" gorm:"not null"`
+	StartsAt      time.Time            `json:"starts_at" gorm:"not null"`
+	EndsAt        *time.Time           `json:"ends_at"`
+	Status        ScheduledPriceStatus `json:"status" gorm:"not null;index"`
+	OriginalPrice *float64             `json:"original_price,omitempty"`
+	Reason        string               `json:"reason"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// PriceListEntry bir para birimindeki fiyat listesinin satırıdır; ürünün
+// ya da VariantID verilmişse varyantın o para birimindeki sabit fiyatıdır.
+// Listede olmayan fiyatlar kurla çevrilir.
+type PriceListEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TenantID  string    `json:"-" gorm:"size:64;not null;default:'default';index"`
+	Currency  string    `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_price_list_item"`
+	ProductID uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_price_list_item;index"`
+	VariantID uint      `json:"variant_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_price_list_item"`
+	Price     float64   `json:"price" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PriceSource gösterilen fiyatın nereden geldiğidir
+type PriceSource string
+
+const (
+	PriceSourceBase         PriceSource = "base"
+	PriceSourcePriceList    PriceSource = "price_list"
+	PriceSourceExchangeRate PriceSource = "exchange_rate"
+)
+
+// Money istenen para birimine çevrilmiş ve o para biriminin basamak
+// sayısına yuvarlanmış fiyattır
+type Money struct {
+	Currency string      `json:"currency"`
+	Amount   float64     `json:"amount"`
+	Source   PriceSource `json:"source"`
+	Rate     float64     `json:"rate,omitempty"`
+}
+
+// PriceTimeline GET /products/:id/prices yanıtı
+type PriceTimeline struct {
+	ProductID    uint             `json:"product_id"`
+	CurrentPrice float64          `json:"current_price"`
+	History      []PriceChange    `json:"history"`
+	Scheduled    []ScheduledPrice `json:"scheduled"`
+}
@@ -6,15 +6,43 @@ import (
 	"gorm.io/gorm"
 )
 
+// Stock, aktif depolardaki StockLevels toplamından türetilen satılabilir
+// stoktur ve yalnızca envanter hareketleriyle değişir. Stock, ReorderThreshold
+// değerine ya da altına düştüğünde LowStock uyarısı üretilir. ExternalID
+// katalog kaynağındaki (ERP, PIM) kimliktir ve toplu içe aktarmada eşleştirme
+// anahtarı olarak kullanılır. TaxClass basket servisindeki vergi tablosunda
+// ürünün hangi oranla vergilendirileceğini belirler. Ağırlık (kg) ve
+// boyutlar (cm) kargo ücreti hesaplamasında kullanılır; 0 bilinmiyor demektir.
+// Price ve varyant fiyatları Currency cinsindendir; DisplayPrice yalnızca
+// başka bir para birimi istendiğinde doldurulur ve saklanmaz. Name ve
+// Description katalog varsayılan dilindedir; okumada istenen dile
+// çevrildiğinde Locale kullanılan dili gösterir.
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
-	Stock       int            `json:"stock" gorm:"not null;default:0"`
-	Category    string         `json:"category"`
-	ImageURL    string         `json:"image_url"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID               uint             `json:"id" gorm:"primaryKey"`
+	TenantID         string           `json:"-" gorm:"size:64;not null;default:'default';uniqueIndex:idx_product_tenant_external_id"`
+	ExternalID       *string          `json:"external_id,omitempty" gorm:"uniqueIndex:idx_product_tenant_external_id"`
+	Name             string           `json:"name" gorm:"not null"`
+	Description      string           `json:"description"`
+	Locale           string           `json:"locale,omitempty" gorm:"-"`
+	Price            float64          `json:"price" gorm:"not null"`
+	Currency         string           `json:"currency" gorm:"size:3;not null;default:TRY"`
+	DisplayPrice     *Money           `json:"display_price,omitempty" gorm:"-"`
+	Stock            int              `json:"stock" gorm:"not null;default:0"`
+	ReorderThreshold int              `json:"reorder_threshold" gorm:"not null;default:0"`
+	TaxClass         string           `json:"tax_class" gorm:"size:32;not null;default:standard"`
+	WeightKg         float64          `json:"weight_kg" gorm:"not null;default:0"`
+	LengthCm         float64          `json:"length_cm" gorm:"not null;default:0"`
+	WidthCm          float64          `json:"width_cm" gorm:"not null;default:0"`
+	HeightCm         float64          `json:"height_cm" gorm:"not null;default:0"`
+	CategoryID       *uint            `json:"category_id" gorm:"index"`
+	Category         *Category        `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	ImageURL         string           `json:"image_url"`
+	Version          uint             `json:"version" gorm:"not null;default:1"`
+	Options          []ProductOption  `json:"options,omitempty" gorm:"foreignKey:ProductID"`
+	Variants         []ProductVariant `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
+	StockLevels      []StockLevel     `json:"stock_levels,omitempty" gorm:"foreignKey:ProductID"`
+	Images           []ProductImage   `json:"images,omitempty" gorm:"foreignKey:ProductID"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt   `json:"deleted_at,omitempty" gorm:"index"`
 }
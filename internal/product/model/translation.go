@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+)
+
+// ProductTranslation ürün metninin bir dildeki karşılığıdır. Product.Name ve
+// Description katalog varsayılan dilindedir; varsayılan dil için çeviri
+// tutulmaz. Boş Description, zincirdeki bir sonraki dile düşer.
+type ProductTranslation struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TenantID    string    `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID   uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_product_translation"`
+	Locale      string    `json:"locale" gorm:"size:35;not null;uniqueIndex:idx_product_translation;index"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation kategori adının bir dildeki karşılığıdır; slug
+// çevrilmez
+type CategoryTranslation struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TenantID   string    `json:"-" gorm:"size:64;not null;default:'default';index"`
+	CategoryID uint      `json:"category_id" gorm:"not null;uniqueIndex:idx_category_translation"`
+	Locale     string    `json:"locale" gorm:"size:35;not null;uniqueIndex:idx_category_translation;index"`
+	Name       string    `json:"name" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProductTranslations /products/:id/translations yanıtıdır; Missing henüz
+// çevirisi olmayan desteklenen dillerdir
+type ProductTranslations struct {
+	ProductID     uint                 `json:"product_id"`
+	DefaultLocale string               `json:"default_locale"`
+	Translations  []ProductTranslation `json:"translations"`
+	Missing       []string             `json:"missing"`
+}
+
+type CategoryTranslations struct {
+	CategoryID    uint                  `json:"category_id"`
+	DefaultLocale string                `json:"default_locale"`
+	Translations  []CategoryTranslation `json:"translations"`
+	Missing       []string              `json:"missing"`
+}
+
+// TranslationCoverage bir dilde çevirisi olan kayıtların oranıdır.
+// MissingDescription açıklaması olduğu halde çevirisinde açıklama
+// bulunmayan ürünlerdir; bu ürünler Translated'a dahildir.
+type TranslationCoverage struct {
+	Total              int64   `json:"total"`
+	Translated         int64   `json:"translated"`
+	Missing            int64   `json:"missing"`
+	MissingDescription int64   `json:"missing_description,omitempty"`
+	Percent            float64 `json:"percent"`
+}
+
+type LocaleCompleteness struct {
+	Locale     string              `json:"locale"`
+	Products   TranslationCoverage `json:"products"`
+	Categories TranslationCoverage `json:"categories"`
+}
+
+// TranslationReport GET /translations/completeness yanıtıdır
+type TranslationReport struct {
+	DefaultLocale string               `json:"default_locale"`
+	Locales       []LocaleCompleteness `json:"locales"`
+}
+
+// MissingTranslation bir dilde çevirisi olmayan kaydın varsayılan dildeki adıdır
+type MissingTranslation struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
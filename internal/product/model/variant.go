@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProductOption bir ürünün varyant eksenidir (ör. size: S, M, L)
+type ProductOption struct {
+	ID        uint     `json:"-" gorm:"primaryKey"`
+	TenantID  string   `json:"-" gorm:"size:64;not null;default:'default';index"`
+	ProductID uint     `json:"-" gorm:"not null;index"`
+	Name      string   `json:"name" gorm:"not null"`
+	Values    []string `json:"values" gorm:"serializer:json;not null"`
+	Position  int      `json:"position" gorm:"not null;default:0"`
+}
+
+// ProductVariant kendi fiyatı, stoğu ve görseli olan satılabilir bir SKU'dur;
+// Attributes her option ekseni için seçilen değeri tutar. Price ürünün para
+// birimindedir; DisplayPrice istenen para birimindeki fiyattır ve saklanmaz.
+type ProductVariant struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	TenantID     string            `json:"-" gorm:"size:64;not null;default:'default';uniqueIndex:idx_variant_tenant_sku"`
+	ProductID    uint              `json:"product_id" gorm:"not null;index"`
+	SKU          string            `json:"sku" gorm:"not null;uniqueIndex:idx_variant_tenant_sku"`
+	Attributes   map[string]string `json:"attributes" gorm:"serializer:json;not null"`
+	Price        float64           `json:"price" gorm:"not null"`
+	DisplayPrice *Money            `json:"display_price,omitempty" gorm:"-"`
+	Stock        int               `json:"stock" gorm:"not null;default:0"`
+	ImageURL     string            `json:"image_url"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt    `json:"deleted_at,omitempty" gorm:"index"`
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+)
+
+// AuditRepository denetim kayıtlarını yalnızca okur; kayıtlar değişikliği
+// yapan repository'lerin transaction'ları içinde recordAudit ile eklenir
+type AuditRepository interface {
+	List(filter model.AuditFilter) ([]model.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) List(filter model.AuditFilter) ([]model.AuditEntry, error) {
+	query := r.db.Model(&model.AuditEntry{})
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.Before != 0 {
+		query = query.Where("id < ?", filter.Before)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []model.AuditEntry
+	err := query.Order("id DESC").Find(&entries).Error
+	return entries, err
+}
+
+// recordAudit bir ürün değişikliğini çağıranın transaction'ında kaydeder;
+// değişiklik geri alınırsa kayıt da geri alınır
+func recordAudit(tx *gorm.DB, actor model.Actor, action model.AuditAction, productID uint, changes map[string]model.AuditChange) error {
+	if actor.Name == "" {
+		actor.Name = "anonymous"
+	}
+	if changes == nil {
+		changes = map[string]model.AuditChange{}
+	}
+	return tx.Create(&model.AuditEntry{
+		Entity:    model.AuditEntityProduct,
+		EntityID:  productID,
+		Action:    action,
+		Actor:     actor.Name,
+		Source:    actor.Source,
+		RequestID: actor.RequestID,
+		Changes:   changes,
+	}).Error
+}
+
+// diffProduct düzenlenebilir alanlardan değişenleri döndürür; before nil ise
+// oluşturma, after nil ise kalıcı silme olarak tüm alanlar yazılır
+func diffProduct(before, after *model.Product) map[string]model.AuditChange {
+	old, current := productAuditFields(before), productAuditFields(after)
+	changes := make(map[string]model.AuditChange)
+	for _, column := range editableColumns {
+		if old[column] != current[column] {
+			changes[column] = model.AuditChange{Old: old[column], New: current[column]}
+		}
+	}
+	return changes
+}
+
+// deletedAtChange çöp kutusuna taşıma ve geri yükleme kayıtlarının farkıdır
+func deletedAtChange(old, current *time.Time) map[string]model.AuditChange {
+	change := model.AuditChange{}
+	if old != nil {
+		change.Old = *old
+	}
+	if current != nil {
+		change.New = *current
+	}
+	return map[string]model.AuditChange{"deleted_at": change}
+}
+
+// productAuditFields karşılaştırılabilir olsun diye pointer alanları
+// değerlerine çevirir; nil ürün için tüm alanlar nil'dir
+func productAuditFields(p *model.Product) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		"external_id":       nil,
+		"name":              p.Name,
+		"description":       p.Description,
+		"price":             p.Price,
+		"currency":          p.Currency,
+		"reorder_threshold": p.ReorderThreshold,
+		"tax_class":         p.TaxClass,
+		"weight_kg":         p.WeightKg,
+		"length_cm":         p.LengthCm,
+		"width_cm":          p.WidthCm,
+		"height_cm":         p.HeightCm,
+		"category_id":       nil,
+		"image_url":         p.ImageURL,
+	}
+	if p.ExternalID != nil {
+		fields["external_id"] = *p.ExternalID
+	}
+	if p.CategoryID != nil {
+		fields["category_id"] = *p.CategoryID
+	}
+	return fields
+}
+
+// Varyant, option, fiyat listesi, çeviri ve görsel değişiklikleri ürünün
+// update kaydı olarak yazılır; alan adları alt kaydı gösterir
+// (variants.12.price, price_lists.EUR, translations.de.name, images)
+
+// diffFields before ile after arasında değişen alanları prefix ile changes'e
+// ekler; nil taraf kaydın oluşturulduğunu ya da silindiğini gösterir
+func diffFields(changes map[string]model.AuditChange, prefix string, before, after map[string]interface{}) {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes[prefix+name] = model.AuditChange{Old: before[name], New: after[name]}
+		}
+	}
+}
+
+// diffVariant stok hariç varyant alanlarını karşılaştırır; stok değişiklikleri
+// envanter hareketlerinde kayıtlıdır
+func diffVariant(before, after *model.ProductVariant) map[string]model.AuditChange {
+	changes := make(map[string]model.AuditChange)
+	id := after
+	if id == nil {
+		id = before
+	}
+	diffFields(changes, fmt.Sprintf("variants.%d.", id.ID), variantAuditFields(before), variantAuditFields(after))
+	return changes
+}
+
+func variantAuditFields(v *model.ProductVariant) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"sku":        v.SKU,
+		"attributes": v.Attributes,
+		"price":      v.Price,
+		"image_url":  v.ImageURL,
+	}
+}
+
+// optionsAuditValue option eksenlerini sıralarıyla, kimlikleri olmadan döndürür
+func optionsAuditValue(options []model.ProductOption) []map[string]interface{} {
+	value := make([]map[string]interface{}, 0, len(options))
+	for _, option := range options {
+		value = append(value, map[string]interface{}{"name": option.Name, "values": option.Values})
+	}
+	return value
+}
+
+func priceListAuditField(currency string, variantID uint) string {
+	if variantID == 0 {
+		return "price_lists." + currency
+	}
+	return fmt.Sprintf("price_lists.%s.variants.%d", currency, variantID)
+}
+
+func translationAuditFields(t *model.ProductTranslation) map[string]interface{} {
+	if t == nil {
+		return nil
+	}
+	return map[string]interface{}{"name": t.Name, "description": t.Description}
+}
+
+// productImageURLs ürünün görsellerini sıralarıyla döndürür; görsel
+// değişiklikleri bu listenin önceki ve sonraki hali olarak yazılır
+func productImageURLs(tx *gorm.DB, productID uint) ([]string, error) {
+	urls := []string{}
+	err := tx.Model(&model.ProductImage{}).
+		Where("product_id = ?", productID).
+		Order("position ASC, id ASC").
+		Pluck("url", &urls).Error
+	return urls, err
+}
+
+func recordImageAudit(tx *gorm.DB, actor model.Actor, productID uint, before []string) error {
+	after, err := productImageURLs(tx, productID)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, model.AuditUpdate, productID, map[string]model.AuditChange{
+		"images": {Old: before, New: after},
+	})
+}
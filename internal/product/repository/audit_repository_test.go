@@ -0,0 +1,264 @@
+package repository_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+var testActor = model.Actor{Name: "editor", Source: model.AuditSourceHTTP, RequestID: "req-1"}
+
+type auditFixture struct {
+	db      *gorm.DB
+	product *model.Product
+	audit   repository.AuditRepository
+	seen    int
+}
+
+func newAuditFixture(t *testing.T) *auditFixture {
+	t.Helper()
+	databasetest.Open(t)
+	db := database.ForTenant("acme")
+	product := &model.Product{Name: "Mug", Price: 10, Currency: "TRY"}
+	if err := repository.NewProductRepository(db).Create(product, testActor); err != nil {
+		t.Fatal(err)
+	}
+	return &auditFixture{db: db, product: product, audit: repository.NewAuditRepository(db), seen: 1}
+}
+
+// next ürünün son yazmadan sonra tek bir yeni update kaydı aldığını doğrular
+// ve o kaydın değişikliklerini döndürür
+func (f *auditFixture) next(t *testing.T) map[string]model.AuditChange {
+	t.Helper()
+	entries, err := f.audit.List(model.AuditFilter{Entity: model.AuditEntityProduct, EntityID: f.product.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != f.seen+1 {
+		t.Fatalf("product has %d audit entries, want %d", len(entries), f.seen+1)
+	}
+	f.seen++
+
+	entry := entries[0]
+	if entry.Action != model.AuditUpdate || entry.Actor != testActor.Name || entry.Source != testActor.Source || entry.RequestID != testActor.RequestID {
+		t.Fatalf("entry = %+v", entry)
+	}
+	return entry.Changes
+}
+
+// assertNoEntry başarısız yazmanın denetim kaydı bırakmadığını doğrular
+func (f *auditFixture) assertNoEntry(t *testing.T) {
+	t.Helper()
+	entries, err := f.audit.List(model.AuditFilter{Entity: model.AuditEntityProduct, EntityID: f.product.ID})
+	if err != nil || len(entries) != f.seen {
+		t.Fatalf("product has %d audit entries (%v), want %d", len(entries), err, f.seen)
+	}
+}
+
+func assertChangedFields(t *testing.T, changes map[string]model.AuditChange, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(changes))
+	for field := range changes {
+		got = append(got, field)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changed fields = %v, want %v", got, want)
+	}
+}
+
+func TestVariantWritesAreAudited(t *testing.T) {
+	f := newAuditFixture(t)
+	repo := repository.NewVariantRepository(f.db)
+
+	options := []model.ProductOption{{Name: "color", Values: []string{"red", "blue"}}}
+	if err := repo.ReplaceOptions(f.product.ID, options, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes := f.next(t)
+	assertChangedFields(t, changes, "options")
+	if old, ok := changes["options"].Old.([]interface{}); !ok || len(old) != 0 {
+		t.Fatalf("options old = %#v", changes["options"].Old)
+	}
+
+	variant := &model.ProductVariant{ProductID: f.product.ID, SKU: "MUG-RED", Attributes: map[string]string{"color": "red"}, Price: 11, Stock: 5}
+	if err := repo.Create(variant, testActor); err != nil {
+		t.Fatal(err)
+	}
+	prefix := fmt.Sprintf("variants.%d.", variant.ID)
+	changes = f.next(t)
+	assertChangedFields(t, changes, prefix+"sku", prefix+"attributes", prefix+"price", prefix+"image_url")
+	if change := changes[prefix+"sku"]; change.Old != nil || change.New != "MUG-RED" {
+		t.Fatalf("sku change = %+v", change)
+	}
+
+	// Stok değişikliği envanter hareketlerinde tutulur, denetim kaydına girmez
+	updated := *variant
+	updated.Price = 12.5
+	updated.Stock = 9
+	if err := repo.Update(&updated, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes = f.next(t)
+	assertChangedFields(t, changes, prefix+"price")
+	if change := changes[prefix+"price"]; change.Old != 11.0 || change.New != 12.5 {
+		t.Fatalf("price change = %+v", change)
+	}
+
+	if err := repo.Delete(variant.ID, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes = f.next(t)
+	assertChangedFields(t, changes, prefix+"sku", prefix+"attributes", prefix+"price", prefix+"image_url")
+	if change := changes[prefix+"sku"]; change.Old != "MUG-RED" || change.New != nil {
+		t.Fatalf("sku change = %+v", change)
+	}
+
+	missing := model.ProductVariant{ID: variant.ID, ProductID: f.product.ID, SKU: "MUG-RED"}
+	if err := repo.Update(&missing, testActor); err == nil {
+		t.Fatal("update of deleted variant succeeded")
+	}
+	f.assertNoEntry(t)
+}
+
+func TestPriceListWritesAreAudited(t *testing.T) {
+	f := newAuditFixture(t)
+	repo := repository.NewPriceListRepository(f.db)
+
+	if err := repo.Upsert(&model.PriceListEntry{Currency: "EUR", ProductID: f.product.ID, Price: 3}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes := f.next(t)
+	assertChangedFields(t, changes, "price_lists.EUR")
+	if change := changes["price_lists.EUR"]; change.Old != nil || change.New != 3.0 {
+		t.Fatalf("change = %+v", change)
+	}
+
+	if err := repo.Upsert(&model.PriceListEntry{Currency: "EUR", ProductID: f.product.ID, Price: 4}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if change := f.next(t)["price_lists.EUR"]; change.Old != 3.0 || change.New != 4.0 {
+		t.Fatalf("change = %+v", change)
+	}
+
+	if err := repo.Upsert(&model.PriceListEntry{Currency: "EUR", ProductID: f.product.ID, VariantID: 7, Price: 5}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	assertChangedFields(t, f.next(t), "price_lists.EUR.variants.7")
+
+	deleted, err := repo.Delete("EUR", f.product.ID, 0, testActor)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	if change := f.next(t)["price_lists.EUR"]; change.Old != 4.0 || change.New != nil {
+		t.Fatalf("change = %+v", change)
+	}
+
+	// Olmayan fiyatı silmek kayıt bırakmaz
+	if deleted, err := repo.Delete("EUR", f.product.ID, 0, testActor); err != nil || deleted {
+		t.Fatalf("second Delete = %v, %v", deleted, err)
+	}
+	f.assertNoEntry(t)
+}
+
+func TestTranslationWritesAreAudited(t *testing.T) {
+	f := newAuditFixture(t)
+	repo := repository.NewTranslationRepository(f.db)
+
+	if err := repo.UpsertProductTranslation(&model.ProductTranslation{ProductID: f.product.ID, Locale: "de", Name: "Tasse"}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	assertChangedFields(t, f.next(t), "translations.de.name", "translations.de.description")
+
+	if err := repo.UpsertProductTranslation(&model.ProductTranslation{ProductID: f.product.ID, Locale: "de", Name: "Becher"}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes := f.next(t)
+	assertChangedFields(t, changes, "translations.de.name")
+	if change := changes["translations.de.name"]; change.Old != "Tasse" || change.New != "Becher" {
+		t.Fatalf("change = %+v", change)
+	}
+
+	deleted, err := repo.DeleteProductTranslation(f.product.ID, "de", testActor)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v", deleted, err)
+	}
+	changes = f.next(t)
+	assertChangedFields(t, changes, "translations.de.name", "translations.de.description")
+	if change := changes["translations.de.name"]; change.Old != "Becher" || change.New != nil {
+		t.Fatalf("change = %+v", change)
+	}
+
+	if deleted, err := repo.DeleteProductTranslation(f.product.ID, "de", testActor); err != nil || deleted {
+		t.Fatalf("second Delete = %v, %v", deleted, err)
+	}
+	f.assertNoEntry(t)
+}
+
+func TestImageWritesAreAudited(t *testing.T) {
+	f := newAuditFixture(t)
+	repo := repository.NewImageRepository(f.db)
+
+	images := []model.ProductImage{
+		{ProductID: f.product.ID, Key: "products/1/a.png", URL: "https://cdn/a.png", ContentType: "image/png"},
+		{ProductID: f.product.ID, Key: "products/1/b.png", URL: "https://cdn/b.png", ContentType: "image/png"},
+	}
+	if err := repo.Create(images, 10, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes := f.next(t)
+	assertChangedFields(t, changes, "images")
+	assertImages(t, changes["images"].Old)
+	assertImages(t, changes["images"].New, "https://cdn/a.png", "https://cdn/b.png")
+
+	// Sınır ürün kilitliyken mevcut görsellerle birlikte sayılır
+	extra := []model.ProductImage{{ProductID: f.product.ID, Key: "products/1/c.png", URL: "https://cdn/c.png", ContentType: "image/png"}}
+	if err := repo.Create(extra, 2, testActor); !errors.Is(err, repository.ErrImageLimitReached) {
+		t.Fatalf("Create over the limit = %v, want ErrImageLimitReached", err)
+	}
+	if count, err := repo.CountByProductID(f.product.ID); err != nil || count != 2 {
+		t.Fatalf("images after rejected Create = %d (%v)", count, err)
+	}
+	f.assertNoEntry(t)
+
+	if err := repo.Reorder(f.product.ID, []uint{images[1].ID, images[0].ID}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes = f.next(t)
+	assertImages(t, changes["images"].Old, "https://cdn/a.png", "https://cdn/b.png")
+	assertImages(t, changes["images"].New, "https://cdn/b.png", "https://cdn/a.png")
+
+	if err := repo.Reorder(f.product.ID, []uint{images[0].ID}, testActor); err != repository.ErrImageOrderMismatch {
+		t.Fatalf("partial reorder = %v", err)
+	}
+	f.assertNoEntry(t)
+
+	if err := repo.Delete(f.product.ID, images[1].ID, testActor); err != nil {
+		t.Fatal(err)
+	}
+	changes = f.next(t)
+	assertImages(t, changes["images"].Old, "https://cdn/b.png", "https://cdn/a.png")
+	assertImages(t, changes["images"].New, "https://cdn/a.png")
+}
+
+func assertImages(t *testing.T, value interface{}, want ...string) {
+	t.Helper()
+	urls, ok := value.([]interface{})
+	if !ok || len(urls) != len(want) {
+		t.Fatalf("images = %#v, want %v", value, want)
+	}
+	for i := range want {
+		if urls[i] != want[i] {
+			t.Fatalf("images = %v, want %v", urls, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/tenant"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(category *model.Category) error
+	GetByID(id uint) (*model.Category, error)
+	GetBySlug(slug string) (*model.Category, error)
+	GetAll() ([]model.Category, error)
+	Update(category *model.Category) error
+	Delete(id uint) error
+	GetDescendantIDs(id uint) ([]uint, error)
+	CountChildren(id uint) (int64, error)
+	CountProducts(id uint) (int64, error)
+	CountProductsPerCategory() (map[uint]int64, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *model.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) GetByID(id uint) (*model.Category, error) {
+	var category model.Category
+	err := r.db.First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetBySlug(slug string) (*model.Category, error) {
+	var category model.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetAll() ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Order("position ASC, name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) Update(category *model.Category) error {
+	return r.db.Model(category).
+		Select("name", "slug", "parent_id", "position", "updated_at").
+		Updates(category).Error
+}
+
+func (r *categoryRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&model.CategoryTranslation{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetDescendantIDs kategorinin kendisi dahil tüm alt kategorilerinin id'lerini döndürür
+func (r *categoryRepository) GetDescendantIDs(id uint) ([]uint, error) {
+	var ids []uint
+	// Raw SQL'e tenant koşulu otomatik eklenmez
+	scope := tenant.ID(r.db.Statement.Context)
+	err := r.db.Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ? AND tenant_id = ?
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.tenant_id = ?
+		)
+		SELECT id FROM tree`, id, scope, scope).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *categoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountProducts çöp kutusundaki ürünleri de sayar; onlar da kategoriye referans verir
+func (r *categoryRepository) CountProducts(id uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.Product{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepository) CountProductsPerCategory() (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	err := r.db.Model(&model.Product{}).
+		Select("category_id, COUNT(*) AS count").
+		Where("category_id IS NOT NULL").
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"errors"
+
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrImageOrderMismatch yeni sıralama ürünün görsellerinin tamamını içermediğinde döner
+var ErrImageOrderMismatch = errors.New("image order must list every image of the product exactly once")
+
+// ErrImageLimitReached eklenen görsellerle ürünün görsel sayısı sınırı aştığında döner
+var ErrImageLimitReached = errors.New("product has reached the image limit")
+
+type ImageRepository interface {
+	Create(images []model.ProductImage, maxPerProduct int, actor model.Actor) error
+	GetByProductID(productID uint) ([]model.ProductImage, error)
+	Get(imageID uint) (*model.ProductImage, error)
+	CountByProductID(productID uint) (int64, error)
+	Reorder(productID uint, imageIDs []uint, actor model.Actor) error
+	Delete(productID, imageID uint, actor model.Actor) error
+	SetThumbnail(imageID uint, key, url string, status model.ThumbnailStatus) error
+	GetPendingThumbnails(limit int) ([]model.ProductImage, error)
+	GetBlobDeletions(limit int) ([]model.BlobDeletion, error)
+	DeleteBlobDeletions(ids []uint) error
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db: db}
+}
+
+// Create görselleri mevcut görsellerin sonuna ekler, ana görseli ve ürün
+// versiyonunu günceller. Görsel sayısı ürün satırı kilitliyken sayılır;
+// eşzamanlı yüklemeler birlikte maxPerProduct'ı aşamaz. Görsel değişiklikleri
+// ürünün denetim kaydına aynı transaction içinde yazılır.
+func (r *imageRepository) Create(images []model.ProductImage, maxPerProduct int, actor model.Actor) error {
+	if len(images) == 0 {
+		return nil
+	}
+	productID := images[0].ProductID
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		before, err := productImageURLs(tx, productID)
+		if err != nil {
+			return err
+		}
+		if len(before)+len(images) > maxPerProduct {
+			return ErrImageLimitReached
+		}
+
+		var maxPosition *int
+		if err := tx.Model(&model.ProductImage{}).
+			Where("product_id = ?", productID).
+			Select("MAX(position)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+
+		next := 0
+		if maxPosition != nil {
+			next = *maxPosition + 1
+		}
+		for i := range images {
+			images[i].Position = next + i
+		}
+
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+		if err := syncPrimaryImage(tx, productID, ""); err != nil {
+			return err
+		}
+		return recordImageAudit(tx, actor, productID, before)
+	})
+}
+
+func (r *imageRepository) GetByProductID(productID uint) ([]model.ProductImage, error) {
+	var images []model.ProductImage
+	err := r.db.Where("product_id = ?", productID).Order("position ASC, id ASC").Find(&images).Error
+	return images, err
+}
+
+func (r *imageRepository) Get(imageID uint) (*model.ProductImage, error) {
+	var image model.ProductImage
+	err := r.db.First(&image, imageID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *imageRepository) CountByProductID(productID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ProductImage{}).Where("product_id = ?", productID).Count(&count).Error
+	return count, err
+}
+
+func (r *imageRepository) Reorder(productID uint, imageIDs []uint, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+
+		var existing []uint
+		if err := tx.Model(&model.ProductImage{}).Where("product_id = ?", productID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !sameIDs(existing, imageIDs) {
+			return ErrImageOrderMismatch
+		}
+		before, err := productImageURLs(tx, productID)
+		if err != nil {
+			return err
+		}
+
+		for position, id := range imageIDs {
+			if err := tx.Model(&model.ProductImage{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		if err := syncPrimaryImage(tx, productID, ""); err != nil {
+			return err
+		}
+		return recordImageAudit(tx, actor, productID, before)
+	})
+}
+
+func (r *imageRepository) Delete(productID, imageID uint, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		before, err := productImageURLs(tx, productID)
+		if err != nil {
+			return err
+		}
+
+		var image model.ProductImage
+		if err := tx.Where("product_id = ?", productID).First(&image, imageID).Error; err != nil {
+			return err
+		}
+		if err := deleteImages(tx, "id = ?", image.ID); err != nil {
+			return err
+		}
+		if err := syncPrimaryImage(tx, productID, image.URL); err != nil {
+			return err
+		}
+		return recordImageAudit(tx, actor, productID, before)
+	})
+}
+
+func (r *imageRepository) SetThumbnail(imageID uint, key, url string, status model.ThumbnailStatus) error {
+	return r.db.Model(&model.ProductImage{}).
+		Where("id = ?", imageID).
+		Updates(map[string]interface{}{
+			"thumbnail_key":    key,
+			"thumbnail_url":    url,
+			"thumbnail_status": status,
+		}).Error
+}
+
+func (r *imageRepository) GetPendingThumbnails(limit int) ([]model.ProductImage, error) {
+	var images []model.ProductImage
+	err := r.db.Where("thumbnail_status = ?", model.ThumbnailPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&images).Error
+	return images, err
+}
+
+func (r *imageRepository) GetBlobDeletions(limit int) ([]model.BlobDeletion, error) {
+	var deletions []model.BlobDeletion
+	err := r.db.Order("id ASC").Limit(limit).Find(&deletions).Error
+	return deletions, err
+}
+
+func (r *imageRepository) DeleteBlobDeletions(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&model.BlobDeletion{}, ids).Error
+}
+
+// deleteImages koşula uyan görsel satırlarını siler ve blob'larını silinmek
+// üzere kuyruğa yazar
+func deleteImages(tx *gorm.DB, query string, args ...interface{}) error {
+	var images []model.ProductImage
+	if err := tx.Where(query, args...).Find(&images).Error; err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	deletions := make([]model.BlobDeletion, 0, len(images)*2)
+	ids := make([]uint, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, image.ID)
+		deletions = append(deletions, model.BlobDeletion{Key: image.Key})
+		if image.ThumbnailKey != "" {
+			deletions = append(deletions, model.BlobDeletion{Key: image.ThumbnailKey})
+		}
+	}
+
+	if err := tx.Create(&deletions).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&model.ProductImage{}, ids).Error
+}
+
+// syncPrimaryImage ilk görselin URL'sini products.image_url'e yazar ve
+// versiyonu artırır. Hiç görsel kalmadıysa image_url yalnızca silinen görseli
+// gösteriyorsa temizlenir; elle girilmiş harici URL'lere dokunulmaz.
+func syncPrimaryImage(tx *gorm.DB, productID uint, removedURL string) error {
+	var primary model.ProductImage
+	err := tx.Where("product_id = ?", productID).Order("position ASC, id ASC").First(&primary).Error
+
+	updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+	switch {
+	case err == nil:
+		updates["image_url"] = primary.URL
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if removedURL != "" {
+			updates["image_url"] = gorm.Expr("CASE WHEN image_url = ? THEN '' ELSE image_url END", removedURL)
+		}
+	default:
+		return err
+	}
+
+	return tx.Model(&model.Product{}).Where("id = ?", productID).Updates(updates).Error
+}
+
+func lockProduct(tx *gorm.DB, productID uint) error {
+	var product model.Product
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&product, productID).Error
+}
+
+func sameIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[uint]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
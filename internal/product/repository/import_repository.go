@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDryRun dry-run batch'lerinde transaction'ı geri almak için kullanılır
+var errDryRun = errors.New("dry run")
+
+type ImportRepository interface {
+	CreateJob(job *model.ImportJob) error
+	GetJob(id uint) (*model.ImportJob, error)
+	UpdateJob(job *model.ImportJob) error
+	FailInterruptedJobs(reason string) (int64, error)
+	SaveRowResults(results []model.ImportRowResult) error
+	GetRowResults(jobID uint, action model.ImportAction) ([]model.ImportRowResult, error)
+	ApplyBatch(records []model.ImportRecord, dryRun bool, actor model.Actor) ([]model.ImportRowResult, error)
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) CreateJob(job *model.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importRepository) GetJob(id uint) (*model.ImportJob, error) {
+	var job model.ImportJob
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importRepository) UpdateJob(job *model.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// FailInterruptedJobs servis yeniden başladığında yarıda kalmış işleri kapatır
+func (r *importRepository) FailInterruptedJobs(reason string) (int64, error) {
+	result := r.db.Model(&model.ImportJob{}).
+		Where("status IN ?", []model.ImportStatus{model.ImportPending, model.ImportRunning}).
+		Updates(map[string]interface{}{
+			"status":      model.ImportFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *importRepository) SaveRowResults(results []model.ImportRowResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(results, 500).Error
+}
+
+func (r *importRepository) GetRowResults(jobID uint, action model.ImportAction) ([]model.ImportRowResult, error) {
+	query := r.db.Where("job_id = ?", jobID)
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	var results []model.ImportRowResult
+	err := query.Order("line ASC").Find(&results).Error
+	return results, err
+}
+
+// ApplyBatch kayıtları tek transaction içinde upsert eder. Her satır kendi
+// savepoint'inde çalışır; veritabanı hatası alan satır reddedilir ama
+// batch'in geri kalanı etkilenmez. dryRun ise transaction sonunda geri alınır.
+func (r *importRepository) ApplyBatch(records []model.ImportRecord, dryRun bool, actor model.Actor) ([]model.ImportRowResult, error) {
+	results := make([]model.ImportRowResult, 0, len(records))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		results = results[:0]
+		for _, record := range records {
+			if err := tx.SavePoint("import_row").Error; err != nil {
+				return err
+			}
+
+			result, err := applyImportRecord(tx, record, actor)
+			if err != nil {
+				if rollbackErr := tx.RollbackTo("import_row").Error; rollbackErr != nil {
+					return rollbackErr
+				}
+				result = rejectedRow(record, err.Error())
+			}
+			if dryRun && result.Action == model.ImportActionCreate {
+				result.ProductID = nil
+			}
+			results = append(results, result)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return results, err
+}
+
+// applyImportRecord satırı external_id, yoksa varyant SKU'su üzerinden
+// eşleştirir. Yalnızca SKU ile gelen satırlar mevcut ürünü güncelleyebilir;
+// yeni ürün oluşturmak için external_id gerekir.
+func applyImportRecord(tx *gorm.DB, record model.ImportRecord, actor model.Actor) (model.ImportRowResult, error) {
+	var product model.Product
+	found := true
+
+	if record.ExternalID != "" {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("external_id = ?", record.ExternalID).
+			First(&product).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			found = false
+		} else if err != nil {
+			return model.ImportRowResult{}, err
+		}
+	} else {
+		var variant model.ProductVariant
+		err := tx.Where("sku = ?", record.SKU).First(&variant).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rejectedRow(record, "no variant with this sku"), nil
+		}
+		if err != nil {
+			return model.ImportRowResult{}, err
+		}
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, variant.ProductID).Error
+		if err != nil {
+			return model.ImportRowResult{}, err
+		}
+	}
+
+	if found && product.DeletedAt.Valid {
+		return rejectedRow(record, "product is in the trash"), nil
+	}
+
+	categoryID, message, err := resolveImportCategory(tx, record)
+	if err != nil {
+		return model.ImportRowResult{}, err
+	}
+	if message != "" {
+		return rejectedRow(record, message), nil
+	}
+
+	if !found {
+		return createImportedProduct(tx, record, categoryID, actor)
+	}
+	return updateImportedProduct(tx, record, &product, categoryID, actor)
+}
+
+func createImportedProduct(tx *gorm.DB, record model.ImportRecord, categoryID *uint, actor model.Actor) (model.ImportRowResult, error) {
+	if record.Name == nil || record.Price == nil {
+		return rejectedRow(record, "name and price are required for new products"), nil
+	}
+
+	externalID := record.ExternalID
+	product := model.Product{
+		ExternalID: &externalID,
+		Name:       *record.Name,
+		Price:      *record.Price,
+		CategoryID: categoryID,
+		Version:    1,
+	}
+	if record.Description != nil {
+		product.Description = *record.Description
+	}
+	if record.ReorderThreshold != nil {
+		product.ReorderThreshold = *record.ReorderThreshold
+	}
+	if record.ImageURL != nil {
+		product.ImageURL = *record.ImageURL
+	}
+	if record.TaxClass != nil {
+		product.TaxClass = *record.TaxClass
+	}
+	if record.Currency != nil {
+		product.Currency = *record.Currency
+	}
+	setFloat := func(target *float64, value *float64) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	setFloat(&product.WeightKg, record.WeightKg)
+	setFloat(&product.LengthCm, record.LengthCm)
+	setFloat(&product.WidthCm, record.WidthCm)
+	setFloat(&product.HeightCm, record.HeightCm)
+
+	if err := tx.Omit(clause.Associations).Create(&product).Error; err != nil {
+		return model.ImportRowResult{}, err
+	}
+	if err := recordAudit(tx, actor, model.AuditCreate, product.ID, diffProduct(nil, &product)); err != nil {
+		return model.ImportRowResult{}, err
+	}
+	err := recordPriceChange(tx, &model.PriceChange{
+		ProductID:   product.ID,
+		NewPrice:    product.Price,
+		Source:      model.PriceChangeImport,
+		EffectiveAt: product.CreatedAt,
+	})
+	if err != nil {
+		return model.ImportRowResult{}, err
+	}
+
+	return model.ImportRowResult{
+		Line:      record.Line,
+		Key:       record.Key(),
+		Action:    model.ImportActionCreate,
+		ProductID: &product.ID,
+	}, nil
+}
+
+func updateImportedProduct(tx *gorm.DB, record model.ImportRecord, product *model.Product, categoryID *uint, actor model.Actor) (model.ImportRowResult, error) {
+	before := *product
+	oldPrice := product.Price
+	changed := false
+
+	setString := func(target *string, value *string) {
+		if value != nil && *target != *value {
+			*target = *value
+			changed = true
+		}
+	}
+	setString(&product.Name, record.Name)
+	setString(&product.Description, record.Description)
+	setString(&product.ImageURL, record.ImageURL)
+	setString(&product.TaxClass, record.TaxClass)
+	setString(&product.Currency, record.Currency)
+
+	setFloat := func(target *float64, value *float64) {
+		if value != nil && *target != *value {
+			*target = *value
+			changed = true
+		}
+	}
+	setFloat(&product.WeightKg, record.WeightKg)
+	setFloat(&product.LengthCm, record.LengthCm)
+	setFloat(&product.WidthCm, record.WidthCm)
+	setFloat(&product.HeightCm, record.HeightCm)
+
+	if record.Price != nil && product.Price != *record.Price {
+		product.Price = *record.Price
+		changed = true
+	}
+	if record.ReorderThreshold != nil && product.ReorderThreshold != *record.ReorderThreshold {
+		product.ReorderThreshold = *record.ReorderThreshold
+		changed = true
+	}
+	if categoryID != nil && (product.CategoryID == nil || *product.CategoryID != *categoryID) {
+		product.CategoryID = categoryID
+		changed = true
+	}
+
+	result := model.ImportRowResult{
+		Line:      record.Line,
+		Key:       record.Key(),
+		Action:    model.ImportActionUnchanged,
+		ProductID: &product.ID,
+	}
+	if !changed {
+		return result, nil
+	}
+
+	product.Version++
+	columns := append(append([]string{}, editableColumns...), "version", "updated_at")
+	if err := tx.Model(product).Select(columns).Omit(clause.Associations).Updates(product).Error; err != nil {
+		return model.ImportRowResult{}, err
+	}
+	if err := recordAudit(tx, actor, model.AuditUpdate, product.ID, diffProduct(&before, product)); err != nil {
+		return model.ImportRowResult{}, err
+	}
+
+	if oldPrice != product.Price {
+		err := recordPriceChange(tx, &model.PriceChange{
+			ProductID:   product.ID,
+			OldPrice:    &oldPrice,
+			NewPrice:    product.Price,
+			Source:      model.PriceChangeImport,
+			EffectiveAt: product.UpdatedAt,
+		})
+		if err != nil {
+			return model.ImportRowResult{}, err
+		}
+	}
+
+	result.Action = model.ImportActionUpdate
+	return result, nil
+}
+
+// resolveImportCategory kategori slug'ını ya da id'sini doğrular; satır
+// reddedilecekse ikinci dönüş değeri nedenini taşır
+func resolveImportCategory(tx *gorm.DB, record model.ImportRecord) (*uint, string, error) {
+	var category model.Category
+	var err error
+	switch {
+	case record.CategorySlug != "":
+		err = tx.Where("slug = ?", record.CategorySlug).First(&category).Error
+	case record.CategoryID != nil:
+		err = tx.First(&category, *record.CategoryID).Error
+	default:
+		return nil, "", nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "category not found", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &category.ID, "", nil
+}
+
+func rejectedRow(record model.ImportRecord, message string) model.ImportRowResult {
+	return model.ImportRowResult{
+		Line:    record.Line,
+		Key:     record.Key(),
+		Action:  model.ImportActionRejected,
+		Message: message,
+		Raw:     record.Raw,
+	}
+}
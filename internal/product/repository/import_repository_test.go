@@ -0,0 +1,153 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type importFixture struct {
+	db       *gorm.DB
+	repo     repository.ImportRepository
+	products repository.ProductRepository
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	t.Helper()
+	raw := databasetest.Open(t)
+	// 666 fiyatlı satırın fiyat geçmişi yazılamaz; ürün satırı yazıldıktan
+	// sonra hata alan bir satırı taklit eder
+	err := raw.Exec(`CREATE TRIGGER reject_price BEFORE INSERT ON price_changes
+		WHEN NEW.new_price = 666 BEGIN SELECT RAISE(ABORT, 'price rejected by trigger'); END`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := database.ForTenant("acme")
+	if err := db.Create(&model.Category{Name: "Kitchen", Slug: "kitchen"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &importFixture{db: db, repo: repository.NewImportRepository(db), products: repository.NewProductRepository(db)}
+}
+
+func importRecord(line int, externalID, name string, price float64) model.ImportRecord {
+	record := model.ImportRecord{Line: line, Raw: "raw", ExternalID: externalID, Price: &price}
+	if name != "" {
+		record.Name = &name
+	}
+	return record
+}
+
+func (f *importFixture) count(t *testing.T, value interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := f.db.Model(value).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func assertActions(t *testing.T, results []model.ImportRowResult, want ...model.ImportAction) {
+	t.Helper()
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v", results)
+	}
+	for i := range want {
+		if results[i].Action != want[i] || results[i].Line != i+1 {
+			t.Fatalf("result %d = %+v, want action %s", i, results[i], want[i])
+		}
+	}
+}
+
+func TestApplyBatchRejectsFailingRowOnly(t *testing.T) {
+	f := newImportFixture(t)
+	existing := &model.Product{ExternalID: strPtr("ERP-1"), Name: "Mug", Price: 10, Currency: "TRY"}
+	if err := f.products.Create(existing, testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	withCategory := importRecord(4, "ERP-4", "Bowl", 7)
+	withCategory.CategorySlug = "kitchen"
+	unknownCategory := importRecord(5, "ERP-5", "Cup", 3)
+	unknownCategory.CategorySlug = "garden"
+	records := []model.ImportRecord{
+		importRecord(1, "ERP-1", "Big mug", 12),
+		importRecord(2, "ERP-2", "Plate", 666),
+		importRecord(3, "ERP-3", "", 5),
+		withCategory,
+		unknownCategory,
+		importRecord(6, "ERP-1", "Big mug", 12),
+	}
+	results, err := f.repo.ApplyBatch(records, false, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertActions(t, results, model.ImportActionUpdate, model.ImportActionRejected, model.ImportActionRejected,
+		model.ImportActionCreate, model.ImportActionRejected, model.ImportActionUnchanged)
+
+	if !strings.Contains(results[1].Message, "price rejected by trigger") || results[1].Raw != "raw" || results[1].ProductID != nil {
+		t.Fatalf("database error row = %+v", results[1])
+	}
+	if results[2].Message != "name and price are required for new products" {
+		t.Fatalf("missing name row = %+v", results[2])
+	}
+	if results[4].Message != "category not found" {
+		t.Fatalf("unknown category row = %+v", results[4])
+	}
+
+	// Hata alan satırın ürünü ve denetim kaydı savepoint'e geri alınır;
+	// önceki ve sonraki satırlar yazılır
+	var names []string
+	if err := f.db.Model(&model.Product{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "Big mug,Bowl" {
+		t.Fatalf("products = %v", names)
+	}
+	if entries := f.count(t, &model.AuditEntry{}); entries != 3 {
+		t.Fatalf("audit entries = %d, want create, update and create", entries)
+	}
+	bowl, err := f.products.GetByID(*results[3].ProductID)
+	if err != nil || bowl.CategoryID == nil || *bowl.ExternalID != "ERP-4" {
+		t.Fatalf("created product = %+v (%v)", bowl, err)
+	}
+}
+
+func TestApplyBatchDryRunWritesNothing(t *testing.T) {
+	f := newImportFixture(t)
+	existing := &model.Product{ExternalID: strPtr("ERP-1"), Name: "Mug", Price: 10, Currency: "TRY"}
+	if err := f.products.Create(existing, testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []model.ImportRecord{
+		importRecord(1, "ERP-1", "Big mug", 12),
+		importRecord(2, "ERP-2", "Plate", 666),
+		importRecord(3, "ERP-3", "Bowl", 7),
+	}
+	results, err := f.repo.ApplyBatch(records, true, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertActions(t, results, model.ImportActionUpdate, model.ImportActionRejected, model.ImportActionCreate)
+	if results[0].ProductID == nil || *results[0].ProductID != existing.ID || results[2].ProductID != nil {
+		t.Fatalf("dry-run product ids = %v, %v", results[0].ProductID, results[2].ProductID)
+	}
+
+	stored, err := f.products.GetByID(existing.ID)
+	if err != nil || stored.Name != "Mug" || stored.Version != existing.Version {
+		t.Fatalf("product after dry run = %+v (%v)", stored, err)
+	}
+	if products, entries, prices := f.count(t, &model.Product{}), f.count(t, &model.AuditEntry{}), f.count(t, &model.PriceChange{}); products != 1 || entries != 1 || prices != 1 {
+		t.Fatalf("dry run wrote rows: %d products, %d audit entries, %d price changes", products, entries, prices)
+	}
+}
+
+func strPtr(value string) *string {
+	return &value
+}
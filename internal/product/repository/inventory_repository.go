@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientStock bir hareket depodaki stoğu negatife düşüreceğinde döner
+var ErrInsufficientStock = errors.New("insufficient stock in warehouse")
+
+type MovementFilter struct {
+	ProductID   uint
+	WarehouseID uint
+	Type        model.MovementType
+	Limit       int
+}
+
+type InventoryRepository interface {
+	CreateWarehouse(warehouse *model.Warehouse) error
+	GetWarehouseByID(id uint) (*model.Warehouse, error)
+	GetWarehouseByCode(code string) (*model.Warehouse, error)
+	GetWarehouses() ([]model.Warehouse, error)
+	UpdateWarehouse(warehouse *model.Warehouse) ([]uint, error)
+	GetStockLevels(productID uint) ([]model.StockLevel, error)
+	ApplyMovement(movement *model.InventoryMovement) error
+	GetMovements(filter MovementFilter) ([]model.InventoryMovement, error)
+}
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) CreateWarehouse(warehouse *model.Warehouse) error {
+	return r.db.Create(warehouse).Error
+}
+
+func (r *inventoryRepository) GetWarehouseByID(id uint) (*model.Warehouse, error) {
+	var warehouse model.Warehouse
+	err := r.db.First(&warehouse, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *inventoryRepository) GetWarehouseByCode(code string) (*model.Warehouse, error) {
+	var warehouse model.Warehouse
+	err := r.db.Where("code = ?", code).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *inventoryRepository) GetWarehouses() ([]model.Warehouse, error) {
+	var warehouses []model.Warehouse
+	err := r.db.Order("code ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+// UpdateWarehouse depoyu günceller; aktiflik değiştiyse bu depoda stoğu olan
+// ürünlerin satılabilir stoğunu yeniden hesaplar ve etkilenen ürünleri döndürür
+func (r *inventoryRepository) UpdateWarehouse(warehouse *model.Warehouse) ([]uint, error) {
+	var productIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(warehouse).
+			Select("name", "address", "active", "updated_at").
+			Updates(warehouse)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(&model.StockLevel{}).
+			Where("warehouse_id = ?", warehouse.ID).
+			Distinct().
+			Pluck("product_id", &productIDs).Error; err != nil {
+			return err
+		}
+		return recalculateProductStock(tx, productIDs...)
+	})
+	return productIDs, err
+}
+
+func (r *inventoryRepository) GetStockLevels(productID uint) ([]model.StockLevel, error) {
+	var levels []model.StockLevel
+	err := r.db.Preload("Warehouse").
+		Where("product_id = ?", productID).
+		Order("warehouse_id ASC").
+		Find(&levels).Error
+	return levels, err
+}
+
+// ApplyMovement hareketi deftere yazar, ilgili stok seviyelerini satır kilidi
+// altında günceller ve ürünün satılabilir stoğunu aynı transaction içinde
+// yeniden hesaplar
+func (r *inventoryRepository) ApplyMovement(movement *model.InventoryMovement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		switch movement.Type {
+		case model.MovementReceive:
+			if err := changeStockLevel(tx, movement.ProductID, *movement.ToWarehouseID, movement.Quantity); err != nil {
+				return err
+			}
+		case model.MovementShip:
+			if err := changeStockLevel(tx, movement.ProductID, *movement.FromWarehouseID, -movement.Quantity); err != nil {
+				return err
+			}
+		case model.MovementAdjust:
+			if err := changeStockLevel(tx, movement.ProductID, *movement.ToWarehouseID, movement.Quantity); err != nil {
+				return err
+			}
+		case model.MovementTransfer:
+			if err := changeStockLevel(tx, movement.ProductID, *movement.FromWarehouseID, -movement.Quantity); err != nil {
+				return err
+			}
+			if err := changeStockLevel(tx, movement.ProductID, *movement.ToWarehouseID, movement.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		return recalculateProductStock(tx, movement.ProductID)
+	})
+}
+
+func (r *inventoryRepository) GetMovements(filter MovementFilter) ([]model.InventoryMovement, error) {
+	query := r.db.Model(&model.InventoryMovement{})
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.WarehouseID != 0 {
+		query = query.Where("from_warehouse_id = ? OR to_warehouse_id = ?", filter.WarehouseID, filter.WarehouseID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var movements []model.InventoryMovement
+	err := query.Order("id DESC").Find(&movements).Error
+	return movements, err
+}
+
+func changeStockLevel(tx *gorm.DB, productID, warehouseID uint, delta int) error {
+	level := model.StockLevel{ProductID: productID, WarehouseID: warehouseID}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).
+		First(&level).Error
+	if err != nil {
+		return err
+	}
+
+	if level.Quantity+delta < 0 {
+		return ErrInsufficientStock
+	}
+	return tx.Model(&level).Update("quantity", level.Quantity+delta).Error
+}
+
+// recalculateProductStock products.stock'u aktif depolardaki seviyelerin toplamına eşitler
+func recalculateProductStock(tx *gorm.DB, productIDs ...uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	return tx.Exec(`
+		UPDATE products SET stock = (
+			SELECT COALESCE(SUM(sl.quantity), 0)
+			FROM stock_levels sl
+			JOIN warehouses w ON w.id = sl.warehouse_id AND w.active
+			WHERE sl.product_id = products.id
+		)
+		WHERE id IN ? AND tenant_id = ?`, productIDs, tenant.ID(tx.Statement.Context)).Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PriceListRepository interface {
+	GetByCurrency(currency string) ([]model.PriceListEntry, error)
+	GetByProduct(productID uint) ([]model.PriceListEntry, error)
+	// GetForProducts verilen ürünlerin currency cinsinden liste fiyatlarını döndürür
+	GetForProducts(currency string, productIDs []uint) ([]model.PriceListEntry, error)
+	Upsert(entry *model.PriceListEntry, actor model.Actor) error
+	Delete(currency string, productID, variantID uint, actor model.Actor) (bool, error)
+}
+
+type priceListRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) PriceListRepository {
+	return &priceListRepository{db: db}
+}
+
+func (r *priceListRepository) GetByCurrency(currency string) ([]model.PriceListEntry, error) {
+	var entries []model.PriceListEntry
+	err := r.db.Where("currency = ?", currency).Order("product_id ASC, variant_id ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *priceListRepository) GetByProduct(productID uint) ([]model.PriceListEntry, error) {
+	var entries []model.PriceListEntry
+	err := r.db.Where("product_id = ?", productID).Order("currency ASC, variant_id ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *priceListRepository) GetForProducts(currency string, productIDs []uint) ([]model.PriceListEntry, error) {
+	var entries []model.PriceListEntry
+	if len(productIDs) == 0 {
+		return entries, nil
+	}
+	err := r.db.Where("currency = ? AND product_id IN ?", currency, productIDs).Find(&entries).Error
+	return entries, err
+}
+
+// Upsert fiyatı ve denetim kaydını aynı transaction içinde yazar
+func (r *priceListRepository) Upsert(entry *model.PriceListEntry, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		old, err := findListPrice(tx, entry.Currency, entry.ProductID, entry.VariantID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "currency"}, {Name: "product_id"}, {Name: "variant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+		}).Create(entry).Error; err != nil {
+			return err
+		}
+		change := model.AuditChange{New: entry.Price}
+		if old != nil {
+			change.Old = *old
+		}
+		return recordAudit(tx, actor, model.AuditUpdate, entry.ProductID, map[string]model.AuditChange{
+			priceListAuditField(entry.Currency, entry.VariantID): change,
+		})
+	})
+}
+
+func (r *priceListRepository) Delete(currency string, productID, variantID uint, actor model.Actor) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		old, err := findListPrice(tx, currency, productID, variantID)
+		if err != nil || old == nil {
+			return err
+		}
+
+		if err := tx.Where("currency = ? AND product_id = ? AND variant_id = ?", currency, productID, variantID).
+			Delete(&model.PriceListEntry{}).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return recordAudit(tx, actor, model.AuditUpdate, productID, map[string]model.AuditChange{
+			priceListAuditField(currency, variantID): {Old: *old, New: nil},
+		})
+	})
+	return deleted, err
+}
+
+// findListPrice kayıt yoksa nil döner; satır değişiklik bitene kadar kilitlenir
+func findListPrice(tx *gorm.DB, currency string, productID, variantID uint) (*float64, error) {
+	var entries []model.PriceListEntry
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("currency = ? AND product_id = ? AND variant_id = ?", currency, productID, variantID).
+		Limit(1).
+		Find(&entries).Error
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0].Price, nil
+}
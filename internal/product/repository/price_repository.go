@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrScheduleNotPending zaten uygulanmış ya da kapanmış bir planlı fiyat
+// değiştirilmek istendiğinde döner
+var ErrScheduleNotPending = errors.New("scheduled price is not pending")
+
+type PriceRepository interface {
+	GetHistory(productID uint, since *time.Time) ([]model.PriceChange, error)
+	GetSchedules(productID uint, statuses ...model.ScheduledPriceStatus) ([]model.ScheduledPrice, error)
+	GetScheduleByID(productID, scheduleID uint) (*model.ScheduledPrice, error)
+	HasOverlappingSchedule(productID uint, startsAt time.Time, endsAt *time.Time) (bool, error)
+	CreateSchedule(schedule *model.ScheduledPrice) error
+	GetDueSchedules(now time.Time) ([]model.ScheduledPrice, error)
+	GetExpiredSchedules(now time.Time) ([]model.ScheduledPrice, error)
+	ActivateSchedule(scheduleID uint, now time.Time, actor model.Actor) (bool, error)
+	EndSchedule(scheduleID uint, status model.ScheduledPriceStatus, now time.Time, actor model.Actor) (bool, error)
+}
+
+type priceRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &priceRepository{db: db}
+}
+
+func (r *priceRepository) GetHistory(productID uint, since *time.Time) ([]model.PriceChange, error) {
+	query := r.db.Where("product_id = ?", productID)
+	if since != nil {
+		query = query.Where("effective_at >= ?", *since)
+	}
+
+	var changes []model.PriceChange
+	err := query.Order("effective_at ASC, id ASC").Find(&changes).Error
+	return changes, err
+}
+
+func (r *priceRepository) GetSchedules(productID uint, statuses ...model.ScheduledPriceStatus) ([]model.ScheduledPrice, error) {
+	query := r.db.Where("product_id = ?", productID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+
+	var schedules []model.ScheduledPrice
+	err := query.Order("starts_at ASC, id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *priceRepository) GetScheduleByID(productID, scheduleID uint) (*model.ScheduledPrice, error) {
+	var schedule model.ScheduledPrice
+	err := r.db.Where("product_id = ?", productID).First(&schedule, scheduleID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// HasOverlappingSchedule bekleyen ya da aktif bir planın zaman aralığı verilen
+// aralıkla kesişiyorsa true döner; bitişi olmayan aralıklar sonsuz kabul edilir
+func (r *priceRepository) HasOverlappingSchedule(productID uint, startsAt time.Time, endsAt *time.Time) (bool, error) {
+	query := r.db.Model(&model.ScheduledPrice{}).
+		Where("product_id = ? AND status IN ?", productID,
+			[]model.ScheduledPriceStatus{model.ScheduledPricePending, model.ScheduledPriceActive}).
+		Where("ends_at IS NULL OR ends_at > ?", startsAt)
+	if endsAt != nil {
+		query = query.Where("starts_at < ?", *endsAt)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *priceRepository) CreateSchedule(schedule *model.ScheduledPrice) error {
+	return r.db.Create(schedule).Error
+}
+
+// GetDueSchedules başlama zamanı gelmiş bekleyen planları döndürür
+func (r *priceRepository) GetDueSchedules(now time.Time) ([]model.ScheduledPrice, error) {
+	var schedules []model.ScheduledPrice
+	err := r.db.Where("status = ? AND starts_at <= ?", model.ScheduledPricePending, now).
+		Order("starts_at ASC, id ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// GetExpiredSchedules bitiş zamanı geçmiş aktif planları döndürür
+func (r *priceRepository) GetExpiredSchedules(now time.Time) ([]model.ScheduledPrice, error) {
+	var schedules []model.ScheduledPrice
+	err := r.db.Where("status = ? AND ends_at IS NOT NULL AND ends_at <= ?", model.ScheduledPriceActive, now).
+		Order("ends_at ASC, id ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// ActivateSchedule planlı fiyatı ürüne uygular, önceki fiyatı plana yazar ve
+// fiyat geçmişine kayıt ekler. Ürün silinmişse plan iptal edilir ve false döner.
+func (r *priceRepository) ActivateSchedule(scheduleID uint, now time.Time, actor model.Actor) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		schedule, err := lockPendingSchedule(tx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		var product model.Product
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price").
+			First(&product, schedule.ProductID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Model(schedule).Update("status", model.ScheduledPriceCancelled).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := setProductPrice(tx, product.ID, product.Price, schedule.Price, now, actor); err != nil {
+			return err
+		}
+		if err := tx.Model(schedule).Updates(map[string]interface{}{
+			"status":         model.ScheduledPriceActive,
+			"original_price": product.Price,
+		}).Error; err != nil {
+			return err
+		}
+
+		applied = true
+		return recordPriceChange(tx, &model.PriceChange{
+			ProductID:        product.ID,
+			OldPrice:         &product.Price,
+			NewPrice:         schedule.Price,
+			Source:           model.PriceChangeScheduled,
+			ScheduledPriceID: &schedule.ID,
+			EffectiveAt:      now,
+		})
+	})
+	return applied, err
+}
+
+// EndSchedule planı verilen duruma geçirir. Plan aktifse ve ürünün fiyatı hâlâ
+// planlı fiyattaysa önceki fiyata geri dönülür; arada elle değiştirilen fiyata
+// dokunulmaz. Ürün fiyatı değiştiyse true döner.
+func (r *priceRepository) EndSchedule(scheduleID uint, status model.ScheduledPriceStatus, now time.Time, actor model.Actor) (bool, error) {
+	reverted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var schedule model.ScheduledPrice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&schedule, scheduleID).Error
+		if err != nil {
+			return err
+		}
+
+		switch schedule.Status {
+		case model.ScheduledPricePending:
+			return tx.Model(&schedule).Update("status", status).Error
+		case model.ScheduledPriceActive:
+		default:
+			return ErrScheduleNotPending
+		}
+
+		if err := tx.Model(&schedule).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		var product model.Product
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price").
+			First(&product, schedule.ProductID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if schedule.OriginalPrice == nil || product.Price != schedule.Price {
+			return nil
+		}
+
+		if err := setProductPrice(tx, product.ID, product.Price, *schedule.OriginalPrice, now, actor); err != nil {
+			return err
+		}
+
+		reverted = true
+		return recordPriceChange(tx, &model.PriceChange{
+			ProductID:        product.ID,
+			OldPrice:         &product.Price,
+			NewPrice:         *schedule.OriginalPrice,
+			Source:           model.PriceChangeScheduleEnded,
+			ScheduledPriceID: &schedule.ID,
+			EffectiveAt:      now,
+		})
+	})
+	return reverted, err
+}
+
+func lockPendingSchedule(tx *gorm.DB, scheduleID uint) (*model.ScheduledPrice, error) {
+	var schedule model.ScheduledPrice
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&schedule, scheduleID).Error
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != model.ScheduledPricePending {
+		return nil, ErrScheduleNotPending
+	}
+	return &schedule, nil
+}
+
+// setProductPrice fiyatı değiştirir, ETag'lerin geçersizleşmesi için
+// versiyonu artırır ve değişikliği denetim kaydına yazar
+func setProductPrice(tx *gorm.DB, productID uint, oldPrice, price float64, now time.Time, actor model.Actor) error {
+	err := tx.Model(&model.Product{}).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"price":      price,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, model.AuditUpdate, productID, map[string]model.AuditChange{
+		"price": {Old: oldPrice, New: price},
+	})
+}
+
+func recordPriceChange(tx *gorm.DB, change *model.PriceChange) error {
+	if change.EffectiveAt.IsZero() {
+		change.EffectiveAt = time.Now()
+	}
+	return tx.Create(change).Error
+}
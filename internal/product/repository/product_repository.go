@@ -1,17 +1,40 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
+	"cluster-iac/internal/problem"
 	"cluster-iac/internal/product/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict kayıt, beklenen versiyondan farklı bir versiyonda olduğunda döner
+var ErrVersionConflict = problem.New(problem.PreconditionFailed, "product has been modified")
+
+// ErrNotDeleted çöp kutusunda olmayan bir ürün geri yüklenmek istendiğinde döner
+var ErrNotDeleted = problem.New(problem.Conflict, "product is not deleted")
+
+// PUT/PATCH ile güncellenebilen kolonlar; id, versiyon, zaman damgaları ve
+// envanter hareketlerinden türetilen stock hariç
+var editableColumns = []string{"external_id", "name", "description", "price", "currency", "reorder_threshold", "tax_class",
+	"weight_kg", "length_cm", "width_cm", "height_cm", "category_id", "image_url"}
+
 type ProductRepository interface {
-	Create(product *model.Product) error
+	Create(product *model.Product, actor model.Actor) error
 	GetByID(id uint) (*model.Product, error)
 	GetAll() ([]model.Product, error)
-	Update(product *model.Product) error
-	Delete(id uint) error
-	GetByCategory(category string) ([]model.Product, error)
+	Update(product *model.Product, expectedVersion uint, actor model.Actor) error
+	Delete(id uint, expectedVersion uint, actor model.Actor) error
+	GetByCategoryIDs(categoryIDs []uint) ([]model.Product, error)
+	GetByIDWithDeleted(id uint) (*model.Product, error)
+	GetByExternalID(externalID string) (*model.Product, error)
+	GetDeleted() ([]model.Product, error)
+	Restore(id uint, actor model.Actor) (*model.Product, error)
+	Purge(id uint, actor model.Actor) error
+	PurgeDeletedBefore(cutoff time.Time, actor model.Actor) ([]uint, error)
+	StreamExport(categoryIDs []uint, updatedSince *time.Time, fn func(row *model.ProductExportRow) error) error
 }
 
 type productRepository struct {
@@ -22,13 +45,34 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
-func (r *productRepository) Create(product *model.Product) error {
-	return r.db.Create(product).Error
+// Create ürünü, fiyat geçmişinin ilk kaydını ve denetim kaydını aynı
+// transaction içinde yazar
+func (r *productRepository) Create(product *model.Product, actor model.Actor) error {
+	product.Version = 1
+	product.Stock = 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(product).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, actor, model.AuditCreate, product.ID, diffProduct(nil, product)); err != nil {
+			return err
+		}
+		return recordPriceChange(tx, &model.PriceChange{
+			ProductID:   product.ID,
+			NewPrice:    product.Price,
+			Source:      model.PriceChangeInitial,
+			EffectiveAt: product.CreatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return r.withDetails().First(product, product.ID).Error
 }
 
 func (r *productRepository) GetByID(id uint) (*model.Product, error) {
 	var product model.Product
-	err := r.db.First(&product, id).Error
+	err := r.withDetails().First(&product, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,21 +81,270 @@ func (r *productRepository) GetByID(id uint) (*model.Product, error) {
 
 func (r *productRepository) GetAll() ([]model.Product, error) {
 	var products []model.Product
-	err := r.db.Find(&products).Error
+	err := r.withDetails().Find(&products).Error
 	return products, err
 }
 
-func (r *productRepository) Update(product *model.Product) error {
-	return r.db.Save(product).Error
+// Update yalnızca kayıt hâlâ expectedVersion'daysa düzenlenebilir kolonları
+// yazar ve versiyonu bir artırır; başarılı olursa product güncel haliyle doldurulur.
+// Değişen alanlar denetim kaydına, fiyat değiştiyse fiyat geçmişine aynı
+// transaction içinde yazılır.
+func (r *productRepository) Update(product *model.Product, expectedVersion uint, actor model.Actor) error {
+	product.Version = expectedVersion + 1
+
+	columns := append(append([]string{}, editableColumns...), "version", "updated_at")
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current model.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("version = ?", expectedVersion).
+			First(&current, product.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return r.conflictOrNotFound(product.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(product).
+			Select(columns).
+			Omit(clause.Associations).
+			Updates(product).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, actor, model.AuditUpdate, product.ID, diffProduct(&current, product)); err != nil {
+			return err
+		}
+
+		if current.Price == product.Price {
+			return nil
+		}
+		return recordPriceChange(tx, &model.PriceChange{
+			ProductID:   product.ID,
+			OldPrice:    &current.Price,
+			NewPrice:    product.Price,
+			Source:      model.PriceChangeManual,
+			EffectiveAt: product.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	product.Category = nil
+	return r.withDetails().First(product, product.ID).Error
+}
+
+func (r *productRepository) Delete(id uint, expectedVersion uint, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", expectedVersion).Delete(&model.Product{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return r.conflictOrNotFound(id)
+		}
+
+		var product model.Product
+		if err := tx.Unscoped().Select("id", "deleted_at").First(&product, id).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, model.AuditDelete, id, deletedAtChange(nil, &product.DeletedAt.Time))
+	})
 }
 
-func (r *productRepository) Delete(id uint) error {
+func (r *productRepository) GetByCategoryIDs(categoryIDs []uint) ([]model.Product, error) {
+	var products []model.Product
+	err := r.withDetails().Where("category_id IN ?", categoryIDs).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) GetByIDWithDeleted(id uint) (*model.Product, error) {
 	var product model.Product
-	return r.db.Delete(&product, id).Error
+	err := r.withDetails().Unscoped().First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
 }
 
-func (r *productRepository) GetByCategory(category string) ([]model.Product, error) {
+// GetByExternalID çöp kutusundakiler dahil arar; unique index silinmiş
+// ürünleri de kapsar
+func (r *productRepository) GetByExternalID(externalID string) (*model.Product, error) {
+	var product model.Product
+	err := r.db.Unscoped().Where("external_id = ?", externalID).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) GetDeleted() ([]model.Product, error) {
 	var products []model.Product
-	err := r.db.Where("category = ?", category).Find(&products).Error
+	err := r.withDetails().Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&products).Error
 	return products, err
 }
+
+func (r *productRepository) Restore(id uint, actor model.Actor) (*model.Product, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "deleted_at").
+			First(&product, id).Error
+		if err != nil {
+			return err
+		}
+		if !product.DeletedAt.Valid {
+			return ErrNotDeleted
+		}
+
+		if err := tx.Unscoped().Model(&model.Product{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, model.AuditRestore, id, deletedAtChange(&product.DeletedAt.Time, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// Purge yalnızca çöp kutusundaki bir ürünü varyantlarıyla birlikte kalıcı olarak siler
+func (r *productRepository) Purge(id uint, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Unscoped().Model(&model.Product{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return purgeProducts(tx, []uint{id}, actor)
+	})
+}
+
+func (r *productRepository) PurgeDeletedBefore(cutoff time.Time, actor model.Actor) ([]uint, error) {
+	var ids []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&model.Product{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return purgeProducts(tx, ids, actor)
+	})
+	return ids, err
+}
+
+// StreamExport ürünleri bir veritabanı cursor'ı üzerinden tek tek fn'e verir;
+// sonuç kümesi belleğe alınmaz. categoryIDs nil ise kategori filtresi uygulanmaz.
+func (r *productRepository) StreamExport(categoryIDs []uint, updatedSince *time.Time, fn func(row *model.ProductExportRow) error) error {
+	query := r.db.Model(&model.Product{}).
+		Select(`products.id, products.external_id, products.name, products.description,
+			products.price, products.currency, products.stock, products.reorder_threshold, products.tax_class,
+			products.weight_kg, products.length_cm, products.width_cm, products.height_cm, products.category_id,
+			categories.slug AS category, products.image_url, products.version,
+			products.created_at, products.updated_at`).
+		Joins("LEFT JOIN categories ON categories.id = products.category_id")
+	if categoryIDs != nil {
+		query = query.Where("products.category_id IN ?", categoryIDs)
+	}
+	if updatedSince != nil {
+		query = query.Where("products.updated_at >= ?", *updatedSince)
+	}
+
+	rows, err := query.Order("products.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.ProductExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// withDetails ürün okumalarında kategori, option, varyant ve stok seviyelerini yükler
+func (r *productRepository) withDetails() *gorm.DB {
+	return r.db.
+		Preload("Category").
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("StockLevels", func(db *gorm.DB) *gorm.DB { return db.Order("warehouse_id ASC") }).
+		Preload("StockLevels.Warehouse").
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") })
+}
+
+// purgeProducts ürünleri ve onlara bağlı satırları foreign key sırasına göre
+// siler. Denetim kayıtları silinmez; her ürün için son hali kaydedilir.
+func purgeProducts(tx *gorm.DB, ids []uint, actor model.Actor) error {
+	var products []model.Product
+	if err := tx.Unscoped().Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return err
+	}
+	for i := range products {
+		if err := recordAudit(tx, actor, model.AuditPurge, products[i].ID, diffProduct(&products[i], nil)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.ProductOption{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("product_id IN ?", ids).Delete(&model.ProductVariant{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.StockLevel{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.PriceChange{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.ScheduledPrice{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.PriceListEntry{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id IN ?", ids).Delete(&model.ProductTranslation{}).Error; err != nil {
+		return err
+	}
+	if err := deleteImages(tx, "product_id IN ?", ids); err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&model.Product{}, ids).Error
+}
+
+// conflictOrNotFound koşullu bir yazma hiçbir satırı etkilemediğinde nedenini ayırt eder
+func (r *productRepository) conflictOrNotFound(id uint) error {
+	var count int64
+	if err := r.db.Model(&model.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionConflict
+}
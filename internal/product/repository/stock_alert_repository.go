@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockAlertRepository interface {
+	GetProductStock(productID uint) (*model.Product, error)
+	GetAllProductStock() ([]model.Product, error)
+	GetState(productID uint) (*model.StockAlertState, error)
+	SaveState(state *model.StockAlertState) error
+	DeleteState(productID uint) error
+}
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepository(db *gorm.DB) StockAlertRepository {
+	return &stockAlertRepository{db: db}
+}
+
+// GetProductStock uyarı değerlendirmesi için yalnızca gereken kolonları okur
+func (r *stockAlertRepository) GetProductStock(productID uint) (*model.Product, error) {
+	var product model.Product
+	err := r.db.Select("id", "tenant_id", "name", "stock", "reorder_threshold").First(&product, productID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *stockAlertRepository) GetAllProductStock() ([]model.Product, error) {
+	var products []model.Product
+	err := r.db.Select("id", "tenant_id", "name", "stock", "reorder_threshold").Order("id ASC").Find(&products).Error
+	return products, err
+}
+
+// GetState kayıt yoksa nil döner
+func (r *stockAlertRepository) GetState(productID uint) (*model.StockAlertState, error) {
+	var state model.StockAlertState
+	err := r.db.First(&state, productID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *stockAlertRepository) SaveState(state *model.StockAlertState) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(state).Error
+}
+
+func (r *stockAlertRepository) DeleteState(productID uint) error {
+	return r.db.Delete(&model.StockAlertState{}, productID).Error
+}
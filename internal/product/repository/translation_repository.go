@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranslationRepository interface {
+	GetProductTranslations(productID uint) ([]model.ProductTranslation, error)
+	// GetProductTranslationsFor verilen ürünlerin locales dillerindeki çevirilerini döndürür
+	GetProductTranslationsFor(productIDs []uint, locales []string) ([]model.ProductTranslation, error)
+	UpsertProductTranslation(translation *model.ProductTranslation, actor model.Actor) error
+	DeleteProductTranslation(productID uint, locale string, actor model.Actor) (bool, error)
+
+	GetCategoryTranslations(categoryID uint) ([]model.CategoryTranslation, error)
+	GetCategoryTranslationsFor(categoryIDs []uint, locales []string) ([]model.CategoryTranslation, error)
+	UpsertCategoryTranslation(translation *model.CategoryTranslation) error
+	DeleteCategoryTranslation(categoryID uint, locale string) (bool, error)
+
+	ProductCoverage(locale string) (model.TranslationCoverage, error)
+	CategoryCoverage(locale string) (model.TranslationCoverage, error)
+	// MissingProducts locale dilinde çevirisi olmayan ürünleri id sırasıyla döndürür
+	MissingProducts(locale string, limit int) ([]model.MissingTranslation, error)
+	MissingCategories(locale string, limit int) ([]model.MissingTranslation, error)
+}
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) GetProductTranslations(productID uint) ([]model.ProductTranslation, error) {
+	var translations []model.ProductTranslation
+	err := r.db.Where("product_id = ?", productID).Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) GetProductTranslationsFor(productIDs []uint, locales []string) ([]model.ProductTranslation, error) {
+	var translations []model.ProductTranslation
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("product_id IN ? AND locale IN ?", productIDs, locales).Find(&translations).Error
+	return translations, err
+}
+
+// UpsertProductTranslation çeviriyi ve ürünün denetim kaydını aynı
+// transaction içinde yazar
+func (r *translationRepository) UpsertProductTranslation(translation *model.ProductTranslation, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		before, err := findProductTranslation(tx, translation.ProductID, translation.Locale)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+		}).Create(translation).Error; err != nil {
+			return err
+		}
+
+		changes := make(map[string]model.AuditChange)
+		diffFields(changes, "translations."+translation.Locale+".", translationAuditFields(before), translationAuditFields(translation))
+		return recordAudit(tx, actor, model.AuditUpdate, translation.ProductID, changes)
+	})
+}
+
+func (r *translationRepository) DeleteProductTranslation(productID uint, locale string, actor model.Actor) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		before, err := findProductTranslation(tx, productID, locale)
+		if err != nil || before == nil {
+			return err
+		}
+
+		if err := tx.Delete(&model.ProductTranslation{}, before.ID).Error; err != nil {
+			return err
+		}
+		deleted = true
+
+		changes := make(map[string]model.AuditChange)
+		diffFields(changes, "translations."+locale+".", translationAuditFields(before), nil)
+		return recordAudit(tx, actor, model.AuditUpdate, productID, changes)
+	})
+	return deleted, err
+}
+
+// findProductTranslation çeviri yoksa nil döner; satır değişiklik bitene kadar kilitlenir
+func findProductTranslation(tx *gorm.DB, productID uint, locale string) (*model.ProductTranslation, error) {
+	var translations []model.ProductTranslation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND locale = ?", productID, locale).
+		Limit(1).
+		Find(&translations).Error
+	if err != nil || len(translations) == 0 {
+		return nil, err
+	}
+	return &translations[0], nil
+}
+
+func (r *translationRepository) GetCategoryTranslations(categoryID uint) ([]model.CategoryTranslation, error) {
+	var translations []model.CategoryTranslation
+	err := r.db.Where("category_id = ?", categoryID).Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) GetCategoryTranslationsFor(categoryIDs []uint, locales []string) ([]model.CategoryTranslation, error) {
+	var translations []model.CategoryTranslation
+	if len(categoryIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("category_id IN ? AND locale IN ?", categoryIDs, locales).Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) UpsertCategoryTranslation(translation *model.CategoryTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) DeleteCategoryTranslation(categoryID uint, locale string) (bool, error) {
+	result := r.db.Where("category_id = ? AND locale = ?", categoryID, locale).Delete(&model.CategoryTranslation{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ProductCoverage çöp kutusundaki ürünleri saymaz
+func (r *translationRepository) ProductCoverage(locale string) (model.TranslationCoverage, error) {
+	var coverage model.TranslationCoverage
+	if err := r.db.Model(&model.Product{}).Count(&coverage.Total).Error; err != nil {
+		return coverage, err
+	}
+
+	translated := r.db.Model(&model.ProductTranslation{}).
+		Joins("JOIN products ON products.id = product_translations.product_id AND products.deleted_at IS NULL").
+		Where("product_translations.locale = ?", locale)
+	if err := translated.Count(&coverage.Translated).Error; err != nil {
+		return coverage, err
+	}
+
+	err := r.db.Model(&model.ProductTranslation{}).
+		Joins("JOIN products ON products.id = product_translations.product_id AND products.deleted_at IS NULL").
+		Where("product_translations.locale = ? AND product_translations.description = '' AND products.description <> ''", locale).
+		Count(&coverage.MissingDescription).Error
+	return coverage, err
+}
+
+func (r *translationRepository) CategoryCoverage(locale string) (model.TranslationCoverage, error) {
+	var coverage model.TranslationCoverage
+	if err := r.db.Model(&model.Category{}).Count(&coverage.Total).Error; err != nil {
+		return coverage, err
+	}
+
+	err := r.db.Model(&model.CategoryTranslation{}).
+		Joins("JOIN categories ON categories.id = category_translations.category_id").
+		Where("category_translations.locale = ?", locale).
+		Count(&coverage.Translated).Error
+	return coverage, err
+}
+
+func (r *translationRepository) MissingProducts(locale string, limit int) ([]model.MissingTranslation, error) {
+	var missing []model.MissingTranslation
+	err := r.db.Model(&model.Product{}).
+		Select("products.id, products.name").
+		Joins("LEFT JOIN product_translations ON product_translations.product_id = products.id AND product_translations.locale = ?", locale).
+		Where("product_translations.id IS NULL").
+		Order("products.id ASC").
+		Limit(limit).
+		Scan(&missing).Error
+	return missing, err
+}
+
+func (r *translationRepository) MissingCategories(locale string, limit int) ([]model.MissingTranslation, error) {
+	var missing []model.MissingTranslation
+	err := r.db.Model(&model.Category{}).
+		Select("categories.id, categories.name").
+		Joins("LEFT JOIN category_translations ON category_translations.category_id = categories.id AND category_translations.locale = ?", locale).
+		Where("category_translations.id IS NULL").
+		Order("categories.id ASC").
+		Limit(limit).
+		Scan(&missing).Error
+	return missing, err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VariantRepository interface {
+	GetOptions(productID uint) ([]model.ProductOption, error)
+	ReplaceOptions(productID uint, options []model.ProductOption, actor model.Actor) error
+	Create(variant *model.ProductVariant, actor model.Actor) error
+	GetByID(id uint) (*model.ProductVariant, error)
+	GetBySKU(sku string) (*model.ProductVariant, error)
+	GetByProductID(productID uint) ([]model.ProductVariant, error)
+	Update(variant *model.ProductVariant, actor model.Actor) error
+	Delete(id uint, actor model.Actor) error
+}
+
+type variantRepository struct {
+	db *gorm.DB
+}
+
+func NewVariantRepository(db *gorm.DB) VariantRepository {
+	return &variantRepository{db: db}
+}
+
+func (r *variantRepository) GetOptions(productID uint) ([]model.ProductOption, error) {
+	var options []model.ProductOption
+	err := r.db.Where("product_id = ?", productID).Order("position ASC, id ASC").Find(&options).Error
+	return options, err
+}
+
+// ReplaceOptions eksenleri ve denetim kaydını aynı transaction içinde yazar
+func (r *variantRepository) ReplaceOptions(productID uint, options []model.ProductOption, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before []model.ProductOption
+		if err := tx.Where("product_id = ?", productID).Order("position ASC, id ASC").Find(&before).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", productID).Delete(&model.ProductOption{}).Error; err != nil {
+			return err
+		}
+
+		if len(options) > 0 {
+			for i := range options {
+				options[i].ID = 0
+				options[i].ProductID = productID
+			}
+			if err := tx.Create(&options).Error; err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, actor, model.AuditUpdate, productID, map[string]model.AuditChange{
+			"options": {Old: optionsAuditValue(before), New: optionsAuditValue(options)},
+		})
+	})
+}
+
+func (r *variantRepository) Create(variant *model.ProductVariant, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, model.AuditUpdate, variant.ProductID, diffVariant(nil, variant))
+	})
+}
+
+func (r *variantRepository) GetByID(id uint) (*model.ProductVariant, error) {
+	var variant model.ProductVariant
+	err := r.db.First(&variant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// GetBySKU silinmiş varyantlar dahil arar; unique index silinmiş
+// varyantları da kapsar
+func (r *variantRepository) GetBySKU(sku string) (*model.ProductVariant, error) {
+	var variant model.ProductVariant
+	err := r.db.Unscoped().Where("sku = ?", sku).First(&variant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *variantRepository) GetByProductID(productID uint) ([]model.ProductVariant, error) {
+	var variants []model.ProductVariant
+	err := r.db.Where("product_id = ?", productID).Order("id ASC").Find(&variants).Error
+	return variants, err
+}
+
+func (r *variantRepository) Update(variant *model.ProductVariant, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before model.ProductVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, variant.ID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(variant).
+			Select("sku", "attributes", "price", "stock", "image_url", "updated_at").
+			Updates(variant)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordAudit(tx, actor, model.AuditUpdate, before.ProductID, diffVariant(&before, variant))
+	})
+}
+
+func (r *variantRepository) Delete(id uint, actor model.Actor) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var before model.ProductVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&before, id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.ProductVariant{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordAudit(tx, actor, model.AuditUpdate, before.ProductID, diffVariant(&before, nil))
+	})
+}
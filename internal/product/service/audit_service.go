@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+type AuditService interface {
+	ListEntries(filter model.AuditFilter) (*model.AuditPage, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// ListEntries filtreye uyan kayıtları yeniden eskiye sayfalar. Sonraki sayfanın
+// olup olmadığını anlamak için bir kayıt fazla okunur.
+func (s *auditService) ListEntries(filter model.AuditFilter) (*model.AuditPage, error) {
+	if err := validateAuditFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	entries, err := s.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []model.AuditEntry{}
+	}
+
+	page := &model.AuditPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		next := page.Entries[limit-1].ID
+		page.NextBefore = &next
+	}
+	return page, nil
+}
+
+func validateAuditFilter(filter *model.AuditFilter) error {
+	switch filter.Entity {
+	case "", model.AuditEntityProduct:
+	default:
+		return fmt.Errorf("%w: unknown entity %q", ErrInvalidAuditFilter, filter.Entity)
+	}
+	switch filter.Action {
+	case "", model.AuditCreate, model.AuditUpdate, model.AuditDelete, model.AuditRestore, model.AuditPurge:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidAuditFilter, filter.Action)
+	}
+	switch filter.Source {
+	case "", model.AuditSourceHTTP, model.AuditSourceGRPC, model.AuditSourceSystem:
+	default:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidAuditFilter, filter.Source)
+	}
+	if filter.EntityID != 0 && filter.Entity == "" {
+		return fmt.Errorf("%w: id requires entity", ErrInvalidAuditFilter)
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Until.After(*filter.Since) {
+		return fmt.Errorf("%w: until must be after since", ErrInvalidAuditFilter)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditFilter, maxAuditLimit)
+	}
+	return nil
+}
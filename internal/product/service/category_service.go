@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type CategoryService interface {
+	CreateCategory(category *model.Category) error
+	GetCategoryByID(id uint) (*model.Category, error)
+	GetAllCategories() ([]model.Category, error)
+	UpdateCategory(category *model.Category) error
+	DeleteCategory(id uint) error
+	GetCategoryTree() ([]*model.CategoryTreeNode, error)
+}
+
+type categoryService struct {
+	repo repository.CategoryRepository
+}
+
+func NewCategoryService(repo repository.CategoryRepository) CategoryService {
+	return &categoryService{repo: repo}
+}
+
+func (s *categoryService) CreateCategory(category *model.Category) error {
+	if err := s.prepare(category); err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		if err := s.checkParent(*category.ParentID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Create(category)
+}
+
+func (s *categoryService) GetCategoryByID(id uint) (*model.Category, error) {
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrCategoryNotFound)
+	}
+	return category, nil
+}
+
+func (s *categoryService) GetAllCategories() ([]model.Category, error) {
+	return s.repo.GetAll()
+}
+
+func (s *categoryService) UpdateCategory(category *model.Category) error {
+	if _, err := s.repo.GetByID(category.ID); err != nil {
+		return notFound(err, ErrCategoryNotFound)
+	}
+	if err := s.prepare(category); err != nil {
+		return err
+	}
+
+	if category.ParentID != nil {
+		if err := s.checkParent(*category.ParentID); err != nil {
+			return err
+		}
+
+		// Kategori kendi alt ağacına taşınamaz
+		descendants, err := s.repo.GetDescendantIDs(category.ID)
+		if err != nil {
+			return err
+		}
+		for _, id := range descendants {
+			if id == *category.ParentID {
+				return ErrCategoryCycle
+			}
+		}
+	}
+
+	return s.repo.Update(category)
+}
+
+func (s *categoryService) DeleteCategory(id uint) error {
+	children, err := s.repo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	products, err := s.repo.CountProducts(id)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return ErrCategoryInUse
+	}
+
+	return notFound(s.repo.Delete(id), ErrCategoryNotFound)
+}
+
+func (s *categoryService) GetCategoryTree() ([]*model.CategoryTreeNode, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountProductsPerCategory()
+	if err != nil {
+		return nil, err
+	}
+
+	// GetAll position'a göre sıralı döndüğü için çocuklar da sıralı eklenir
+	nodes := make(map[uint]*model.CategoryTreeNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &model.CategoryTreeNode{
+			ID:           category.ID,
+			Name:         category.Name,
+			Slug:         category.Slug,
+			Position:     category.Position,
+			ProductCount: counts[category.ID],
+			Children:     []*model.CategoryTreeNode{},
+		}
+	}
+
+	roots := []*model.CategoryTreeNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil || nodes[*category.ParentID] == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent := nodes[*category.ParentID]
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, root := range roots {
+		sumProductCounts(root)
+	}
+	return roots, nil
+}
+
+func sumProductCounts(node *model.CategoryTreeNode) int64 {
+	node.TotalProductCount = node.ProductCount
+	for _, child := range node.Children {
+		node.TotalProductCount += sumProductCounts(child)
+	}
+	return node.TotalProductCount
+}
+
+// prepare slug'ı normalize eder ve başka bir kategoride kullanılmadığını kontrol eder
+func (s *categoryService) prepare(category *model.Category) error {
+	if category.Slug == "" {
+		category.Slug = model.Slugify(category.Name)
+	} else {
+		category.Slug = model.Slugify(category.Slug)
+	}
+	if category.Slug == "" {
+		return ErrInvalidSlug
+	}
+
+	existing, err := s.repo.GetBySlug(category.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != category.ID {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+func (s *categoryService) checkParent(parentID uint) error {
+	_, err := s.repo.GetByID(parentID)
+	return notFound(err, ErrParentNotFound)
+}
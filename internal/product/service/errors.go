@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+
+	"cluster-iac/internal/problem"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound    = problem.New(problem.NotFound, "category not found")
+	ErrUnknownCategory     = problem.New(problem.Invalid, "category not found")
+	ErrParentNotFound      = problem.New(problem.Invalid, "parent category not found")
+	ErrCategoryCycle       = problem.New(problem.Invalid, "category cannot be moved under itself or one of its descendants")
+	ErrCategoryHasChildren = problem.New(problem.Conflict, "category has child categories")
+	ErrCategoryInUse       = problem.New(problem.Conflict, "category is referenced by products")
+	ErrSlugTaken           = problem.New(problem.Duplicate, "category slug is already in use")
+	ErrInvalidSlug         = problem.New(problem.Invalid, "category slug is empty or invalid")
+)
+
+var (
+	ErrProductNotFound          = problem.New(problem.NotFound, "product not found")
+	ErrExternalIDTaken          = problem.New(problem.Duplicate, "external_id is already in use")
+	ErrVariantNotFound          = problem.New(problem.NotFound, "variant not found")
+	ErrSKUTaken                 = problem.New(problem.Duplicate, "sku is already in use")
+	ErrInvalidVariantAttributes = problem.New(problem.Invalid, "variant attributes do not match the product options")
+	ErrDuplicateVariant         = problem.New(problem.Duplicate, "a variant with the same attributes already exists")
+	ErrInvalidOptions           = problem.New(problem.Invalid, "product options must have unique names and at least one value")
+	ErrOptionsInUse             = problem.New(problem.Conflict, "existing variants do not fit the new options")
+)
+
+var (
+	ErrWarehouseNotFound  = problem.New(problem.NotFound, "warehouse not found")
+	ErrWarehouseCodeTaken = problem.New(problem.Duplicate, "warehouse code is already in use")
+	ErrWarehouseInactive  = problem.New(problem.Conflict, "warehouse is inactive")
+	ErrInvalidMovement    = problem.New(problem.Invalid, "invalid inventory movement")
+	ErrInsufficientStock  = problem.New(problem.Conflict, "insufficient stock in warehouse")
+)
+
+var (
+	ErrScheduleNotFound = problem.New(problem.NotFound, "scheduled price not found")
+	ErrInvalidSchedule  = problem.New(problem.Invalid, "invalid scheduled price")
+	ErrScheduleOverlap  = problem.New(problem.Conflict, "scheduled price overlaps an existing schedule")
+	ErrScheduleClosed   = problem.New(problem.Conflict, "scheduled price is already completed or cancelled")
+)
+
+var (
+	ErrInvalidListPrice  = problem.New(problem.Invalid, "invalid price list entry")
+	ErrListPriceNotFound = problem.New(problem.NotFound, "price list entry not found")
+)
+
+var (
+	ErrInvalidTranslation  = problem.New(problem.Invalid, "invalid translation")
+	ErrTranslationNotFound = problem.New(problem.NotFound, "translation not found")
+)
+
+var (
+	ErrImportNotFound = problem.New(problem.NotFound, "import job not found")
+	ErrInvalidImport  = problem.New(problem.Invalid, "invalid import file")
+)
+
+var (
+	ErrInvalidAuditFilter = problem.New(problem.Invalid, "invalid audit filter")
+)
+
+var (
+	ErrImageNotFound     = problem.New(problem.NotFound, "image not found")
+	ErrUnsupportedImage  = problem.New(problem.UnsupportedMedia, "unsupported image type")
+	ErrImageTooLarge     = problem.New(problem.TooLarge, "image exceeds the size limit")
+	ErrTooManyImages     = problem.New(problem.Conflict, "product has reached the image limit")
+	ErrInvalidImageOrder = problem.New(problem.Invalid, "image order must list every image of the product exactly once")
+)
+
+// notFound repository'nin gorm.ErrRecordNotFound hatasını domain hatasına
+// çevirir; diğer hatalar (ör. bağlantı kopması) olduğu gibi döner
+func notFound(err error, domainErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErr
+	}
+	return err
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/storage"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// allowedImageTypes içerikten tespit edilen (istemcinin bildirdiği değil)
+// kabul edilen görsel türleri ve uzantıları
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+type ImageLimits struct {
+	MaxBytes int64
+	// MaxPixels genişlik*yükseklik sınırıdır; küçük bir dosya çok büyük
+	// boyutlarla çözülüp belleği doldurmasın diye kontrol edilir
+	MaxPixels     int
+	MaxPerProduct int
+	ThumbnailSize int
+}
+
+type ImageService interface {
+	Upload(ctx context.Context, productID uint, files [][]byte, actor model.Actor) ([]model.ProductImage, error)
+	GetImages(productID uint) ([]model.ProductImage, error)
+	Reorder(productID uint, imageIDs []uint, actor model.Actor) ([]model.ProductImage, error)
+	Delete(productID, imageID uint, actor model.Actor) error
+	GenerateThumbnail(ctx context.Context, imageID uint) error
+	ProcessPendingThumbnails(ctx context.Context) error
+	CollectGarbage(ctx context.Context) error
+}
+
+type imageService struct {
+	repo        repository.ImageRepository
+	productRepo repository.ProductRepository
+	store       storage.BlobStore
+	publisher   events.Publisher
+	limits      ImageLimits
+
+	// Küçük görsel üretilecek görsel id'leri; kuyruk doluysa periyodik tarama yakalar
+	thumbnails chan<- uint
+}
+
+func NewImageService(repo repository.ImageRepository, productRepo repository.ProductRepository, store storage.BlobStore, publisher events.Publisher, limits ImageLimits, thumbnails chan<- uint) ImageService {
+	return &imageService{
+		repo:        repo,
+		productRepo: productRepo,
+		store:       store,
+		publisher:   publisher,
+		limits:      limits,
+		thumbnails:  thumbnails,
+	}
+}
+
+// Upload tüm dosyaları doğrular, blob store'a yazar ve ardından kayıtları tek
+// transaction'da ekler. Kayıt başarısız olursa yüklenen blob'lar geri silinir.
+func (s *imageService) Upload(ctx context.Context, productID uint, files [][]byte, actor model.Actor) ([]model.ProductImage, error) {
+	if err := s.checkProduct(productID); err != nil {
+		return nil, err
+	}
+
+	// Dosyalar depoya yazılmadan önce erken ret; sınır asıl olarak Create'te
+	// ürün kilitliyken kontrol edilir
+	count, err := s.repo.CountByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if int(count)+len(files) > s.limits.MaxPerProduct {
+		return nil, fmt.Errorf("%w of %d", ErrTooManyImages, s.limits.MaxPerProduct)
+	}
+
+	images := make([]model.ProductImage, 0, len(files))
+	for i, data := range files {
+		if int64(len(data)) > s.limits.MaxBytes {
+			return nil, fmt.Errorf("%w of %d bytes (file %d)", ErrImageTooLarge, s.limits.MaxBytes, i+1)
+		}
+
+		contentType := http.DetectContentType(data)
+		extension, ok := allowedImageTypes[contentType]
+		if !ok {
+			return nil, fmt.Errorf("%w %q (file %d)", ErrUnsupportedImage, contentType, i+1)
+		}
+
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: file %d cannot be decoded", ErrUnsupportedImage, i+1)
+		}
+		if err := s.checkDimensions(config); err != nil {
+			return nil, fmt.Errorf("%w (file %d)", err, i+1)
+		}
+
+		key := fmt.Sprintf("products/%d/%s.%s", productID, randomName(), extension)
+		images = append(images, model.ProductImage{
+			ProductID:       productID,
+			Key:             key,
+			URL:             s.store.URL(key),
+			ContentType:     contentType,
+			Size:            int64(len(data)),
+			Width:           config.Width,
+			Height:          config.Height,
+			ThumbnailStatus: model.ThumbnailPending,
+		})
+	}
+
+	for i := range images {
+		if err := s.store.Put(ctx, images[i].Key, files[i], images[i].ContentType); err != nil {
+			s.removeBlobs(ctx, images[:i])
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(images, s.limits.MaxPerProduct, actor); err != nil {
+		s.removeBlobs(ctx, images)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrProductNotFound
+		case errors.Is(err, repository.ErrImageLimitReached):
+			return nil, fmt.Errorf("%w of %d", ErrTooManyImages, s.limits.MaxPerProduct)
+		}
+		return nil, err
+	}
+
+	for _, img := range images {
+		s.enqueueThumbnail(img.ID)
+	}
+	s.publish(productID)
+	return images, nil
+}
+
+func (s *imageService) GetImages(productID uint) ([]model.ProductImage, error) {
+	if err := s.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProductID(productID)
+}
+
+func (s *imageService) Reorder(productID uint, imageIDs []uint, actor model.Actor) ([]model.ProductImage, error) {
+	err := s.repo.Reorder(productID, imageIDs, actor)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, ErrProductNotFound
+	case errors.Is(err, repository.ErrImageOrderMismatch):
+		return nil, ErrInvalidImageOrder
+	case err != nil:
+		return nil, err
+	}
+
+	s.publish(productID)
+	return s.repo.GetByProductID(productID)
+}
+
+func (s *imageService) Delete(productID, imageID uint, actor model.Actor) error {
+	if err := s.checkProduct(productID); err != nil {
+		return err
+	}
+
+	err := s.repo.Delete(productID, imageID, actor)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrImageNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.publish(productID)
+	return nil
+}
+
+// GenerateThumbnail orijinali blob store'dan okuyup en uzun kenarı
+// ThumbnailSize olacak şekilde küçültür. Şeffaflık taşıyabilen türler PNG,
+// diğerleri JPEG olarak kaydedilir. Görsel bu arada silindiyse bir şey yapmaz.
+func (s *imageService) GenerateThumbnail(ctx context.Context, imageID uint) error {
+	img, err := s.repo.Get(imageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if img.ThumbnailStatus != model.ThumbnailPending {
+		return nil
+	}
+
+	thumbnail, contentType, err := s.renderThumbnail(ctx, img)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) || errors.Is(err, image.ErrFormat) || errors.Is(err, ErrImageTooLarge) {
+			slog.WarnContext(ctx, "thumbnail cannot be generated", "image_id", img.ID, "error", err)
+			return s.repo.SetThumbnail(img.ID, "", "", model.ThumbnailFailed)
+		}
+		return err
+	}
+
+	extension := allowedImageTypes[contentType]
+	key := fmt.Sprintf("products/%d/%s_thumb.%s", img.ProductID, randomName(), extension)
+	if err := s.store.Put(ctx, key, thumbnail, contentType); err != nil {
+		return err
+	}
+	if err := s.repo.SetThumbnail(img.ID, key, s.store.URL(key), model.ThumbnailReady); err != nil {
+		return err
+	}
+
+	s.publish(img.ProductID)
+	return nil
+}
+
+func (s *imageService) renderThumbnail(ctx context.Context, img *model.ProductImage) ([]byte, string, error) {
+	reader, err := s.store.Get(ctx, img.Key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, "", err
+	}
+	// Sınır yüklemede kontrol edilir; sınır sonradan düşürülmüş ya da blob
+	// başka yoldan yazılmış olabileceği için çözmeden önce tekrar bakılır
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", image.ErrFormat, err)
+	}
+	if err := s.checkDimensions(config); err != nil {
+		return nil, "", err
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", image.ErrFormat, err)
+	}
+
+	bounds := source.Bounds()
+	width, height := thumbnailSize(bounds.Dx(), bounds.Dy(), s.limits.ThumbnailSize)
+	target := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(target, target.Bounds(), source, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if img.ContentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, target, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err = png.Encode(&buf, target)
+	return buf.Bytes(), "image/png", err
+}
+
+// ProcessPendingThumbnails kuyruğa giremeyen ya da servis yeniden
+// başladığı için yarım kalan küçük görselleri üretir
+func (s *imageService) ProcessPendingThumbnails(ctx context.Context) error {
+	pending, err := s.repo.GetPendingThumbnails(100)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, img := range pending {
+		if err := s.GenerateThumbnail(ctx, img.ID); err != nil {
+			errs = append(errs, fmt.Errorf("image %d: %w", img.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CollectGarbage silinen görsellerin blob'larını store'dan kaldırır; silinemeyen
+// anahtarlar kuyrukta kalır ve sonraki çalıştırmada tekrar denenir
+func (s *imageService) CollectGarbage(ctx context.Context) error {
+	deletions, err := s.repo.GetBlobDeletions(500)
+	if err != nil {
+		return err
+	}
+
+	done := make([]uint, 0, len(deletions))
+	var errs []error
+	for _, deletion := range deletions {
+		if err := s.store.Delete(ctx, deletion.Key); err != nil {
+			errs = append(errs, fmt.Errorf("blob %s: %w", deletion.Key, err))
+			continue
+		}
+		done = append(done, deletion.ID)
+	}
+
+	if err := s.repo.DeleteBlobDeletions(done); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *imageService) checkDimensions(config image.Config) error {
+	if s.limits.MaxPixels > 0 && int64(config.Width)*int64(config.Height) > int64(s.limits.MaxPixels) {
+		return fmt.Errorf("%w of %d pixels (%dx%d)", ErrImageTooLarge, s.limits.MaxPixels, config.Width, config.Height)
+	}
+	return nil
+}
+
+func (s *imageService) checkProduct(productID uint) error {
+	_, err := s.productRepo.GetByID(productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+func (s *imageService) enqueueThumbnail(imageID uint) {
+	select {
+	case s.thumbnails <- imageID:
+	default:
+	}
+}
+
+func (s *imageService) removeBlobs(ctx context.Context, images []model.ProductImage) {
+	for _, img := range images {
+		if err := s.store.Delete(ctx, img.Key); err != nil {
+			slog.ErrorContext(ctx, "failed to remove orphaned blob", "key", img.Key, "error", err)
+		}
+	}
+}
+
+func (s *imageService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductUpdated, ProductID: productID})
+}
+
+func randomName() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func thumbnailSize(width, height, max int) (int, int) {
+	if width <= max && height <= max {
+		return width, height
+	}
+	if width >= height {
+		return max, maxInt(1, height*max/width)
+	}
+	return maxInt(1, width*max/height), max
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
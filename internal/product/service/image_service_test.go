@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"cluster-iac/internal/product/storage"
+	"cluster-iac/internal/product/storage/storagetest"
+	"gorm.io/gorm"
+)
+
+type fakeImageRepo struct {
+	repository.ImageRepository
+	images map[uint]*model.ProductImage
+	nextID uint
+	// beforeCreate ayarlanırsa Create sınırı kontrol etmeden önce çağrılır
+	beforeCreate func()
+}
+
+func (r *fakeImageRepo) Create(images []model.ProductImage, maxPerProduct int, actor model.Actor) error {
+	if r.beforeCreate != nil {
+		r.beforeCreate()
+	}
+	if count, _ := r.CountByProductID(images[0].ProductID); int(count)+len(images) > maxPerProduct {
+		return repository.ErrImageLimitReached
+	}
+	for i := range images {
+		r.nextID++
+		images[i].ID = r.nextID
+		stored := images[i]
+		r.images[stored.ID] = &stored
+	}
+	return nil
+}
+
+func (r *fakeImageRepo) Get(imageID uint) (*model.ProductImage, error) {
+	img, ok := r.images[imageID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *img
+	return &copied, nil
+}
+
+func (r *fakeImageRepo) CountByProductID(productID uint) (int64, error) {
+	var count int64
+	for _, img := range r.images {
+		if img.ProductID == productID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeImageRepo) SetThumbnail(imageID uint, key, url string, status model.ThumbnailStatus) error {
+	img := r.images[imageID]
+	img.ThumbnailKey, img.ThumbnailURL, img.ThumbnailStatus = key, url, status
+	return nil
+}
+
+func (r *fakeImageRepo) GetPendingThumbnails(limit int) ([]model.ProductImage, error) {
+	var pending []model.ProductImage
+	for _, img := range r.images {
+		if img.ThumbnailStatus == model.ThumbnailPending {
+			pending = append(pending, *img)
+		}
+	}
+	return pending, nil
+}
+
+// fakeProductRepo görsel testlerinin yalnızca ürünün varlığını sorduğu repository'dir
+type fakeProductRepo struct {
+	repository.ProductRepository
+	ids map[uint]bool
+}
+
+func (r *fakeProductRepo) GetByID(id uint) (*model.Product, error) {
+	if !r.ids[id] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.Product{ID: id}, nil
+}
+
+type imageFixture struct {
+	repo       *fakeImageRepo
+	stub       *storagetest.S3Stub
+	store      storage.BlobStore
+	thumbnails chan uint
+	svc        ImageService
+}
+
+func newImageFixture(t *testing.T, limits ImageLimits) *imageFixture {
+	t.Helper()
+	stub := storagetest.NewS3Stub("media")
+	t.Cleanup(stub.Close)
+	f := &imageFixture{
+		repo:       &fakeImageRepo{images: map[uint]*model.ProductImage{}},
+		stub:       stub,
+		store:      storage.NewS3Store(storage.S3Config{Endpoint: stub.URL(), Bucket: "media", AccessKey: "a", SecretKey: "b"}),
+		thumbnails: make(chan uint, 10),
+	}
+	f.svc = NewImageService(f.repo, &fakeProductRepo{ids: map[uint]bool{1: true}}, f.store, nil, limits, f.thumbnails)
+	return f
+}
+
+var testImageLimits = ImageLimits{MaxBytes: 1 << 20, MaxPixels: 1_000_000, MaxPerProduct: 5, ThumbnailSize: 100}
+
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x*height/width, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageUploadAndThumbnail(t *testing.T) {
+	for _, tc := range []struct {
+		format, contentType, thumbExtension string
+		width, height                       int
+		thumbWidth, thumbHeight             int
+	}{
+		{"png", "image/png", ".png", 400, 200, 100, 50},
+		{"jpeg", "image/jpeg", ".jpg", 150, 300, 50, 100},
+		{"png", "image/png", ".png", 60, 40, 60, 40},
+	} {
+		f := newImageFixture(t, testImageLimits)
+		ctx := context.Background()
+
+		images, err := f.svc.Upload(ctx, 1, [][]byte{encodeTestImage(t, tc.format, tc.width, tc.height)}, model.Actor{Name: "test"})
+		if err != nil {
+			t.Fatalf("%s Upload: %v", tc.format, err)
+		}
+		img := images[0]
+		if img.ContentType != tc.contentType || img.Width != tc.width || img.Height != tc.height || img.ThumbnailStatus != model.ThumbnailPending {
+			t.Fatalf("%s uploaded image = %+v", tc.format, img)
+		}
+		if !strings.HasPrefix(img.Key, "products/1/") || img.URL != f.store.URL(img.Key) {
+			t.Fatalf("%s key = %q, url = %q", tc.format, img.Key, img.URL)
+		}
+		if _, _, ok := f.stub.Object(img.Key); !ok {
+			t.Fatalf("%s original was not stored", tc.format)
+		}
+		if queued := <-f.thumbnails; queued != img.ID {
+			t.Fatalf("%s queued image %d, want %d", tc.format, queued, img.ID)
+		}
+
+		if err := f.svc.GenerateThumbnail(ctx, img.ID); err != nil {
+			t.Fatalf("%s GenerateThumbnail: %v", tc.format, err)
+		}
+		stored := f.repo.images[img.ID]
+		if stored.ThumbnailStatus != model.ThumbnailReady || !strings.HasSuffix(stored.ThumbnailKey, "_thumb"+tc.thumbExtension) {
+			t.Fatalf("%s thumbnail = %q %q", tc.format, stored.ThumbnailStatus, stored.ThumbnailKey)
+		}
+		data, contentType, ok := f.stub.Object(stored.ThumbnailKey)
+		if !ok || contentType != tc.contentType {
+			t.Fatalf("%s thumbnail blob = %v %q", tc.format, ok, contentType)
+		}
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil || config.Width != tc.thumbWidth || config.Height != tc.thumbHeight {
+			t.Fatalf("%s thumbnail size = %dx%d (%v), want %dx%d", tc.format, config.Width, config.Height, err, tc.thumbWidth, tc.thumbHeight)
+		}
+
+		// Hazır küçük görsel tekrar üretilmez
+		if err := f.svc.GenerateThumbnail(ctx, img.ID); err != nil || len(f.stub.Keys()) != 2 {
+			t.Fatalf("%s second GenerateThumbnail: %v, keys %v", tc.format, err, f.stub.Keys())
+		}
+	}
+}
+
+func TestImageUploadRejectsOversizedImages(t *testing.T) {
+	f := newImageFixture(t, testImageLimits)
+
+	_, err := f.svc.Upload(context.Background(), 1, [][]byte{encodeTestImage(t, "png", 2000, 501)}, model.Actor{Name: "test"})
+	if !errors.Is(err, ErrImageTooLarge) || !strings.Contains(err.Error(), "pixels") {
+		t.Fatalf("Upload of 2000x501 = %v, want ErrImageTooLarge", err)
+	}
+
+	limits := testImageLimits
+	limits.MaxBytes = 10
+	f = newImageFixture(t, limits)
+	if _, err := f.svc.Upload(context.Background(), 1, [][]byte{encodeTestImage(t, "png", 10, 10)}, model.Actor{Name: "test"}); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("Upload over MaxBytes = %v, want ErrImageTooLarge", err)
+	}
+	if keys := f.stub.Keys(); len(keys) != 0 {
+		t.Fatalf("rejected uploads were stored: %v", keys)
+	}
+
+	f = newImageFixture(t, testImageLimits)
+	if _, err := f.svc.Upload(context.Background(), 1, [][]byte{[]byte("not an image")}, model.Actor{Name: "test"}); !errors.Is(err, ErrUnsupportedImage) {
+		t.Fatalf("Upload of text = %v, want ErrUnsupportedImage", err)
+	}
+}
+
+func TestThumbnailFailsForUnusableBlobs(t *testing.T) {
+	f := newImageFixture(t, testImageLimits)
+	ctx := context.Background()
+
+	blobs := map[string][]byte{
+		// Sınır yüklemeden sonra düşürülmüş olabilir; worker çözmeden önce tekrar bakar
+		"products/1/huge.png":    encodeTestImage(t, "png", 1100, 1000),
+		"products/1/corrupt.png": []byte("\x89PNG\r\n\x1a\ngarbage"),
+	}
+	for key, data := range blobs {
+		if err := f.store.Put(ctx, key, data, "image/png"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = f.repo.Create([]model.ProductImage{
+		{ProductID: 1, Key: "products/1/huge.png", ContentType: "image/png", ThumbnailStatus: model.ThumbnailPending},
+		{ProductID: 1, Key: "products/1/corrupt.png", ContentType: "image/png", ThumbnailStatus: model.ThumbnailPending},
+		{ProductID: 1, Key: "products/1/missing.png", ContentType: "image/png", ThumbnailStatus: model.ThumbnailPending},
+	}, testImageLimits.MaxPerProduct, model.Actor{Name: "test"})
+
+	if err := f.svc.ProcessPendingThumbnails(ctx); err != nil {
+		t.Fatalf("ProcessPendingThumbnails: %v", err)
+	}
+	for id, img := range f.repo.images {
+		if img.ThumbnailStatus != model.ThumbnailFailed {
+			t.Fatalf("image %d (%s) status = %q, want failed", id, img.Key, img.ThumbnailStatus)
+		}
+	}
+	if keys := f.stub.Keys(); len(keys) != 2 {
+		t.Fatalf("unexpected thumbnails were stored: %v", keys)
+	}
+}
+
+func TestImageUploadRechecksLimitWhenCreating(t *testing.T) {
+	f := newImageFixture(t, testImageLimits)
+	ctx := context.Background()
+	data := encodeTestImage(t, "png", 10, 10)
+	if _, err := f.svc.Upload(ctx, 1, [][]byte{data, data, data}, model.Actor{Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	stored := f.stub.Keys()
+
+	// İlk sayımdan sonra eşzamanlı bir yükleme son boş yeri doldurur
+	f.repo.beforeCreate = func() {
+		f.repo.beforeCreate = nil
+		f.repo.nextID++
+		f.repo.images[f.repo.nextID] = &model.ProductImage{ID: f.repo.nextID, ProductID: 1}
+	}
+	_, err := f.svc.Upload(ctx, 1, [][]byte{data, data}, model.Actor{Name: "test"})
+	if !errors.Is(err, ErrTooManyImages) || !strings.Contains(err.Error(), "of 5") {
+		t.Fatalf("Upload past the limit = %v, want ErrTooManyImages", err)
+	}
+	if count, _ := f.repo.CountByProductID(1); count != 4 {
+		t.Fatalf("images = %d, want 4", count)
+	}
+	if keys := f.stub.Keys(); len(keys) != len(stored) {
+		t.Fatalf("blobs of the rejected upload were kept: %v", keys)
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"cluster-iac/internal/product/model"
+)
+
+// importColumns CSV başlığında kabul edilen kolonlar; NDJSON satırları aynı
+// alan adlarını kullanır
+var importColumns = map[string]bool{
+	"external_id":       true,
+	"sku":               true,
+	"name":              true,
+	"description":       true,
+	"price":             true,
+	"currency":          true,
+	"reorder_threshold": true,
+	"tax_class":         true,
+	"weight_kg":         true,
+	"length_cm":         true,
+	"width_cm":          true,
+	"height_cm":         true,
+	"category":          true,
+	"category_id":       true,
+	"image_url":         true,
+}
+
+// rowError tek bir satırın ayrıştırılamadığını belirtir; dosyanın geri
+// kalanı işlenmeye devam eder
+type rowError struct {
+	line int
+	raw  string
+	err  error
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+type recordReader interface {
+	// Next dosya bitince io.EOF, okunamayan satırda *rowError döner
+	Next() (model.ImportRecord, error)
+}
+
+func newRecordReader(format model.ImportFormat, r io.Reader) (recordReader, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		return newCSVRecordReader(r)
+	case model.ImportFormatNDJSON:
+		return newNDJSONRecordReader(r), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidImport, format)
+	}
+}
+
+type csvRecordReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+func newCSVRecordReader(r io.Reader) (*csvRecordReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !importColumns[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImport, name)
+		}
+		columns[i] = name
+	}
+
+	return &csvRecordReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvRecordReader) Next() (model.ImportRecord, error) {
+	fields, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return model.ImportRecord{}, io.EOF
+	}
+
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return model.ImportRecord{}, &rowError{line: parseErr.StartLine, raw: encodeCSVLine(fields), err: parseErr.Err}
+		}
+		return model.ImportRecord{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	raw := encodeCSVLine(fields)
+	if len(fields) != len(r.columns) {
+		return model.ImportRecord{}, &rowError{line: line, raw: raw, err: fmt.Errorf("expected %d fields, got %d", len(r.columns), len(fields))}
+	}
+
+	record := model.ImportRecord{Line: line, Raw: raw}
+	for i, value := range fields {
+		value = strings.TrimSpace(value)
+		// Boş hücre güncellemede mevcut değeri korur
+		if value == "" {
+			continue
+		}
+		if err := setImportField(&record, r.columns[i], value); err != nil {
+			return model.ImportRecord{}, &rowError{line: line, raw: raw, err: err}
+		}
+	}
+	return record, nil
+}
+
+func setImportField(record *model.ImportRecord, column, value string) error {
+	switch column {
+	case "external_id":
+		record.ExternalID = value
+	case "sku":
+		record.SKU = value
+	case "name":
+		record.Name = &value
+	case "description":
+		record.Description = &value
+	case "image_url":
+		record.ImageURL = &value
+	case "tax_class":
+		record.TaxClass = &value
+	case "currency":
+		record.Currency = &value
+	case "category":
+		record.CategorySlug = value
+	case "price":
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid price %q", value)
+		}
+		record.Price = &price
+	case "weight_kg", "length_cm", "width_cm", "height_cm":
+		measure, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", column, value)
+		}
+		switch column {
+		case "weight_kg":
+			record.WeightKg = &measure
+		case "length_cm":
+			record.LengthCm = &measure
+		case "width_cm":
+			record.WidthCm = &measure
+		default:
+			record.HeightCm = &measure
+		}
+	case "reorder_threshold":
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid reorder_threshold %q", value)
+		}
+		record.ReorderThreshold = &threshold
+	case "category_id":
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid category_id %q", value)
+		}
+		categoryID := uint(id)
+		record.CategoryID = &categoryID
+	}
+	return nil
+}
+
+func encodeCSVLine(fields []string) string {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write(fields)
+	writer.Flush()
+	return strings.TrimRight(buf.String(), "\r\n")
+}
+
+type ndjsonRecordReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+type ndjsonRow struct {
+	ExternalID       string   `json:"external_id"`
+	SKU              string   `json:"sku"`
+	Name             *string  `json:"name"`
+	Description      *string  `json:"description"`
+	Price            *float64 `json:"price"`
+	Currency         *string  `json:"currency"`
+	ReorderThreshold *int     `json:"reorder_threshold"`
+	TaxClass         *string  `json:"tax_class"`
+	WeightKg         *float64 `json:"weight_kg"`
+	LengthCm         *float64 `json:"length_cm"`
+	WidthCm          *float64 `json:"width_cm"`
+	HeightCm         *float64 `json:"height_cm"`
+	Category         string   `json:"category"`
+	CategoryID       *uint    `json:"category_id"`
+	ImageURL         *string  `json:"image_url"`
+}
+
+func newNDJSONRecordReader(r io.Reader) *ndjsonRecordReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &ndjsonRecordReader{scanner: scanner}
+}
+
+func (r *ndjsonRecordReader) Next() (model.ImportRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+		raw := strings.TrimSpace(r.scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var row ndjsonRow
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return model.ImportRecord{}, &rowError{line: r.line, raw: raw, err: err}
+		}
+
+		return model.ImportRecord{
+			Line:             r.line,
+			Raw:              raw,
+			ExternalID:       strings.TrimSpace(row.ExternalID),
+			SKU:              strings.TrimSpace(row.SKU),
+			Name:             row.Name,
+			Description:      row.Description,
+			Price:            row.Price,
+			Currency:         row.Currency,
+			ReorderThreshold: row.ReorderThreshold,
+			TaxClass:         row.TaxClass,
+			WeightKg:         row.WeightKg,
+			LengthCm:         row.LengthCm,
+			WidthCm:          row.WidthCm,
+			HeightCm:         row.HeightCm,
+			CategorySlug:     strings.TrimSpace(row.Category),
+			CategoryID:       row.CategoryID,
+			ImageURL:         row.ImageURL,
+		}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return model.ImportRecord{}, err
+	}
+	return model.ImportRecord{}, io.EOF
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"cluster-iac/internal/product/model"
+)
+
+// readAll okunan kayıtları ve satır hatalarını sırayla döndürür
+func readAll(t *testing.T, reader recordReader) ([]model.ImportRecord, []*rowError) {
+	t.Helper()
+	var records []model.ImportRecord
+	var rowErrors []*rowError
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records, rowErrors
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVRecordReader(t *testing.T) {
+	file := "\ufeffExternal_ID, name,price,category,weight_kg\n" +
+		"ERP-1,Mug,10.5,kitchen,0.4\n" +
+		"ERP-2,,,,\n" +
+		"ERP-3,Plate,cheap,,\n" +
+		"ERP-4,Bowl\n" +
+		"\"ERP-5\",\"Cup, large\",3,,\n"
+	reader, err := newRecordReader(model.ImportFormatCSV, strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, rowErrors := readAll(t, reader)
+
+	if len(records) != 3 {
+		t.Fatalf("records = %+v", records)
+	}
+	first := records[0]
+	if first.Line != 2 || first.ExternalID != "ERP-1" || *first.Name != "Mug" || *first.Price != 10.5 || first.CategorySlug != "kitchen" || *first.WeightKg != 0.4 {
+		t.Fatalf("first record = %+v", first)
+	}
+	// Boş hücreler nil kalır; güncellemede mevcut değer korunur
+	if second := records[1]; second.ExternalID != "ERP-2" || second.Name != nil || second.Price != nil || second.WeightKg != nil {
+		t.Fatalf("record with empty cells = %+v", second)
+	}
+	if third := records[2]; *third.Name != "Cup, large" || third.Raw != `ERP-5,"Cup, large",3,,` {
+		t.Fatalf("quoted record = %+v", third)
+	}
+
+	if len(rowErrors) != 2 {
+		t.Fatalf("row errors = %v", rowErrors)
+	}
+	if rowErrors[0].line != 4 || !strings.Contains(rowErrors[0].Error(), `invalid price "cheap"`) || rowErrors[0].raw != "ERP-3,Plate,cheap,," {
+		t.Fatalf("price error = %+v", rowErrors[0])
+	}
+	if rowErrors[1].line != 5 || !strings.Contains(rowErrors[1].Error(), "expected 5 fields, got 2") {
+		t.Fatalf("field count error = %+v", rowErrors[1])
+	}
+}
+
+func TestCSVRecordReaderRejectsFile(t *testing.T) {
+	for name, file := range map[string]string{
+		"empty":          "",
+		"unknown column": "external_id,stock\nERP-1,5\n",
+	} {
+		if _, err := newRecordReader(model.ImportFormatCSV, strings.NewReader(file)); !errors.Is(err, ErrInvalidImport) {
+			t.Fatalf("%s: err = %v, want ErrInvalidImport", name, err)
+		}
+	}
+	if _, err := newRecordReader("xml", strings.NewReader("")); !errors.Is(err, ErrInvalidImport) {
+		t.Fatalf("unknown format: err = %v", err)
+	}
+}
+
+func TestNDJSONRecordReader(t *testing.T) {
+	file := `{"external_id": " ERP-1 ", "name": "Mug", "price": 10, "category_id": 3}` + "\n" +
+		"\n" +
+		`{"sku": "MUG-RED", "price": 11}` + "\n" +
+		`{"external_id": "ERP-2", "stock": 5}` + "\n" +
+		`{"external_id": "ERP-3", "price": "ten"}` + "\n" +
+		`not json`
+	records, rowErrors := readAll(t, newNDJSONRecordReader(strings.NewReader(file)))
+
+	if len(records) != 2 {
+		t.Fatalf("records = %+v", records)
+	}
+	if first := records[0]; first.Line != 1 || first.ExternalID != "ERP-1" || *first.Price != 10 || *first.CategoryID != 3 || first.Key() != "external_id:ERP-1" {
+		t.Fatalf("first record = %+v", first)
+	}
+	if second := records[1]; second.Line != 3 || second.Key() != "sku:MUG-RED" || second.Name != nil {
+		t.Fatalf("sku record = %+v", second)
+	}
+
+	// Bilinmeyen alan, yanlış tip ve bozuk JSON satır hatasıdır
+	if len(rowErrors) != 3 || rowErrors[0].line != 4 || rowErrors[1].line != 5 || rowErrors[2].line != 6 {
+		t.Fatalf("row errors = %v", rowErrors)
+	}
+	if !strings.Contains(rowErrors[0].Error(), `unknown field "stock"`) {
+		t.Fatalf("unknown field error = %v", rowErrors[0])
+	}
+}
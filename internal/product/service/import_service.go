@@ -0,0 +1,382 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type ImportService interface {
+	StartImport(format model.ImportFormat, dryRun bool, src io.Reader, actor model.Actor) (*model.ImportJob, error)
+	GetJob(id uint) (*model.ImportJob, error)
+	GetRowResults(id uint, action model.ImportAction) ([]model.ImportRowResult, error)
+	WriteErrorReport(id uint, w io.Writer) error
+	RecoverInterrupted() error
+}
+
+type importService struct {
+	repo       repository.ImportRepository
+	publisher  events.Publisher
+	currencies *currency.Converter
+	dir        string
+	batchSize  int
+
+	// İşler sırayla çalışır; kuyruktakiler pending durumunda bekler
+	slots chan struct{}
+}
+
+func NewImportService(repo repository.ImportRepository, publisher events.Publisher, currencies *currency.Converter, dir string, batchSize int) ImportService {
+	return &importService{
+		repo:       repo,
+		publisher:  publisher,
+		currencies: currencies,
+		dir:        dir,
+		batchSize:  batchSize,
+		slots:      make(chan struct{}, 1),
+	}
+}
+
+// StartImport yüklenen dosyayı diske yazar, başlığını doğrular ve işi arka
+// planda başlatır. Dosya işlendikten sonra silinir.
+func (s *importService) StartImport(format model.ImportFormat, dryRun bool, src io.Reader, actor model.Actor) (*model.ImportJob, error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(s.dir, "import-*."+string(format))
+	if err != nil {
+		return nil, err
+	}
+	path := file.Name()
+
+	if _, err := io.Copy(file, src); err != nil {
+		file.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	total, err := countImportRows(format, path)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	job := &model.ImportJob{
+		Format:    format,
+		DryRun:    dryRun,
+		Status:    model.ImportPending,
+		TotalRows: total,
+		Actor:     actor.Name,
+		RequestID: actor.RequestID,
+		FilePath:  path,
+	}
+	if err := s.repo.CreateJob(job); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	go s.run(*job)
+	return job, nil
+}
+
+func (s *importService) GetJob(id uint) (*model.ImportJob, error) {
+	job, err := s.repo.GetJob(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImportNotFound
+	}
+	return job, err
+}
+
+func (s *importService) GetRowResults(id uint, action model.ImportAction) ([]model.ImportRowResult, error) {
+	if _, err := s.GetJob(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRowResults(id, action)
+}
+
+// WriteErrorReport reddedilen satırları satır numarası, anahtar, hata ve
+// orijinal satır kolonlarıyla CSV olarak yazar
+func (s *importService) WriteErrorReport(id uint, w io.Writer) error {
+	rejected, err := s.GetRowResults(id, model.ImportActionRejected)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"line", "key", "error", "row"}); err != nil {
+		return err
+	}
+	for _, row := range rejected {
+		if err := writer.Write([]string{strconv.Itoa(row.Line), row.Key, row.Message, row.Raw}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *importService) RecoverInterrupted() error {
+	count, err := s.repo.FailInterruptedJobs("interrupted by service restart")
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		slog.Warn("marked interrupted import jobs as failed", "count", count)
+	}
+	return nil
+}
+
+func (s *importService) run(job model.ImportJob) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+	defer os.Remove(job.FilePath)
+
+	now := time.Now()
+	job.Status = model.ImportRunning
+	job.StartedAt = &now
+	if err := s.repo.UpdateJob(&job); err != nil {
+		slog.Error("import job could not be started", "job_id", job.ID, "error", err)
+		return
+	}
+
+	err := s.process(&job)
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Status = model.ImportCompleted
+	if err != nil {
+		job.Status = model.ImportFailed
+		job.Error = err.Error()
+		slog.Error("import job failed", "job_id", job.ID, "request_id", job.RequestID, "error", err)
+	}
+	if err := s.repo.UpdateJob(&job); err != nil {
+		slog.Error("import job could not be saved", "job_id", job.ID, "error", err)
+	}
+}
+
+func (s *importService) process(job *model.ImportJob) error {
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := newRecordReader(job.Format, file)
+	if err != nil {
+		return err
+	}
+
+	batch := make([]model.ImportRecord, 0, s.batchSize)
+	var rejected []model.ImportRowResult
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *rowError
+		switch {
+		case errors.As(err, &parseErr):
+			rejected = append(rejected, model.ImportRowResult{
+				Line:    parseErr.line,
+				Action:  model.ImportActionRejected,
+				Message: parseErr.err.Error(),
+				Raw:     parseErr.raw,
+			})
+		case err != nil:
+			return err
+		default:
+			if message := validateImportRecord(&record, s.currencies); message != "" {
+				rejected = append(rejected, model.ImportRowResult{
+					Line:    record.Line,
+					Key:     record.Key(),
+					Action:  model.ImportActionRejected,
+					Message: message,
+					Raw:     record.Raw,
+				})
+			} else {
+				batch = append(batch, record)
+			}
+		}
+
+		if len(batch)+len(rejected) >= s.batchSize {
+			if err := s.flush(job, batch, rejected); err != nil {
+				return err
+			}
+			batch, rejected = batch[:0], nil
+		}
+	}
+
+	return s.flush(job, batch, rejected)
+}
+
+// flush batch'i uygular, satır sonuçlarını kaydeder ve iş ilerlemesini günceller
+func (s *importService) flush(job *model.ImportJob, batch []model.ImportRecord, rejected []model.ImportRowResult) error {
+	var results []model.ImportRowResult
+	if len(batch) > 0 {
+		applied, err := s.repo.ApplyBatch(batch, job.DryRun, jobActor(job))
+		if err != nil {
+			return err
+		}
+		results = applied
+	}
+	results = append(results, rejected...)
+
+	stored := make([]model.ImportRowResult, 0, len(results))
+	for _, result := range results {
+		switch result.Action {
+		case model.ImportActionCreate:
+			job.Created++
+		case model.ImportActionUpdate:
+			job.Updated++
+		case model.ImportActionUnchanged:
+			job.Unchanged++
+			continue
+		case model.ImportActionRejected:
+			job.Rejected++
+		}
+
+		result.JobID = job.ID
+		stored = append(stored, result)
+
+		if !job.DryRun && result.ProductID != nil {
+			s.publish(result.Action, *result.ProductID)
+		}
+	}
+
+	if err := s.repo.SaveRowResults(stored); err != nil {
+		return err
+	}
+
+	job.ProcessedRows += len(results)
+	return s.repo.UpdateJob(job)
+}
+
+// jobActor işin denetim kayıtlarının aktörüdür; içe aktarma yalnızca HTTP
+// ile başlatılır
+func jobActor(job *model.ImportJob) model.Actor {
+	return model.Actor{Name: job.Actor, Source: model.AuditSourceHTTP, RequestID: job.RequestID}
+}
+
+func (s *importService) publish(action model.ImportAction, productID uint) {
+	if s.publisher == nil {
+		return
+	}
+
+	eventType := events.ProductUpdated
+	if action == model.ImportActionCreate {
+		eventType = events.ProductCreated
+	}
+	s.publisher.Publish(events.Event{Type: eventType, ProductID: productID})
+}
+
+// validateImportRecord veritabanına gitmeden yapılabilen kontrolleri yapar;
+// satır reddedilecekse nedenini döner. Para birimi verilmeyen satırlarda
+// fiyatın basamak sayısı 2 kabul edilir.
+func validateImportRecord(record *model.ImportRecord, currencies *currency.Converter) string {
+	if record.ExternalID == "" && record.SKU == "" {
+		return "external_id or sku is required"
+	}
+	if record.Name != nil {
+		name := strings.TrimSpace(*record.Name)
+		if code, message := nameRule(name); code != "" {
+			return "name " + message
+		}
+		record.Name = &name
+	}
+	places := 2
+	if record.Currency != nil {
+		code := currency.Normalize(*record.Currency)
+		if rule, message := currencyRule(code, currencies); rule != "" {
+			return "currency " + message
+		}
+		record.Currency = &code
+		places = currencies.Decimals(code)
+	}
+	if record.Price != nil {
+		if code, message := priceRule(*record.Price, places); code != "" {
+			return "price " + message
+		}
+	}
+	if record.ReorderThreshold != nil && *record.ReorderThreshold < 0 {
+		return "reorder_threshold must not be negative"
+	}
+	if record.TaxClass != nil {
+		taxClass := strings.ToLower(strings.TrimSpace(*record.TaxClass))
+		if code, message := taxClassRule(taxClass); code != "" {
+			return "tax_class " + message
+		}
+		record.TaxClass = &taxClass
+	}
+	if record.WeightKg != nil {
+		if code, message := measureRule(*record.WeightKg, maxWeightKg); code != "" {
+			return "weight_kg " + message
+		}
+	}
+	for _, dimension := range []struct {
+		field string
+		value *float64
+	}{{"length_cm", record.LengthCm}, {"width_cm", record.WidthCm}, {"height_cm", record.HeightCm}} {
+		if dimension.value == nil {
+			continue
+		}
+		if code, message := measureRule(*dimension.value, maxDimensionCm); code != "" {
+			return dimension.field + " " + message
+		}
+	}
+	if record.ImageURL != nil {
+		imageURL := strings.TrimSpace(*record.ImageURL)
+		if code, message := imageURLRule(imageURL); code != "" {
+			return "image_url " + message
+		}
+		record.ImageURL = &imageURL
+	}
+	if record.CategorySlug != "" {
+		record.CategorySlug = model.Slugify(record.CategorySlug)
+	}
+	return ""
+}
+
+// countImportRows ilerleme yüzdesi için dosyadaki satır sayısını bulur; başlık
+// geçersizse hata döner ve iş hiç oluşturulmaz
+func countImportRows(format model.ImportFormat, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader, err := newRecordReader(format, file)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		_, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return total, nil
+		}
+		var parseErr *rowError
+		if err != nil && !errors.As(err, &parseErr) {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		total++
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type InventoryService interface {
+	CreateWarehouse(warehouse *model.Warehouse) error
+	GetWarehouseByID(id uint) (*model.Warehouse, error)
+	GetWarehouses() ([]model.Warehouse, error)
+	UpdateWarehouse(warehouse *model.Warehouse) error
+	GetProductInventory(productID uint) (*model.ProductInventory, error)
+	RecordMovement(movement *model.InventoryMovement) error
+	GetMovements(filter repository.MovementFilter) ([]model.InventoryMovement, error)
+}
+
+type inventoryService struct {
+	repo        repository.InventoryRepository
+	productRepo repository.ProductRepository
+	publisher   events.Publisher
+}
+
+func NewInventoryService(repo repository.InventoryRepository, productRepo repository.ProductRepository, publisher events.Publisher) InventoryService {
+	return &inventoryService{
+		repo:        repo,
+		productRepo: productRepo,
+		publisher:   publisher,
+	}
+}
+
+func (s *inventoryService) CreateWarehouse(warehouse *model.Warehouse) error {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+
+	_, err := s.repo.GetWarehouseByCode(warehouse.Code)
+	if err == nil {
+		return ErrWarehouseCodeTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.repo.CreateWarehouse(warehouse)
+}
+
+func (s *inventoryService) GetWarehouseByID(id uint) (*model.Warehouse, error) {
+	warehouse, err := s.repo.GetWarehouseByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWarehouseNotFound
+	}
+	return warehouse, err
+}
+
+func (s *inventoryService) GetWarehouses() ([]model.Warehouse, error) {
+	return s.repo.GetWarehouses()
+}
+
+func (s *inventoryService) UpdateWarehouse(warehouse *model.Warehouse) error {
+	productIDs, err := s.repo.UpdateWarehouse(warehouse)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWarehouseNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, id := range productIDs {
+		s.publish(id)
+	}
+	return nil
+}
+
+func (s *inventoryService) GetProductInventory(productID uint) (*model.ProductInventory, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	levels, err := s.repo.GetStockLevels(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &model.ProductInventory{ProductID: productID, Levels: levels}
+	for _, level := range levels {
+		if level.Warehouse != nil && level.Warehouse.Active {
+			inventory.AvailableToSell += level.Quantity
+		}
+	}
+	return inventory, nil
+}
+
+// RecordMovement hareketin türüne göre gereken depoları doğrular ve hareketi
+// uygular. receive hedef, ship kaynak, transfer her iki depoyu ister; adjust
+// hedef depodaki stoğu işaretli miktar kadar değiştirir.
+func (s *inventoryService) RecordMovement(movement *model.InventoryMovement) error {
+	if err := validateMovement(movement); err != nil {
+		return err
+	}
+
+	if _, err := s.productRepo.GetByID(movement.ProductID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+
+	if movement.FromWarehouseID != nil {
+		if _, err := s.GetWarehouseByID(*movement.FromWarehouseID); err != nil {
+			return err
+		}
+	}
+
+	// Pasif depodan çıkış yapılabilir ama pasif depoya giriş yapılamaz
+	if movement.ToWarehouseID != nil {
+		warehouse, err := s.GetWarehouseByID(*movement.ToWarehouseID)
+		if err != nil {
+			return err
+		}
+		if !warehouse.Active {
+			return ErrWarehouseInactive
+		}
+	}
+
+	err := s.repo.ApplyMovement(movement)
+	if errors.Is(err, repository.ErrInsufficientStock) {
+		return ErrInsufficientStock
+	}
+	if err != nil {
+		return err
+	}
+
+	s.publish(movement.ProductID)
+	return nil
+}
+
+func (s *inventoryService) GetMovements(filter repository.MovementFilter) ([]model.InventoryMovement, error) {
+	return s.repo.GetMovements(filter)
+}
+
+func (s *inventoryService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductStockChanged, ProductID: productID})
+}
+
+func validateMovement(m *model.InventoryMovement) error {
+	m.Reason = strings.TrimSpace(m.Reason)
+	m.Actor = strings.TrimSpace(m.Actor)
+	if m.Reason == "" || m.Actor == "" {
+		return fmt.Errorf("%w: reason and actor are required", ErrInvalidMovement)
+	}
+
+	switch m.Type {
+	case model.MovementReceive, model.MovementAdjust:
+		if m.ToWarehouseID == nil || m.FromWarehouseID != nil {
+			return fmt.Errorf("%w: %s requires only to_warehouse_id", ErrInvalidMovement, m.Type)
+		}
+	case model.MovementShip:
+		if m.FromWarehouseID == nil || m.ToWarehouseID != nil {
+			return fmt.Errorf("%w: ship requires only from_warehouse_id", ErrInvalidMovement)
+		}
+	case model.MovementTransfer:
+		if m.FromWarehouseID == nil || m.ToWarehouseID == nil || *m.FromWarehouseID == *m.ToWarehouseID {
+			return fmt.Errorf("%w: transfer requires two different warehouses", ErrInvalidMovement)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidMovement, m.Type)
+	}
+
+	if m.Type == model.MovementAdjust {
+		if m.Quantity == 0 {
+			return fmt.Errorf("%w: adjust quantity must not be zero", ErrInvalidMovement)
+		}
+	} else if m.Quantity <= 0 {
+		return fmt.Errorf("%w: quantity must be positive", ErrInvalidMovement)
+	}
+	return nil
+}
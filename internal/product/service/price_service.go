@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type PriceService interface {
+	GetTimeline(productID uint, since *time.Time) (*model.PriceTimeline, error)
+	SchedulePrice(schedule *model.ScheduledPrice) error
+	CancelSchedule(productID, scheduleID uint, actor model.Actor) error
+	ApplySchedules(now time.Time) (int, error)
+}
+
+type priceService struct {
+	repo        repository.PriceRepository
+	productRepo repository.ProductRepository
+	publisher   events.Publisher
+}
+
+func NewPriceService(repo repository.PriceRepository, productRepo repository.ProductRepository, publisher events.Publisher) PriceService {
+	return &priceService{
+		repo:        repo,
+		productRepo: productRepo,
+		publisher:   publisher,
+	}
+}
+
+// GetTimeline fiyat geçmişini ve henüz kapanmamış planlı değişiklikleri döndürür
+func (s *priceService) GetTimeline(productID uint, since *time.Time) (*model.PriceTimeline, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetHistory(productID, since)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := s.repo.GetSchedules(productID, model.ScheduledPricePending, model.ScheduledPriceActive)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PriceTimeline{
+		ProductID:    productID,
+		CurrentPrice: product.Price,
+		History:      history,
+		Scheduled:    scheduled,
+	}, nil
+}
+
+func (s *priceService) SchedulePrice(schedule *model.ScheduledPrice) error {
+	if schedule.Price <= 0 {
+		return fmt.Errorf("%w: price must be positive", ErrInvalidSchedule)
+	}
+	if schedule.StartsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at is required", ErrInvalidSchedule)
+	}
+	if schedule.EndsAt != nil {
+		if !schedule.EndsAt.After(schedule.StartsAt) {
+			return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidSchedule)
+		}
+		if !schedule.EndsAt.After(time.Now()) {
+			return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidSchedule)
+		}
+	}
+
+	if _, err := s.productRepo.GetByID(schedule.ProductID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+
+	overlaps, err := s.repo.HasOverlappingSchedule(schedule.ProductID, schedule.StartsAt, schedule.EndsAt)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrScheduleOverlap
+	}
+
+	schedule.Status = model.ScheduledPricePending
+	schedule.OriginalPrice = nil
+	return s.repo.CreateSchedule(schedule)
+}
+
+// CancelSchedule bekleyen planı iptal eder; aktif planı iptal etmek fiyatı
+// hemen önceki değerine döndürür
+func (s *priceService) CancelSchedule(productID, scheduleID uint, actor model.Actor) error {
+	if _, err := s.repo.GetScheduleByID(productID, scheduleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+
+	reverted, err := s.repo.EndSchedule(scheduleID, model.ScheduledPriceCancelled, time.Now(), actor)
+	if errors.Is(err, repository.ErrScheduleNotPending) {
+		return ErrScheduleClosed
+	}
+	if err != nil {
+		return err
+	}
+
+	if reverted {
+		s.publish(productID)
+	}
+	return nil
+}
+
+// ApplySchedules önce süresi dolan aktif planları geri alır, sonra zamanı
+// gelen planları uygular; böylece art arda gelen kampanyalarda yeni plan
+// kampanya öncesi fiyatı orijinal fiyat olarak görür. Değişen fiyat sayısını döner.
+func (s *priceService) ApplySchedules(now time.Time) (int, error) {
+	changed := 0
+	actor := model.SystemActor("price-scheduler")
+
+	expired, err := s.repo.GetExpiredSchedules(now)
+	if err != nil {
+		return changed, err
+	}
+	for _, schedule := range expired {
+		reverted, err := s.repo.EndSchedule(schedule.ID, model.ScheduledPriceCompleted, now, actor)
+		if err != nil {
+			slog.Error("failed to end scheduled price", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if reverted {
+			changed++
+			s.publish(schedule.ProductID)
+		}
+	}
+
+	due, err := s.repo.GetDueSchedules(now)
+	if err != nil {
+		return changed, err
+	}
+	for _, schedule := range due {
+		// Servis kapalıyken penceresi tamamen geçmiş planlar hiç uygulanmaz
+		if schedule.EndsAt != nil && !schedule.EndsAt.After(now) {
+			if _, err := s.repo.EndSchedule(schedule.ID, model.ScheduledPriceCompleted, now, actor); err != nil {
+				slog.Error("failed to close missed scheduled price", "schedule_id", schedule.ID, "error", err)
+			}
+			continue
+		}
+
+		applied, err := s.repo.ActivateSchedule(schedule.ID, now, actor)
+		if err != nil {
+			slog.Error("failed to apply scheduled price", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if applied {
+			changed++
+			s.publish(schedule.ProductID)
+		}
+	}
+
+	return changed, nil
+}
+
+func (s *priceService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductUpdated, ProductID: productID})
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+// PricingService ürün fiyatlarını istenen para biriminde sunar ve para
+// birimi başına fiyat listelerini yönetir. Fiyat listesinde olmayan
+// fiyatlar kur tablosuyla çevrilir.
+type PricingService interface {
+	Negotiate(query, header string) (string, error)
+	Rates() currency.Rates
+	Present(product *model.Product, code string) error
+	PresentAll(products []model.Product, code string) error
+	GetPriceList(code string) ([]model.PriceListEntry, error)
+	GetProductPriceLists(productID uint) ([]model.PriceListEntry, error)
+	SetListPrice(entry *model.PriceListEntry, actor model.Actor) error
+	DeleteListPrice(code string, productID, variantID uint, actor model.Actor) error
+}
+
+type pricingService struct {
+	repo        repository.PriceListRepository
+	productRepo repository.ProductRepository
+	variantRepo repository.VariantRepository
+	currencies  *currency.Converter
+	publisher   events.Publisher
+}
+
+func NewPricingService(repo repository.PriceListRepository, productRepo repository.ProductRepository, variantRepo repository.VariantRepository, currencies *currency.Converter, publisher events.Publisher) PricingService {
+	return &pricingService{
+		repo:        repo,
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		currencies:  currencies,
+		publisher:   publisher,
+	}
+}
+
+func (s *pricingService) Negotiate(query, header string) (string, error) {
+	return s.currencies.Negotiate(query, header)
+}
+
+func (s *pricingService) Rates() currency.Rates {
+	return s.currencies.Rates()
+}
+
+func (s *pricingService) Present(product *model.Product, code string) error {
+	if code == "" {
+		return nil
+	}
+	return s.present([]*model.Product{product}, code)
+}
+
+func (s *pricingService) PresentAll(products []model.Product, code string) error {
+	if code == "" || len(products) == 0 {
+		return nil
+	}
+
+	targets := make([]*model.Product, len(products))
+	for i := range products {
+		targets[i] = &products[i]
+	}
+	return s.present(targets, code)
+}
+
+type listKey struct {
+	productID uint
+	variantID uint
+}
+
+// present ürün ve varyantların DisplayPrice alanını doldurur; liste fiyatı
+// kurla çevrilmiş fiyata göre önceliklidir
+func (s *pricingService) present(products []*model.Product, code string) error {
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	entries, err := s.repo.GetForProducts(code, ids)
+	if err != nil {
+		return err
+	}
+	listed := make(map[listKey]float64, len(entries))
+	for _, entry := range entries {
+		listed[listKey{entry.ProductID, entry.VariantID}] = entry.Price
+	}
+
+	for _, product := range products {
+		money, err := s.money(product.Price, product.Currency, code, listed, listKey{product.ID, 0})
+		if err != nil {
+			return err
+		}
+		product.DisplayPrice = money
+
+		for i := range product.Variants {
+			variant := &product.Variants[i]
+			money, err := s.money(variant.Price, product.Currency, code, listed, listKey{product.ID, variant.ID})
+			if err != nil {
+				return err
+			}
+			variant.DisplayPrice = money
+		}
+	}
+	return nil
+}
+
+func (s *pricingService) money(amount float64, base, code string, listed map[listKey]float64, key listKey) (*model.Money, error) {
+	if price, ok := listed[key]; ok {
+		return &model.Money{Currency: code, Amount: price, Source: model.PriceSourcePriceList}, nil
+	}
+	if base == code {
+		return &model.Money{Currency: code, Amount: s.currencies.Round(amount, code), Source: model.PriceSourceBase}, nil
+	}
+
+	rate, err := s.currencies.Rate(base, code)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := s.currencies.Convert(amount, base, code)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Money{Currency: code, Amount: converted, Source: model.PriceSourceExchangeRate, Rate: currency.Round(rate, 6)}, nil
+}
+
+func (s *pricingService) GetPriceList(code string) ([]model.PriceListEntry, error) {
+	code = currency.Normalize(code)
+	if !s.currencies.Supports(code) {
+		return nil, fmt.Errorf("%w: currency %q is not supported", ErrInvalidListPrice, code)
+	}
+	return s.repo.GetByCurrency(code)
+}
+
+func (s *pricingService) GetProductPriceLists(productID uint) ([]model.PriceListEntry, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, notFound(err, ErrProductNotFound)
+	}
+	return s.repo.GetByProduct(productID)
+}
+
+// SetListPrice ürünün (ya da varyantın) bir para birimindeki sabit fiyatını
+// ekler veya günceller. Ürünün kendi para birimi için liste fiyatı
+// tutulmaz; o fiyat ürünün üzerindedir.
+func (s *pricingService) SetListPrice(entry *model.PriceListEntry, actor model.Actor) error {
+	entry.Currency = currency.Normalize(entry.Currency)
+	if !s.currencies.Supports(entry.Currency) {
+		return fmt.Errorf("%w: currency %q is not supported", ErrInvalidListPrice, entry.Currency)
+	}
+	if math.IsNaN(entry.Price) || math.IsInf(entry.Price, 0) || entry.Price < 0 {
+		return fmt.Errorf("%w: price must be a non-negative number", ErrInvalidListPrice)
+	}
+	if places := s.currencies.Decimals(entry.Currency); currency.Round(entry.Price, places) != entry.Price {
+		return fmt.Errorf("%w: %s prices have at most %d decimal places", ErrInvalidListPrice, entry.Currency, places)
+	}
+
+	product, err := s.productRepo.GetByID(entry.ProductID)
+	if err != nil {
+		return notFound(err, ErrProductNotFound)
+	}
+	if product.Currency == entry.Currency {
+		return fmt.Errorf("%w: %s is the product's base currency, update the product price instead", ErrInvalidListPrice, entry.Currency)
+	}
+	if entry.VariantID != 0 {
+		variant, err := s.variantRepo.GetByID(entry.VariantID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && variant.ProductID != entry.ProductID) {
+			return ErrVariantNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Upsert(entry, actor); err != nil {
+		return err
+	}
+	s.publish(entry.ProductID)
+	return nil
+}
+
+func (s *pricingService) DeleteListPrice(code string, productID, variantID uint, actor model.Actor) error {
+	deleted, err := s.repo.Delete(currency.Normalize(code), productID, variantID, actor)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrListPriceNotFound
+	}
+
+	s.publish(productID)
+	return nil
+}
+
+// publish liste fiyatı değişikliğini ürün güncellemesi olarak yayınlar;
+// basket servisi cache'teki ürünü düşürür
+func (s *pricingService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductUpdated, ProductID: productID})
+}
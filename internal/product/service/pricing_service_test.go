@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+)
+
+func TestListPriceTakesPrecedenceOverConvertedPrice(t *testing.T) {
+	databasetest.Open(t)
+	db := database.ForTenant("acme")
+	// Gömülü tablo: 1 EUR = 36.25 TRY = 1.0842 USD
+	currencies, err := currency.NewConverter(context.Background(), currency.NewSource(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	productRepo := repository.NewProductRepository(db)
+	variantRepo := repository.NewVariantRepository(db)
+	variants := NewVariantService(variantRepo, productRepo, nil)
+	svc := NewPricingService(repository.NewPriceListRepository(db), productRepo, variantRepo, currencies, nil)
+	actor := model.Actor{Name: "test"}
+
+	product := &model.Product{Name: "Lamp", Price: 1999.9, Currency: "TRY"}
+	if err := productRepo.Create(product, actor); err != nil {
+		t.Fatal(err)
+	}
+	if err := variants.ReplaceOptions(product.ID, []model.ProductOption{{Name: "color", Values: []string{"red"}}}, actor); err != nil {
+		t.Fatal(err)
+	}
+	red := &model.ProductVariant{ProductID: product.ID, SKU: "LAMP-RED", Attributes: map[string]string{"color": "red"}, Price: 2100}
+	if err := variants.CreateVariant(red, actor); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.SetListPrice(&model.PriceListEntry{Currency: "eur", ProductID: product.ID, Price: 49.9}, actor); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetListPrice(&model.PriceListEntry{Currency: "USD", ProductID: product.ID, VariantID: red.ID, Price: 59}, actor); err != nil {
+		t.Fatal(err)
+	}
+
+	present := func(code string) *model.Product {
+		t.Helper()
+		stored, err := productRepo.GetByID(product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stored.Variants) != 1 {
+			t.Fatalf("variants = %+v", stored.Variants)
+		}
+		if err := svc.Present(stored, code); err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+	assertMoney := func(name string, got *model.Money, want model.Money) {
+		t.Helper()
+		if got == nil || *got != want {
+			t.Fatalf("%s display price = %+v, want %+v", name, got, want)
+		}
+	}
+
+	// Ürünün EUR liste fiyatı var, varyantın yok: varyant kurla çevrilir
+	eur := present("EUR")
+	assertMoney("product EUR", eur.DisplayPrice, model.Money{Currency: "EUR", Amount: 49.9, Source: model.PriceSourcePriceList})
+	assertMoney("variant EUR", eur.Variants[0].DisplayPrice, model.Money{Currency: "EUR", Amount: 57.93, Source: model.PriceSourceExchangeRate, Rate: 0.027586})
+
+	// Varyantın liste fiyatı ürünün çevrilmiş fiyatından bağımsızdır
+	usd := present("USD")
+	assertMoney("product USD", usd.DisplayPrice, model.Money{Currency: "USD", Amount: 59.81, Source: model.PriceSourceExchangeRate, Rate: 0.029909})
+	assertMoney("variant USD", usd.Variants[0].DisplayPrice, model.Money{Currency: "USD", Amount: 59, Source: model.PriceSourcePriceList})
+
+	base := present("TRY")
+	assertMoney("product TRY", base.DisplayPrice, model.Money{Currency: "TRY", Amount: 1999.9, Source: model.PriceSourceBase})
+
+	// Liste fiyatı silinince kur yeniden kullanılır
+	if err := svc.DeleteListPrice("EUR", product.ID, 0, actor); err != nil {
+		t.Fatal(err)
+	}
+	assertMoney("product EUR after delete", present("EUR").DisplayPrice, model.Money{Currency: "EUR", Amount: 55.17, Source: model.PriceSourceExchangeRate, Rate: 0.027586})
+
+	for name, entry := range map[string]*model.PriceListEntry{
+		"base currency":   {Currency: "TRY", ProductID: product.ID, Price: 10},
+		"too many places": {Currency: "JPY", ProductID: product.ID, Price: 10.5},
+		"unsupported":     {Currency: "XXX", ProductID: product.ID, Price: 10},
+	} {
+		if err := svc.SetListPrice(entry, actor); !errors.Is(err, ErrInvalidListPrice) {
+			t.Fatalf("%s: err = %v, want ErrInvalidListPrice", name, err)
+		}
+	}
+}
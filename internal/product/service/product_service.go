@@ -1,47 +1,219 @@
 package service
 
 import (
+	"errors"
+	"time"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/product/events"
 	"cluster-iac/internal/product/model"
 	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
 )
 
 type ProductService interface {
-	CreateProduct(product *model.Product) error
+	CreateProduct(product *model.Product, actor model.Actor) error
 	GetProductByID(id uint) (*model.Product, error)
 	GetAllProducts() ([]model.Product, error)
-	UpdateProduct(product *model.Product) error
-	DeleteProduct(id uint) error
-	GetProductsByCategory(category string) ([]model.Product, error)
+	UpdateProduct(product *model.Product, expectedVersion uint, actor model.Actor) error
+	DeleteProduct(id uint, expectedVersion uint, actor model.Actor) error
+	GetProductsByCategory(slug string, includeDescendants bool) ([]model.Product, error)
+	GetProductWithDeleted(id uint) (*model.Product, error)
+	GetTrash() ([]model.Product, error)
+	RestoreProduct(id uint, actor model.Actor) (*model.Product, error)
+	PurgeProduct(id uint, actor model.Actor) error
+	PurgeExpired(retention time.Duration, actor model.Actor) (int, error)
+	ExportProducts(filter model.ProductExportFilter, fn func(row *model.ProductExportRow) error) error
 }
 
 type productService struct {
-	repo repository.ProductRepository
+	repo         repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	currencies   *currency.Converter
+	publisher    events.Publisher
 }
 
-func NewProductService(repo repository.ProductRepository) ProductService {
-	return &productService{repo: repo}
+func NewProductService(repo repository.ProductRepository, categoryRepo repository.CategoryRepository, currencies *currency.Converter, publisher events.Publisher) ProductService {
+	return &productService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		currencies:   currencies,
+		publisher:    publisher,
+	}
 }
 
-func (s *productService) CreateProduct(product *model.Product) error {
-	return s.repo.Create(product)
+func (s *productService) CreateProduct(product *model.Product, actor model.Actor) error {
+	if err := s.validate(product); err != nil {
+		return err
+	}
+	if err := s.repo.Create(product, actor); err != nil {
+		return err
+	}
+
+	s.publish(events.ProductCreated, product.ID)
+	return nil
 }
 
 func (s *productService) GetProductByID(id uint) (*model.Product, error) {
-	return s.repo.GetByID(id)
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrProductNotFound)
+	}
+	return product, nil
 }
 
 func (s *productService) GetAllProducts() ([]model.Product, error) {
 	return s.repo.GetAll()
 }
 
-func (s *productService) UpdateProduct(product *model.Product) error {
-	return s.repo.Update(product)
+func (s *productService) UpdateProduct(product *model.Product, expectedVersion uint, actor model.Actor) error {
+	// Para birimi gönderilmezse varsayılana değil kayıtlı değere düşer;
+	// aksi halde USD fiyatlı bir ürün sessizce TRY olur
+	if currency.Normalize(product.Currency) == "" {
+		current, err := s.repo.GetByID(product.ID)
+		if err != nil {
+			return notFound(err, ErrProductNotFound)
+		}
+		product.Currency = current.Currency
+	}
+	if err := s.validate(product); err != nil {
+		return err
+	}
+	if err := s.repo.Update(product, expectedVersion, actor); err != nil {
+		return notFound(err, ErrProductNotFound)
+	}
+
+	s.publish(events.ProductUpdated, product.ID)
+	return nil
+}
+
+func (s *productService) DeleteProduct(id uint, expectedVersion uint, actor model.Actor) error {
+	if err := s.repo.Delete(id, expectedVersion, actor); err != nil {
+		return notFound(err, ErrProductNotFound)
+	}
+
+	s.publish(events.ProductDeleted, id)
+	return nil
+}
+
+// ExportProducts filtreye uyan ürünleri id sırasıyla fn'e akıtır. Kategori
+// bulunamazsa fn hiç çağrılmadan ErrCategoryNotFound döner.
+func (s *productService) ExportProducts(filter model.ProductExportFilter, fn func(row *model.ProductExportRow) error) error {
+	var categoryIDs []uint
+	if filter.CategorySlug != "" {
+		ids, err := s.categoryIDs(filter.CategorySlug, filter.IncludeDescendants)
+		if err != nil {
+			return err
+		}
+		categoryIDs = ids
+	}
+
+	return s.repo.StreamExport(categoryIDs, filter.UpdatedSince, fn)
 }
 
-func (s *productService) DeleteProduct(id uint) error {
-	return s.repo.Delete(id)
+// GetProductsByCategory slug ile bulunan kategorinin ürünlerini, istenirse
+// tüm alt kategorilerininkilerle birlikte döndürür
+func (s *productService) GetProductsByCategory(slug string, includeDescendants bool) ([]model.Product, error) {
+	categoryIDs, err := s.categoryIDs(slug, includeDescendants)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByCategoryIDs(categoryIDs)
+}
+
+func (s *productService) GetProductWithDeleted(id uint) (*model.Product, error) {
+	product, err := s.repo.GetByIDWithDeleted(id)
+	if err != nil {
+		return nil, notFound(err, ErrProductNotFound)
+	}
+	return product, nil
+}
+
+func (s *productService) GetTrash() ([]model.Product, error) {
+	return s.repo.GetDeleted()
+}
+
+func (s *productService) RestoreProduct(id uint, actor model.Actor) (*model.Product, error) {
+	product, err := s.repo.Restore(id, actor)
+	if err != nil {
+		return nil, notFound(err, ErrProductNotFound)
+	}
+
+	s.publish(events.ProductRestored, id)
+	return product, nil
+}
+
+func (s *productService) PurgeProduct(id uint, actor model.Actor) error {
+	if err := s.repo.Purge(id, actor); err != nil {
+		return notFound(err, ErrProductNotFound)
+	}
+
+	s.publish(events.ProductPurged, id)
+	return nil
+}
+
+// PurgeExpired retention süresinden daha önce silinmiş ürünleri kalıcı olarak siler
+func (s *productService) PurgeExpired(retention time.Duration, actor model.Actor) (int, error) {
+	ids, err := s.repo.PurgeDeletedBefore(time.Now().Add(-retention), actor)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		s.publish(events.ProductPurged, id)
+	}
+	return len(ids), nil
+}
+
+// categoryIDs slug'ın kategori id'sini, istenirse alt kategorileriyle döndürür
+func (s *productService) categoryIDs(slug string, includeDescendants bool) ([]uint, error) {
+	category, err := s.categoryRepo.GetBySlug(model.Slugify(slug))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !includeDescendants {
+		return []uint{category.ID}, nil
+	}
+	return s.categoryRepo.GetDescendantIDs(category.ID)
+}
+
+// validate alan kurallarını, external_id tekilliğini ve kategorinin varlığını
+// kontrol eder; alan kuralları diğer kontrollerden önce ve topluca raporlanır
+func (s *productService) validate(product *model.Product) error {
+	if err := validateProduct(product, s.currencies); err != nil {
+		return err
+	}
+
+	if product.ExternalID != nil {
+		existing, err := s.repo.GetByExternalID(*product.ExternalID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil && existing.ID != product.ID {
+			return ErrExternalIDTaken
+		}
+	}
+
+	return s.checkCategory(product.CategoryID)
+}
+
+func (s *productService) checkCategory(categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	_, err := s.categoryRepo.GetByID(*categoryID)
+	return notFound(err, ErrUnknownCategory)
 }
 
-func (s *productService) GetProductsByCategory(category string) ([]model.Product, error) {
-	return s.repo.GetByCategory(category)
+func (s *productService) publish(eventType events.EventType, productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: eventType, ProductID: productID})
 }
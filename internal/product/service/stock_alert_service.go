@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"cluster-iac/internal/product/alerts"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type StockAlertService interface {
+	Evaluate(ctx context.Context, productID uint) error
+	EvaluateAll(ctx context.Context) error
+}
+
+type stockAlertService struct {
+	repo      repository.StockAlertRepository
+	notifier  alerts.Notifier
+	publisher events.Publisher
+
+	// Event akışı ve periyodik tarama aynı ürünü eşzamanlı değerlendirmesin
+	mu sync.Mutex
+}
+
+func NewStockAlertService(repo repository.StockAlertRepository, notifier alerts.Notifier, publisher events.Publisher) StockAlertService {
+	return &stockAlertService{
+		repo:      repo,
+		notifier:  notifier,
+		publisher: publisher,
+	}
+}
+
+func (s *stockAlertService) Evaluate(ctx context.Context, productID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, err := s.repo.GetProductStock(productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Silinen ürünün durumu tutulmaz; geri yüklenirse yeniden değerlendirilir
+		return s.repo.DeleteState(productID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.evaluate(ctx, product)
+}
+
+func (s *stockAlertService) EvaluateAll(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	products, err := s.repo.GetAllProductStock()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range products {
+		if err := s.evaluate(ctx, &products[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evaluate seviye son bildirilenden farklıysa uyarı gönderir ve durumu kaydeder.
+// Bildirim başarısız olursa durum güncellenmez; böylece bir sonraki
+// değerlendirmede tekrar denenir. Durumu olmayan ürün hiç stoklu
+// görülmemiştir; stoğu yoksa (ör. yeni oluşturulmuşsa) OutOfStock
+// gönderilmeden durumu kaydedilir.
+func (s *stockAlertService) evaluate(ctx context.Context, product *model.Product) error {
+	level := stockAlertLevel(product.Stock, product.ReorderThreshold)
+
+	state, err := s.repo.GetState(product.ID)
+	if err != nil {
+		return err
+	}
+	if state != nil && state.Level == level {
+		return nil
+	}
+	neverStocked := state == nil && level == model.StockAlertOutOfStock
+
+	if level != model.StockAlertOK && !neverStocked {
+		eventType := events.LowStock
+		if level == model.StockAlertOutOfStock {
+			eventType = events.OutOfStock
+		}
+
+		now := time.Now()
+		alert := alerts.Alert{
+			Type:        eventType,
+			Tenant:      product.TenantID,
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Stock:       product.Stock,
+			Threshold:   product.ReorderThreshold,
+			OccurredAt:  now,
+		}
+		if err := s.notifier.Notify(ctx, alert); err != nil {
+			return err
+		}
+		if s.publisher != nil {
+			s.publisher.Publish(events.Event{Type: eventType, ProductID: product.ID, OccurredAt: now})
+		}
+	}
+
+	return s.repo.SaveState(&model.StockAlertState{
+		ProductID: product.ID,
+		Level:     level,
+		ChangedAt: time.Now(),
+	})
+}
+
+func stockAlertLevel(stock, threshold int) model.StockAlertLevel {
+	switch {
+	case stock <= 0:
+		return model.StockAlertOutOfStock
+	case stock <= threshold:
+		return model.StockAlertLow
+	default:
+		return model.StockAlertOK
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/product/alerts"
+	"cluster-iac/internal/product/alerts/alertstest"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"gorm.io/gorm"
+)
+
+type fakeStockAlertRepo struct {
+	products map[uint]*model.Product
+	states   map[uint]model.StockAlertState
+}
+
+func newFakeStockAlertRepo(products ...model.Product) *fakeStockAlertRepo {
+	repo := &fakeStockAlertRepo{products: map[uint]*model.Product{}, states: map[uint]model.StockAlertState{}}
+	for i := range products {
+		repo.products[products[i].ID] = &products[i]
+	}
+	return repo
+}
+
+func (r *fakeStockAlertRepo) GetProductStock(productID uint) (*model.Product, error) {
+	product, ok := r.products[productID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *fakeStockAlertRepo) GetAllProductStock() ([]model.Product, error) {
+	products := make([]model.Product, 0, len(r.products))
+	for _, product := range r.products {
+		products = append(products, *product)
+	}
+	return products, nil
+}
+
+func (r *fakeStockAlertRepo) GetState(productID uint) (*model.StockAlertState, error) {
+	state, ok := r.states[productID]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (r *fakeStockAlertRepo) SaveState(state *model.StockAlertState) error {
+	r.states[state.ProductID] = *state
+	return nil
+}
+
+func (r *fakeStockAlertRepo) DeleteState(productID uint) error {
+	delete(r.states, productID)
+	return nil
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func newStockAlertFixture(t *testing.T, product model.Product) (*fakeStockAlertRepo, *alertstest.WebhookStub, *recordingPublisher, StockAlertService) {
+	t.Helper()
+	repo := newFakeStockAlertRepo(product)
+	stub := alertstest.NewWebhookStub()
+	t.Cleanup(stub.Close)
+	publisher := &recordingPublisher{}
+	svc := NewStockAlertService(repo, alerts.NewWebhookNotifier(stub.URL(), time.Second), publisher)
+	return repo, stub, publisher, svc
+}
+
+// setStock stoğu değiştirip ürünü değerlendirir
+func setStock(t *testing.T, repo *fakeStockAlertRepo, svc StockAlertService, productID uint, stock int) {
+	t.Helper()
+	repo.products[productID].Stock = stock
+	if err := svc.Evaluate(context.Background(), productID); err != nil {
+		t.Fatalf("Evaluate with stock %d: %v", stock, err)
+	}
+}
+
+func alertTypes(list []alerts.Alert) []events.EventType {
+	types := make([]events.EventType, len(list))
+	for i, alert := range list {
+		types[i] = alert.Type
+	}
+	return types
+}
+
+func assertAlertTypes(t *testing.T, got []alerts.Alert, want ...events.EventType) {
+	t.Helper()
+	types := alertTypes(got)
+	if len(types) != len(want) {
+		t.Fatalf("alerts = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("alerts = %v, want %v", types, want)
+		}
+	}
+}
+
+func TestStockAlertCrossingThresholdsEmitsOnce(t *testing.T) {
+	repo, stub, publisher, svc := newStockAlertFixture(t, model.Product{ID: 1, Name: "Mug", Stock: 10, ReorderThreshold: 5})
+
+	setStock(t, repo, svc, 1, 10)
+	assertAlertTypes(t, stub.Alerts())
+
+	setStock(t, repo, svc, 1, 5)
+	assertAlertTypes(t, stub.Alerts(), events.LowStock)
+
+	setStock(t, repo, svc, 1, 0)
+	assertAlertTypes(t, stub.Alerts(), events.LowStock, events.OutOfStock)
+
+	got := stub.Alerts()
+	if got[0].Stock != 5 || got[0].Threshold != 5 || got[0].ProductID != 1 {
+		t.Fatalf("low stock alert = %+v", got[0])
+	}
+	if len(publisher.events) != 2 || publisher.events[0].Type != events.LowStock || publisher.events[1].Type != events.OutOfStock {
+		t.Fatalf("published events = %+v", publisher.events)
+	}
+}
+
+func TestStockAlertNotResentWhileBelowThreshold(t *testing.T) {
+	repo, stub, _, svc := newStockAlertFixture(t, model.Product{ID: 1, Name: "Mug", Stock: 10, ReorderThreshold: 5})
+
+	for _, stock := range []int{4, 3, 2, 1} {
+		setStock(t, repo, svc, 1, stock)
+	}
+	if err := svc.EvaluateAll(context.Background()); err != nil {
+		t.Fatalf("EvaluateAll: %v", err)
+	}
+	assertAlertTypes(t, stub.Alerts(), events.LowStock)
+
+	for _, stock := range []int{0, -1, 0} {
+		setStock(t, repo, svc, 1, stock)
+	}
+	assertAlertTypes(t, stub.Alerts(), events.LowStock, events.OutOfStock)
+}
+
+func TestStockAlertRearmsAfterRestock(t *testing.T) {
+	repo, stub, _, svc := newStockAlertFixture(t, model.Product{ID: 1, Name: "Mug", Stock: 10, ReorderThreshold: 5})
+
+	setStock(t, repo, svc, 1, 10)
+	setStock(t, repo, svc, 1, 0)
+	assertAlertTypes(t, stub.Alerts(), events.OutOfStock)
+
+	// Yeniden stoklanınca uyarı gönderilmez ama durum sıfırlanır
+	setStock(t, repo, svc, 1, 20)
+	assertAlertTypes(t, stub.Alerts(), events.OutOfStock)
+	if state := repo.states[1]; state.Level != model.StockAlertOK {
+		t.Fatalf("state after restock = %q, want %q", state.Level, model.StockAlertOK)
+	}
+
+	setStock(t, repo, svc, 1, 3)
+	setStock(t, repo, svc, 1, 0)
+	assertAlertTypes(t, stub.Alerts(), events.OutOfStock, events.LowStock, events.OutOfStock)
+}
+
+func TestStockAlertRetriedWhenWebhookFails(t *testing.T) {
+	repo, stub, _, svc := newStockAlertFixture(t, model.Product{ID: 1, Name: "Mug", Stock: 10, ReorderThreshold: 5})
+
+	stub.SetStatus(http.StatusInternalServerError)
+	repo.products[1].Stock = 2
+	if err := svc.Evaluate(context.Background(), 1); err == nil {
+		t.Fatal("Evaluate succeeded although the webhook failed")
+	}
+	if _, ok := repo.states[1]; ok {
+		t.Fatal("state was saved although the alert was not delivered")
+	}
+
+	stub.SetStatus(http.StatusNoContent)
+	setStock(t, repo, svc, 1, 2)
+	assertAlertTypes(t, stub.Alerts(), events.LowStock)
+}
+
+func TestStockAlertSkipsProductsThatNeverHadStock(t *testing.T) {
+	repo, stub, publisher, svc := newStockAlertFixture(t, model.Product{ID: 1, Name: "New mug", Stock: 0, ReorderThreshold: 5})
+
+	// Yeni ürünün stoğu 0'dır; ne oluşturulunca ne de taramada uyarı gider
+	setStock(t, repo, svc, 1, 0)
+	if err := svc.EvaluateAll(context.Background()); err != nil {
+		t.Fatalf("EvaluateAll: %v", err)
+	}
+	assertAlertTypes(t, stub.Alerts())
+	if len(publisher.events) != 0 {
+		t.Fatalf("published events = %+v", publisher.events)
+	}
+
+	// İlk stok girişinden sonra uyarılar normal çalışır
+	setStock(t, repo, svc, 1, 20)
+	setStock(t, repo, svc, 1, 0)
+	assertAlertTypes(t, stub.Alerts(), events.OutOfStock)
+}
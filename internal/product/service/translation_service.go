@@ -0,0 +1,466 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"cluster-iac/internal/locale"
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+)
+
+const (
+	defaultMissingLimit = 100
+	maxMissingLimit     = 1000
+)
+
+// TranslationService ürün ve kategori metinlerini istenen dile çevirir ve
+// çevirileri yönetir. Çevirisi olmayan metinler fallback zincirindeki bir
+// sonraki dile, en sonunda katalog varsayılan diline düşer.
+type TranslationService interface {
+	Resolve(query, header string) (locale.Chain, error)
+	Localize(product *model.Product, chain locale.Chain) error
+	LocalizeAll(products []model.Product, chain locale.Chain) error
+	LocalizeCategories(categories []model.Category, chain locale.Chain) error
+	LocalizeTree(nodes []*model.CategoryTreeNode, chain locale.Chain) error
+	GetProductTranslations(productID uint) (*model.ProductTranslations, error)
+	SetProductTranslation(translation *model.ProductTranslation, actor model.Actor) error
+	DeleteProductTranslation(productID uint, tag string, actor model.Actor) error
+	GetCategoryTranslations(categoryID uint) (*model.CategoryTranslations, error)
+	SetCategoryTranslation(translation *model.CategoryTranslation) error
+	DeleteCategoryTranslation(categoryID uint, tag string) error
+	Completeness(tag string) (*model.TranslationReport, error)
+	Missing(tag, entity string, limit int) ([]model.MissingTranslation, error)
+}
+
+type translationService struct {
+	repo         repository.TranslationRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	locales      *locale.Resolver
+	publisher    events.Publisher
+}
+
+func NewTranslationService(repo repository.TranslationRepository, productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, locales *locale.Resolver, publisher events.Publisher) TranslationService {
+	return &translationService{
+		repo:         repo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		locales:      locales,
+		publisher:    publisher,
+	}
+}
+
+func (s *translationService) Resolve(query, header string) (locale.Chain, error) {
+	return s.locales.Resolve(query, header)
+}
+
+// translated zincirdeki varsayılan dil dışındaki dillerdir; çeviriler
+// yalnızca bunlar için tutulur
+func (s *translationService) translated(chain locale.Chain) []string {
+	locales := make([]string, 0, len(chain))
+	for _, tag := range chain {
+		if tag != s.locales.Default() {
+			locales = append(locales, tag)
+		}
+	}
+	return locales
+}
+
+func (s *translationService) Localize(product *model.Product, chain locale.Chain) error {
+	return s.localize([]*model.Product{product}, chain)
+}
+
+func (s *translationService) LocalizeAll(products []model.Product, chain locale.Chain) error {
+	ptrs := make([]*model.Product, len(products))
+	for i := range products {
+		ptrs[i] = &products[i]
+	}
+	return s.localize(ptrs, chain)
+}
+
+func (s *translationService) localize(products []*model.Product, chain locale.Chain) error {
+	var categories []*model.Category
+	for _, product := range products {
+		if product.Category != nil {
+			categories = append(categories, product.Category)
+		}
+	}
+	if err := s.localizeCategories(categories, chain); err != nil {
+		return err
+	}
+
+	locales := s.translated(chain)
+	if len(locales) == 0 {
+		for _, product := range products {
+			product.Locale = s.locales.Default()
+		}
+		return nil
+	}
+
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	translations, err := s.repo.GetProductTranslationsFor(ids, locales)
+	if err != nil {
+		return err
+	}
+	byProduct := make(map[uint]map[string]model.ProductTranslation, len(products))
+	for _, t := range translations {
+		if byProduct[t.ProductID] == nil {
+			byProduct[t.ProductID] = make(map[string]model.ProductTranslation)
+		}
+		byProduct[t.ProductID][t.Locale] = t
+	}
+
+	for _, product := range products {
+		found := byProduct[product.ID]
+
+		// Ad ve açıklama zincirde ayrı ayrı aranır; çeviride boş bırakılan
+		// açıklama bir sonraki dile düşer
+		product.Locale = s.locales.Default()
+		for _, tag := range chain {
+			if t, ok := found[tag]; ok {
+				product.Name = t.Name
+				product.Locale = tag
+				break
+			}
+			if tag == s.locales.Default() {
+				break
+			}
+		}
+		for _, tag := range chain {
+			if t, ok := found[tag]; ok && t.Description != "" {
+				product.Description = t.Description
+				break
+			}
+			if tag == s.locales.Default() {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *translationService) LocalizeCategories(categories []model.Category, chain locale.Chain) error {
+	ptrs := make([]*model.Category, len(categories))
+	for i := range categories {
+		ptrs[i] = &categories[i]
+	}
+	return s.localizeCategories(ptrs, chain)
+}
+
+func (s *translationService) localizeCategories(categories []*model.Category, chain locale.Chain) error {
+	ids := make([]uint, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	names, err := s.categoryNames(ids, chain)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		category.Locale = s.locales.Default()
+		if name, ok := names[category.ID]; ok {
+			category.Name = name.name
+			category.Locale = name.locale
+		}
+	}
+	return nil
+}
+
+func (s *translationService) LocalizeTree(nodes []*model.CategoryTreeNode, chain locale.Chain) error {
+	var all []*model.CategoryTreeNode
+	var walk func(nodes []*model.CategoryTreeNode)
+	walk = func(nodes []*model.CategoryTreeNode) {
+		for _, node := range nodes {
+			all = append(all, node)
+			walk(node.Children)
+		}
+	}
+	walk(nodes)
+
+	ids := make([]uint, len(all))
+	for i, node := range all {
+		ids[i] = node.ID
+	}
+	names, err := s.categoryNames(ids, chain)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range all {
+		node.Locale = s.locales.Default()
+		if name, ok := names[node.ID]; ok {
+			node.Name = name.name
+			node.Locale = name.locale
+		}
+	}
+	return nil
+}
+
+type localizedName struct {
+	name   string
+	locale string
+}
+
+// categoryNames kategorilerin zincirdeki ilk çevirisini döndürür; çevirisi
+// olmayan kategoriler sonuçta yer almaz
+func (s *translationService) categoryNames(ids []uint, chain locale.Chain) (map[uint]localizedName, error) {
+	names := make(map[uint]localizedName)
+	locales := s.translated(chain)
+	if len(ids) == 0 || len(locales) == 0 {
+		return names, nil
+	}
+
+	translations, err := s.repo.GetCategoryTranslationsFor(ids, locales)
+	if err != nil {
+		return nil, err
+	}
+	byCategory := make(map[uint]map[string]string, len(ids))
+	for _, t := range translations {
+		if byCategory[t.CategoryID] == nil {
+			byCategory[t.CategoryID] = make(map[string]string)
+		}
+		byCategory[t.CategoryID][t.Locale] = t.Name
+	}
+
+	for id, found := range byCategory {
+		for _, tag := range chain {
+			if tag == s.locales.Default() {
+				break
+			}
+			if name, ok := found[tag]; ok {
+				names[id] = localizedName{name: name, locale: tag}
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// missing çevirisi olmayan desteklenen dilleri desteklenme sırasıyla döndürür
+func (s *translationService) missing(present map[string]bool) []string {
+	missing := []string{}
+	for _, tag := range s.locales.Supported() {
+		if tag != s.locales.Default() && !present[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
+
+// checkLocale çeviri yazılabilecek bir dil olduğunu doğrular; varsayılan
+// dildeki metin ürünün (kategorinin) kendisindedir
+func (s *translationService) checkLocale(tag string) (string, error) {
+	normalized, err := s.locales.Check(tag)
+	if err != nil {
+		return "", fmt.Errorf("%w: locale %q is not supported", ErrInvalidTranslation, tag)
+	}
+	if normalized == s.locales.Default() {
+		return "", fmt.Errorf("%w: %s is the catalog default locale, update the text on the record instead", ErrInvalidTranslation, normalized)
+	}
+	return normalized, nil
+}
+
+func (s *translationService) GetProductTranslations(productID uint) (*model.ProductTranslations, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, notFound(err, ErrProductNotFound)
+	}
+
+	translations, err := s.repo.GetProductTranslations(productID)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(translations))
+	for _, t := range translations {
+		present[t.Locale] = true
+	}
+
+	return &model.ProductTranslations{
+		ProductID:     productID,
+		DefaultLocale: s.locales.Default(),
+		Translations:  translations,
+		Missing:       s.missing(present),
+	}, nil
+}
+
+// SetProductTranslation ürünün bir dildeki ad ve açıklamasını ekler veya
+// değiştirir; ürün adıyla aynı kurallar uygulanır
+func (s *translationService) SetProductTranslation(translation *model.ProductTranslation, actor model.Actor) error {
+	tag, err := s.checkLocale(translation.Locale)
+	if err != nil {
+		return err
+	}
+	translation.Locale = tag
+
+	var v fieldErrors
+	translation.Name = strings.TrimSpace(translation.Name)
+	if code, message := nameRule(translation.Name); code != "" {
+		v.add("name", code, message)
+	}
+	if utf8.RuneCountInString(translation.Description) > maxProductDescriptionLength {
+		v.add("description", "too_long", fmt.Sprintf("must be at most %d characters", maxProductDescriptionLength))
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	if _, err := s.productRepo.GetByID(translation.ProductID); err != nil {
+		return notFound(err, ErrProductNotFound)
+	}
+	if err := s.repo.UpsertProductTranslation(translation, actor); err != nil {
+		return err
+	}
+	s.publish(translation.ProductID)
+	return nil
+}
+
+func (s *translationService) DeleteProductTranslation(productID uint, tag string, actor model.Actor) error {
+	deleted, err := s.repo.DeleteProductTranslation(productID, locale.Normalize(tag), actor)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+
+	s.publish(productID)
+	return nil
+}
+
+func (s *translationService) GetCategoryTranslations(categoryID uint) (*model.CategoryTranslations, error) {
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return nil, notFound(err, ErrCategoryNotFound)
+	}
+
+	translations, err := s.repo.GetCategoryTranslations(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(translations))
+	for _, t := range translations {
+		present[t.Locale] = true
+	}
+
+	return &model.CategoryTranslations{
+		CategoryID:    categoryID,
+		DefaultLocale: s.locales.Default(),
+		Translations:  translations,
+		Missing:       s.missing(present),
+	}, nil
+}
+
+func (s *translationService) SetCategoryTranslation(translation *model.CategoryTranslation) error {
+	tag, err := s.checkLocale(translation.Locale)
+	if err != nil {
+		return err
+	}
+	translation.Locale = tag
+
+	var v fieldErrors
+	translation.Name = strings.TrimSpace(translation.Name)
+	if code, message := nameRule(translation.Name); code != "" {
+		v.add("name", code, message)
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	if _, err := s.categoryRepo.GetByID(translation.CategoryID); err != nil {
+		return notFound(err, ErrCategoryNotFound)
+	}
+	return s.repo.UpsertCategoryTranslation(translation)
+}
+
+func (s *translationService) DeleteCategoryTranslation(categoryID uint, tag string) error {
+	deleted, err := s.repo.DeleteCategoryTranslation(categoryID, locale.Normalize(tag))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// Completeness desteklenen her dil (tag verilmişse yalnızca o dil) için
+// çevrilmiş ürün ve kategori oranlarını raporlar
+func (s *translationService) Completeness(tag string) (*model.TranslationReport, error) {
+	locales := s.translated(s.locales.Supported())
+	if tag != "" {
+		normalized, err := s.checkLocale(tag)
+		if err != nil {
+			return nil, err
+		}
+		locales = []string{normalized}
+	}
+
+	report := &model.TranslationReport{
+		DefaultLocale: s.locales.Default(),
+		Locales:       make([]model.LocaleCompleteness, 0, len(locales)),
+	}
+	for _, tag := range locales {
+		products, err := s.repo.ProductCoverage(tag)
+		if err != nil {
+			return nil, err
+		}
+		categories, err := s.repo.CategoryCoverage(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Locales = append(report.Locales, model.LocaleCompleteness{
+			Locale:     tag,
+			Products:   withPercent(products),
+			Categories: withPercent(categories),
+		})
+	}
+	return report, nil
+}
+
+// withPercent kaydı olmayan katalog tam çevrilmiş sayılır
+func withPercent(coverage model.TranslationCoverage) model.TranslationCoverage {
+	coverage.Missing = coverage.Total - coverage.Translated
+	coverage.Percent = 100
+	if coverage.Total > 0 {
+		coverage.Percent = math.Round(float64(coverage.Translated)/float64(coverage.Total)*1000) / 10
+	}
+	return coverage
+}
+
+// Missing tag dilinde çevirisi olmayan ürünleri ya da kategorileri listeler
+func (s *translationService) Missing(tag, entity string, limit int) ([]model.MissingTranslation, error) {
+	normalized, err := s.checkLocale(tag)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMissingLimit
+	}
+	if limit > maxMissingLimit {
+		limit = maxMissingLimit
+	}
+
+	switch entity {
+	case "", "product":
+		return s.repo.MissingProducts(normalized, limit)
+	case "category":
+		return s.repo.MissingCategories(normalized, limit)
+	}
+	return nil, fmt.Errorf("%w: entity must be product or category", ErrInvalidTranslation)
+}
+
+// publish çeviri değişikliğini ürün güncellemesi olarak yayınlar; basket
+// servisi cache'teki ürünü düşürür
+func (s *translationService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductUpdated, ProductID: productID})
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"cluster-iac/internal/currency"
+	"cluster-iac/internal/problem"
+	"cluster-iac/internal/product/model"
+)
+
+const (
+	maxProductNameLength        = 200
+	maxProductDescriptionLength = 5000
+	maxExternalIDLength         = 100
+	maxImageURLLength           = 2048
+	maxTaxClassLength           = 32
+	maxWeightKg                 = 10000
+	maxDimensionCm              = 10000
+	// Vergi sınıfı belirtilmeyen ürünler standart oranla vergilendirilir
+	defaultTaxClass = "standard"
+	// Para birimi belirtilmeyen ürünlerin fiyatı TRY kabul edilir
+	defaultCurrency = "TRY"
+)
+
+var taxClassPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// fieldErrors alan ihlallerini toplar; hepsi tek bir Validation hatasında döner
+type fieldErrors []problem.FieldError
+
+func (f *fieldErrors) add(field, code, message string) {
+	*f = append(*f, problem.FieldError{Field: field, Code: code, Message: message})
+}
+
+// err ihlal yoksa nil döner; böylece çağıran doğrudan return edebilir
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	return problem.NewValidation(f...)
+}
+
+// validateProduct ürünün alan kurallarını kontrol eder, metin alanlarını
+// kırpar ve tüm ihlalleri tek seferde döndürür. Stock envanter
+// hareketlerinden türetildiği için gövdede gelirse yalnızca negatif olmaması
+// beklenir, değeri yine de yok sayılır. Fiyatın basamak sayısı ürünün para
+// birimine göre kontrol edilir.
+func validateProduct(product *model.Product, currencies *currency.Converter) error {
+	var v fieldErrors
+
+	product.Name = strings.TrimSpace(product.Name)
+	if code, message := nameRule(product.Name); code != "" {
+		v.add("name", code, message)
+	}
+	if utf8.RuneCountInString(product.Description) > maxProductDescriptionLength {
+		v.add("description", "too_long", fmt.Sprintf("must be at most %d characters", maxProductDescriptionLength))
+	}
+
+	product.Currency = currency.Normalize(product.Currency)
+	if product.Currency == "" {
+		product.Currency = defaultCurrency
+	}
+	places := 2
+	if code, message := currencyRule(product.Currency, currencies); code != "" {
+		v.add("currency", code, message)
+	} else {
+		places = currencies.Decimals(product.Currency)
+	}
+	if code, message := priceRule(product.Price, places); code != "" {
+		v.add("price", code, message)
+	}
+	if product.Stock < 0 {
+		v.add("stock", "negative", "must not be negative")
+	}
+	if product.ReorderThreshold < 0 {
+		v.add("reorder_threshold", "negative", "must not be negative")
+	}
+
+	product.TaxClass = strings.ToLower(strings.TrimSpace(product.TaxClass))
+	if product.TaxClass == "" {
+		product.TaxClass = defaultTaxClass
+	}
+	if code, message := taxClassRule(product.TaxClass); code != "" {
+		v.add("tax_class", code, message)
+	}
+
+	if code, message := measureRule(product.WeightKg, maxWeightKg); code != "" {
+		v.add("weight_kg", code, message)
+	}
+	for _, dimension := range []struct {
+		field string
+		value float64
+	}{{"length_cm", product.LengthCm}, {"width_cm", product.WidthCm}, {"height_cm", product.HeightCm}} {
+		if code, message := measureRule(dimension.value, maxDimensionCm); code != "" {
+			v.add(dimension.field, code, message)
+		}
+	}
+
+	product.ImageURL = strings.TrimSpace(product.ImageURL)
+	if code, message := imageURLRule(product.ImageURL); code != "" {
+		v.add("image_url", code, message)
+	}
+
+	if product.ExternalID != nil {
+		externalID := strings.TrimSpace(*product.ExternalID)
+		switch {
+		case externalID == "":
+			// Boş string "external_id yok" anlamına gelir; unique index'e boş değer yazılmaz
+			product.ExternalID = nil
+		case utf8.RuneCountInString(externalID) > maxExternalIDLength:
+			v.add("external_id", "too_long", fmt.Sprintf("must be at most %d characters", maxExternalIDLength))
+		default:
+			product.ExternalID = &externalID
+		}
+	}
+
+	return v.err()
+}
+
+// Kural fonksiyonları ihlal kodunu ve mesajını döndürür, ihlal yoksa kod
+// boştur; içe aktarma satırları da aynı kurallarla doğrulanır
+func nameRule(name string) (string, string) {
+	switch {
+	case name == "":
+		return "required", "is required"
+	case utf8.RuneCountInString(name) > maxProductNameLength:
+		return "too_long", fmt.Sprintf("must be at most %d characters", maxProductNameLength)
+	}
+	return "", ""
+}
+
+func priceRule(price float64, places int) (string, string) {
+	scale := math.Pow(10, float64(places))
+	switch {
+	case math.IsNaN(price) || math.IsInf(price, 0):
+		return "invalid", "must be a finite number"
+	case price < 0:
+		return "negative", "must not be negative"
+	case math.Abs(price*scale-math.Round(price*scale)) > 1e-6:
+		return "precision", fmt.Sprintf("must have at most %d decimal places", places)
+	}
+	return "", ""
+}
+
+// currencyRule kodun ISO 4217 biçiminde ve kur tablosunda olmasını bekler
+func currencyRule(code string, currencies *currency.Converter) (string, string) {
+	switch {
+	case !currency.ValidCode(code):
+		return "invalid", "must be a three-letter ISO 4217 code"
+	case !currencies.Supports(code):
+		return "unsupported", "has no exchange rate"
+	}
+	return "", ""
+}
+
+func taxClassRule(taxClass string) (string, string) {
+	switch {
+	case len(taxClass) > maxTaxClassLength:
+		return "too_long", fmt.Sprintf("must be at most %d characters", maxTaxClassLength)
+	case !taxClassPattern.MatchString(taxClass):
+		return "invalid", "must contain only lowercase letters, digits, '-' and '_'"
+	}
+	return "", ""
+}
+
+// measureRule ağırlık ve boyutlar için ortak kuraldır; 0 "bilinmiyor" anlamına gelir
+func measureRule(value, max float64) (string, string) {
+	switch {
+	case math.IsNaN(value) || math.IsInf(value, 0):
+		return "invalid", "must be a finite number"
+	case value < 0:
+		return "negative", "must not be negative"
+	case value > max:
+		return "too_large", fmt.Sprintf("must be at most %v", max)
+	}
+	return "", ""
+}
+
+func imageURLRule(rawURL string) (string, string) {
+	if rawURL == "" {
+		return "", ""
+	}
+	if len(rawURL) > maxImageURLLength {
+		return "too_long", fmt.Sprintf("must be at most %d characters", maxImageURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "invalid", "must be an absolute http or https URL"
+	}
+	return "", ""
+}
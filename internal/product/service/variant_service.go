@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"cluster-iac/internal/product/events"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+	"gorm.io/gorm"
+)
+
+type VariantService interface {
+	GetOptions(productID uint) ([]model.ProductOption, error)
+	ReplaceOptions(productID uint, options []model.ProductOption, actor model.Actor) error
+	GetVariants(productID uint) ([]model.ProductVariant, error)
+	CreateVariant(variant *model.ProductVariant, actor model.Actor) error
+	UpdateVariant(variant *model.ProductVariant, actor model.Actor) error
+	DeleteVariant(productID, variantID uint, actor model.Actor) error
+}
+
+type variantService struct {
+	repo        repository.VariantRepository
+	productRepo repository.ProductRepository
+	publisher   events.Publisher
+}
+
+func NewVariantService(repo repository.VariantRepository, productRepo repository.ProductRepository, publisher events.Publisher) VariantService {
+	return &variantService{
+		repo:        repo,
+		productRepo: productRepo,
+		publisher:   publisher,
+	}
+}
+
+func (s *variantService) GetOptions(productID uint) ([]model.ProductOption, error) {
+	if err := s.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOptions(productID)
+}
+
+// ReplaceOptions ürünün option eksenlerini tamamen değiştirir; mevcut varyantlar
+// yeni eksenlere uymuyorsa reddedilir
+func (s *variantService) ReplaceOptions(productID uint, options []model.ProductOption, actor model.Actor) error {
+	if err := s.checkProduct(productID); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(options))
+	for i := range options {
+		options[i].Name = strings.TrimSpace(options[i].Name)
+		if options[i].Name == "" || names[options[i].Name] || len(options[i].Values) == 0 {
+			return ErrInvalidOptions
+		}
+		names[options[i].Name] = true
+	}
+
+	variants, err := s.repo.GetByProductID(productID)
+	if err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		if !attributesMatch(options, variant.Attributes) {
+			return ErrOptionsInUse
+		}
+	}
+
+	if err := s.repo.ReplaceOptions(productID, options, actor); err != nil {
+		return err
+	}
+
+	s.publish(productID)
+	return nil
+}
+
+func (s *variantService) GetVariants(productID uint) ([]model.ProductVariant, error) {
+	if err := s.checkProduct(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProductID(productID)
+}
+
+func (s *variantService) CreateVariant(variant *model.ProductVariant, actor model.Actor) error {
+	if err := s.checkProduct(variant.ProductID); err != nil {
+		return err
+	}
+	if err := s.validate(variant); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(variant, actor); err != nil {
+		return err
+	}
+
+	s.publish(variant.ProductID)
+	return nil
+}
+
+func (s *variantService) UpdateVariant(variant *model.ProductVariant, actor model.Actor) error {
+	existing, err := s.repo.GetByID(variant.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && existing.ProductID != variant.ProductID) {
+		return ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.validate(variant); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(variant, actor); err != nil {
+		return err
+	}
+
+	s.publish(variant.ProductID)
+	return nil
+}
+
+func (s *variantService) DeleteVariant(productID, variantID uint, actor model.Actor) error {
+	existing, err := s.repo.GetByID(variantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && existing.ProductID != productID) {
+		return ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(variantID, actor); err != nil {
+		return err
+	}
+
+	s.publish(productID)
+	return nil
+}
+
+// validate SKU tekilliğini, attribute'ların option eksenlerine uyduğunu ve
+// aynı kombinasyonda başka varyant olmadığını kontrol eder
+func (s *variantService) validate(variant *model.ProductVariant) error {
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	existing, err := s.repo.GetBySKU(variant.SKU)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != variant.ID {
+		return ErrSKUTaken
+	}
+
+	options, err := s.repo.GetOptions(variant.ProductID)
+	if err != nil {
+		return err
+	}
+	if !attributesMatch(options, variant.Attributes) {
+		return ErrInvalidVariantAttributes
+	}
+
+	siblings, err := s.repo.GetByProductID(variant.ProductID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != variant.ID && sameAttributes(sibling.Attributes, variant.Attributes) {
+			return ErrDuplicateVariant
+		}
+	}
+	return nil
+}
+
+func (s *variantService) checkProduct(productID uint) error {
+	_, err := s.productRepo.GetByID(productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// Varyant değişiklikleri üst ürünün snapshot'ını değiştirdiği için ürün
+// güncellemesi olarak yayınlanır
+func (s *variantService) publish(productID uint) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(events.Event{Type: events.ProductUpdated, ProductID: productID})
+}
+
+// attributesMatch her option ekseni için izin verilen bir değer seçildiğini
+// ve fazladan attribute olmadığını kontrol eder
+func attributesMatch(options []model.ProductOption, attributes map[string]string) bool {
+	if len(options) != len(attributes) {
+		return false
+	}
+
+	for _, option := range options {
+		value, ok := attributes[option.Name]
+		if !ok {
+			return false
+		}
+
+		allowed := false
+		for _, candidate := range option.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+func sameAttributes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if b[key] != value {
+			return false
+		}
+	}
+	return true
+}
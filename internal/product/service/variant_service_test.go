@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"cluster-iac/internal/product/database"
+	"cluster-iac/internal/product/database/databasetest"
+	"cluster-iac/internal/product/model"
+	"cluster-iac/internal/product/repository"
+)
+
+func TestRecreatingDeletedVariantSKUIsRejected(t *testing.T) {
+	databasetest.Open(t)
+	db := database.ForTenant("acme")
+	productRepo := repository.NewProductRepository(db)
+	svc := NewVariantService(repository.NewVariantRepository(db), productRepo, nil)
+	actor := model.Actor{Name: "test"}
+
+	product := &model.Product{Name: "Shirt", Price: 10, Currency: "TRY"}
+	if err := productRepo.Create(product, actor); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ReplaceOptions(product.ID, []model.ProductOption{{Name: "size", Values: []string{"S", "M"}}}, actor); err != nil {
+		t.Fatal(err)
+	}
+
+	small := &model.ProductVariant{ProductID: product.ID, SKU: "SHIRT-S", Attributes: map[string]string{"size": "S"}, Price: 10}
+	if err := svc.CreateVariant(small, actor); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteVariant(product.ID, small.ID, actor); err != nil {
+		t.Fatal(err)
+	}
+
+	// Silinmiş varyantın SKU'su unique index'te kalır; yeniden kullanım 500
+	// yerine ErrSKUTaken döner
+	again := &model.ProductVariant{ProductID: product.ID, SKU: " SHIRT-S ", Attributes: map[string]string{"size": "S"}, Price: 10}
+	if err := svc.CreateVariant(again, actor); !errors.Is(err, ErrSKUTaken) {
+		t.Fatalf("recreating a deleted SKU = %v, want ErrSKUTaken", err)
+	}
+
+	medium := &model.ProductVariant{ProductID: product.ID, SKU: "SHIRT-M", Attributes: map[string]string{"size": "M"}, Price: 10}
+	if err := svc.CreateVariant(medium, actor); err != nil {
+		t.Fatal(err)
+	}
+	medium.SKU = "SHIRT-S"
+	if err := svc.UpdateVariant(medium, actor); !errors.Is(err, ErrSKUTaken) {
+		t.Fatalf("renaming to a deleted SKU = %v, want ErrSKUTaken", err)
+	}
+}
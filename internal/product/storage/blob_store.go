@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound istenen anahtarda nesne olmadığında döner
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore ürün görselleri gibi ikili nesnelerin saklandığı arka uçtur.
+// Anahtarlar "/" ile ayrılmış göreli yollardır (ör. products/12/ab12.jpg).
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL nesnenin istemcilere verilecek genel adresidir
+	URL(key string) string
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type S3Config struct {
+	// Endpoint S3 uyumlu servisin adresi (ör. http://minio:9000)
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL nesne URL'lerinin ön eki; boşsa Endpoint/Bucket kullanılır
+	PublicURL string
+}
+
+// S3Store nesneleri path-style adresleme ve AWS Signature V4 ile S3 uyumlu
+// bir servise (AWS S3, MinIO, Ceph RGW) yazar
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+}
+
+func NewS3Store(cfg S3Config) *S3Store {
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+
+	return &S3Store{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(http.MethodPut, key, resp)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrBlobNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s.responseError(http.MethodGet, key, resp)
+	}
+}
+
+// Delete S3 semantiğine uygun olarak olmayan anahtarlar için de başarılı döner
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.responseError(http.MethodDelete, key, resp)
+	}
+	return nil
+}
+
+func (s *S3Store) URL(key string) string {
+	return s.cfg.PublicURL + "/" + key
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Path = "/" + s.cfg.Bucket + "/" + key
+	endpoint.RawPath = "/" + uriEncode(s.cfg.Bucket) + "/" + uriEncodePath(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	s.sign(req, body, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign isteği AWS Signature Version 4 ile imzalar. İmzalanan başlıklar host,
+// x-amz-content-sha256 ve x-amz-date'tir; query string kullanılmaz.
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHex)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHex,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func (s *S3Store) responseError(method, key string, resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode AWS'nin istediği şekilde unreserved karakterler dışındaki her baytı kodlar
+func uriEncode(segment string) string {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func uriEncodePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
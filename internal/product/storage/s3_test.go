@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cluster-iac/internal/product/storage/storagetest"
+)
+
+func newTestS3Store(t *testing.T) (*S3Store, *storagetest.S3Stub) {
+	t.Helper()
+	stub := storagetest.NewS3Stub("media")
+	t.Cleanup(stub.Close)
+	store := NewS3Store(S3Config{
+		Endpoint:  stub.URL() + "/",
+		Bucket:    "media",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	})
+	return store, stub
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	store, stub := newTestS3Store(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "products/12/ab12.png", []byte("png-bytes"), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	data, contentType, ok := stub.Object("products/12/ab12.png")
+	if !ok || string(data) != "png-bytes" || contentType != "image/png" {
+		t.Fatalf("stored object = %q %q %v", data, contentType, ok)
+	}
+
+	reader, err := store.Get(ctx, "products/12/ab12.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(got) != "png-bytes" {
+		t.Fatalf("Get = %q", got)
+	}
+
+	if err := store.Delete(ctx, "products/12/ab12.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "products/12/ab12.png"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("Get after delete = %v, want ErrBlobNotFound", err)
+	}
+	// Olmayan anahtarı silmek hata değildir
+	if err := store.Delete(ctx, "products/12/missing.png"); err != nil {
+		t.Fatalf("Delete missing: %v", err)
+	}
+}
+
+func TestS3StoreKeyLayout(t *testing.T) {
+	store, stub := newTestS3Store(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "products/7/a b+c.jpg", []byte("x"), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Path-style adresleme: /bucket/anahtar, segmentler ayrı ayrı kodlanır
+	requests := stub.Requests()
+	if len(requests) != 1 || requests[0] != "PUT /media/products/7/a%20b%2Bc.jpg" {
+		t.Fatalf("requests = %v", requests)
+	}
+	if keys := stub.Keys(); len(keys) != 1 || keys[0] != "products/7/a b+c.jpg" {
+		t.Fatalf("keys = %v", keys)
+	}
+
+	if got, want := store.URL("products/7/x.jpg"), stub.URL()+"/media/products/7/x.jpg"; got != want {
+		t.Fatalf("URL = %q, want %q", got, want)
+	}
+	cdn := NewS3Store(S3Config{Endpoint: stub.URL(), Bucket: "media", PublicURL: "https://cdn.example.com/"})
+	if got := cdn.URL("products/7/x.jpg"); got != "https://cdn.example.com/products/7/x.jpg" {
+		t.Fatalf("URL with PublicURL = %q", got)
+	}
+}
+
+func TestS3StoreReportsErrors(t *testing.T) {
+	store, _ := newTestS3Store(t)
+	wrongBucket := NewS3Store(S3Config{Endpoint: store.cfg.Endpoint, Bucket: "other", AccessKey: "a", SecretKey: "b"})
+
+	err := wrongBucket.Put(context.Background(), "products/1/a.png", []byte("x"), "image/png")
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Fatalf("Put to unknown bucket = %v", err)
+	}
+}
+
+func TestS3StoreSignsRequests(t *testing.T) {
+	store := NewS3Store(S3Config{Endpoint: "http://minio:9000", Region: "eu-central-1", Bucket: "media", AccessKey: "AKIDEXAMPLE", SecretKey: "secret"})
+	body := []byte("payload")
+	req, _ := http.NewRequest(http.MethodPut, "http://minio:9000/media/products/1/a.png", nil)
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store.sign(req, body, now)
+
+	payloadHash := sha256.Sum256(body)
+	if got := req.Header.Get("x-amz-content-sha256"); got != hex.EncodeToString(payloadHash[:]) {
+		t.Fatalf("x-amz-content-sha256 = %q", got)
+	}
+	if got := req.Header.Get("x-amz-date"); got != "20260102T030405Z" {
+		t.Fatalf("x-amz-date = %q", got)
+	}
+	authorization := req.Header.Get("Authorization")
+	prefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260102/eu-central-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="
+	if !strings.HasPrefix(authorization, prefix) || len(authorization) != len(prefix)+64 {
+		t.Fatalf("Authorization = %q", authorization)
+	}
+
+	// Aynı istek aynı imzayı, farklı gövde farklı imzayı üretir
+	again, _ := http.NewRequest(http.MethodPut, req.URL.String(), nil)
+	store.sign(again, body, now)
+	other, _ := http.NewRequest(http.MethodPut, req.URL.String(), nil)
+	store.sign(other, []byte("changed"), now)
+	if again.Header.Get("Authorization") != authorization || other.Header.Get("Authorization") == authorization {
+		t.Fatal("signature is not derived from the request")
+	}
+}
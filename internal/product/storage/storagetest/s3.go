@@ -0,0 +1,114 @@
+// Package storagetest blob store sürücülerini test etmek için yardımcılardır
+package storagetest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// S3Stub path-style PUT/GET/DELETE isteklerini bellekte karşılayan MinIO
+// benzeri yerel bir sunucudur; storage.S3Store'u gerçek bir servis olmadan
+// test etmek için kullanılır. İmzayı doğrulamaz, yalnızca varlığını kontrol eder.
+type S3Stub struct {
+	server *httptest.Server
+	bucket string
+
+	mu       sync.Mutex
+	objects  map[string]stubObject
+	requests []string
+}
+
+type stubObject struct {
+	data        []byte
+	contentType string
+}
+
+func NewS3Stub(bucket string) *S3Stub {
+	stub := &S3Stub{bucket: bucket, objects: make(map[string]stubObject)}
+	stub.server = httptest.NewServer(http.HandlerFunc(stub.handle))
+	return stub
+}
+
+func (s *S3Stub) URL() string {
+	return s.server.URL
+}
+
+// Object saklanan nesneyi döndürür; ok false ise nesne yoktur
+func (s *S3Stub) Object(key string) (data []byte, contentType string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	return object.data, object.contentType, ok
+}
+
+// Keys saklanan nesnelerin anahtarlarıdır
+func (s *S3Stub) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Requests stub'a gelen isteklerin "METHOD /yol" biçimindeki listesidir
+func (s *S3Stub) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *S3Stub) Close() {
+	s.server.Close()
+}
+
+func (s *S3Stub) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.EscapedPath())
+	s.mu.Unlock()
+
+	prefix := "/" + s.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = stubObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
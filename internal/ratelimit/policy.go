@@ -0,0 +1,231 @@
+// Package ratelimit gateway'in token bucket hız sınırlarıdır. Her route bir
+// kurala eşlenir; istek, kuralın limitiyle IP ya da API anahtarı başına
+// tutulan bucket'tan bir token harcar. Bucket'lar Redis'te
+// tutulur, böylece tüm gateway'ler aynı sayaçları paylaşır.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+//go:embed default_limits.json
+var defaultLimits []byte
+
+// DefaultRule hiçbir route kuralına uymayan isteklerin kuralıdır
+const DefaultRule = "default"
+
+// Limit Per süresinde Rate token ekleyen, en fazla Burst token biriktiren bir bucket'tır
+type Limit struct {
+	Rate  int           `json:"rate"`
+	Per   time.Duration `json:"-"`
+	Burst int           `json:"burst"`
+}
+
+func (l *Limit) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Rate  int    `json:"rate"`
+		Per   string `json:"per"`
+		Burst int    `json:"burst"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	l.Rate, l.Burst, l.Per = raw.Rate, raw.Burst, time.Second
+	if raw.Per != "" {
+		per, err := time.ParseDuration(raw.Per)
+		if err != nil {
+			return fmt.Errorf("invalid per %q: %w", raw.Per, err)
+		}
+		l.Per = per
+	}
+	return nil
+}
+
+func (l Limit) validate(where string) error {
+	if l.Rate <= 0 || l.Per < time.Millisecond {
+		return fmt.Errorf("rate limit %s: rate and per must be positive", where)
+	}
+	if l.Burst < 1 {
+		return fmt.Errorf("rate limit %s: burst must be at least 1", where)
+	}
+	return nil
+}
+
+// perMillisecond bucket'a milisaniyede eklenen token sayısıdır
+func (l Limit) perMillisecond() float64 {
+	return float64(l.Rate) / float64(l.Per.Milliseconds())
+}
+
+// Rule bir route grubunun limitidir. Paths path.Match kalıplarıdır ("*" bir
+// path parçasına uyar); Method boşsa tüm metodlara uyar.
+type Rule struct {
+	Name   string   `json:"name"`
+	Method string   `json:"method"`
+	Paths  []string `json:"paths"`
+	Limit  Limit    `json:"limit"`
+}
+
+// APIKey gateway'e tanıtılmış bir istemcidir. Anahtarın kendisi değil SHA-256
+// özeti saklanır. Limits kural adına göre anahtara özel kotalardır; kotası
+// olmayan kurallarda kuralın kendi limiti geçerlidir. UserClass, gateway'in
+// bu anahtarla gelen sepet isteklerine verdiği saklama sınıfıdır.
+type APIKey struct {
+	Name      string           `json:"name"`
+	KeySHA256 string           `json:"key_sha256"`
+	Limits    map[string]Limit `json:"limits"`
+	UserClass string           `json:"user_class,omitempty"`
+}
+
+// Policy gateway'in tüm limitleridir; kurallar dosyadaki sırayla denenir
+type Policy struct {
+	Default Limit    `json:"default"`
+	Routes  []Rule   `json:"routes"`
+	APIKeys []APIKey `json:"api_keys"`
+
+	keys map[string]*APIKey
+}
+
+// LoadPolicy path boşsa gömülü varsayılan limitleri yükler
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultLimits
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse rate limits: %w", err)
+	}
+	if err := policy.normalize(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (p *Policy) normalize() error {
+	if err := p.Default.validate(DefaultRule); err != nil {
+		return err
+	}
+
+	names := map[string]bool{DefaultRule: true}
+	for i := range p.Routes {
+		rule := &p.Routes[i]
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.Method = strings.ToUpper(strings.TrimSpace(rule.Method))
+		if rule.Name == "" || names[rule.Name] {
+			return fmt.Errorf("rate limit route %d: name %q is empty or used twice", i+1, rule.Name)
+		}
+		names[rule.Name] = true
+		if len(rule.Paths) == 0 {
+			return fmt.Errorf("rate limit %s: at least one path is required", rule.Name)
+		}
+		for _, pattern := range rule.Paths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rate limit %s: invalid path %q", rule.Name, pattern)
+			}
+		}
+		if err := rule.Limit.validate(rule.Name); err != nil {
+			return err
+		}
+	}
+
+	p.keys = make(map[string]*APIKey, len(p.APIKeys))
+	for i := range p.APIKeys {
+		key := &p.APIKeys[i]
+		digest := strings.ToLower(strings.TrimSpace(key.KeySHA256))
+		if key.Name == "" {
+			return fmt.Errorf("rate limit api key %d: name is required", i+1)
+		}
+		if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+			return fmt.Errorf("rate limit api key %s: key_sha256 must be a hex SHA-256 digest", key.Name)
+		}
+		if _, ok := p.keys[digest]; ok {
+			return fmt.Errorf("rate limit api key %s: key is used twice", key.Name)
+		}
+		for name, limit := range key.Limits {
+			if !names[name] {
+				return fmt.Errorf("rate limit api key %s: unknown rule %q", key.Name, name)
+			}
+			if err := limit.validate(key.Name + "/" + name); err != nil {
+				return err
+			}
+		}
+		p.keys[digest] = key
+	}
+	return nil
+}
+
+// Match isteğe uyan ilk route kuralını, yoksa varsayılan kuralı döndürür
+func (p *Policy) Match(method, requestPath string) Rule {
+	if len(requestPath) > 1 {
+		requestPath = strings.TrimSuffix(requestPath, "/")
+	}
+	for _, rule := range p.Routes {
+		if rule.Method != "" && rule.Method != method {
+			continue
+		}
+		for _, pattern := range rule.Paths {
+			if matched, _ := path.Match(pattern, requestPath); matched {
+				return rule
+			}
+		}
+	}
+	return Rule{Name: DefaultRule, Limit: p.Default}
+}
+
+// Client isteği yapanın kimlikleridir; gateway başlıklardan ve bağlantıdan
+// doldurur. Gateway kullanıcıları doğrulamadığı için kullanıcı kimliği
+// (X-User-ID) yoktur: istemcinin gönderdiği bir kimliğe göre bucket tutmak,
+// başka bir kullanıcının adına isteyip onun limitini tüketmeye izin verirdi.
+type Client struct {
+	APIKey string
+	IP     string
+}
+
+// Bucket bir isteğin harcadığı tek bir sayaçtır
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Buckets isteğin rule kuralında harcayacağı bucket'ları döndürür. Tanınan
+// bir API anahtarı kendi bucket'ını, diğer istekler IP bucket'ını kullanır;
+// tanınmayan bir API anahtarı yok sayılır. İkinci değer, tanınan anahtarın
+// adıdır.
+func (p *Policy) Buckets(rule Rule, client Client) ([]Bucket, string) {
+	if key, ok := p.Key(client.APIKey); ok {
+		limit := rule.Limit
+		if quota, ok := key.Limits[rule.Name]; ok {
+			limit = quota
+		}
+		return []Bucket{{Key: bucketKey(rule, "key", key.Name), Limit: limit}}, key.Name
+	}
+
+	return []Bucket{{Key: bucketKey(rule, "ip", client.IP), Limit: rule.Limit}}, ""
+}
+
+// Key isteğin X-API-Key değerine karşılık gelen tanınan anahtarı döndürür
+func (p *Policy) Key(apiKey string) (*APIKey, bool) {
+	if apiKey == "" {
+		return nil, false
+	}
+	digest := sha256.Sum256([]byte(apiKey))
+	key, ok := p.keys[hex.EncodeToString(digest[:])]
+	return key, ok
+}
+
+func bucketKey(rule Rule, kind, id string) string {
+	return rule.Name + ":" + kind + ":" + id
+}
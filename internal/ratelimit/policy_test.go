@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePolicy(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMatch(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		method, path, want string
+	}{
+		{"POST", "/api/products/import", "import"},
+		{"POST", "/products/import/", "import"},
+		{"GET", "/api/products/import", DefaultRule},
+		{"GET", "/api/products/export", "export"},
+		{"POST", "/api/products/12/images", "image-upload"},
+		// "*" tek bir path parçasına uyar
+		{"POST", "/api/products/12/images/3", DefaultRule},
+		{"GET", "/api/products", DefaultRule},
+		{"GET", "/", DefaultRule},
+	} {
+		if rule := policy.Match(tc.method, tc.path); rule.Name != tc.want {
+			t.Errorf("Match(%s %s) = %q, want %q", tc.method, tc.path, rule.Name, tc.want)
+		}
+	}
+	if rule := policy.Match("GET", "/anything"); rule.Limit != policy.Default {
+		t.Fatalf("default rule limit = %+v, want %+v", rule.Limit, policy.Default)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	digest := sha256.Sum256([]byte("partner-secret"))
+	policy, err := LoadPolicy(writePolicy(t, fmt.Sprintf(`{
+		"default": {"rate": 10, "per": "1s", "burst": 20},
+		"routes": [{"name": "import", "method": "post", "paths": ["/import"], "limit": {"rate": 2, "per": "1m", "burst": 2}}],
+		"api_keys": [{"name": "partner", "key_sha256": %q, "limits": {"import": {"rate": 20, "per": "1m", "burst": 5}}}]
+	}`, strings.ToUpper(hex.EncodeToString(digest[:])))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	importRule := policy.Match("POST", "/import")
+	defaultRule := policy.Match("GET", "/")
+
+	for _, tc := range []struct {
+		name    string
+		rule    Rule
+		client  Client
+		want    Bucket
+		keyName string
+	}{
+		{"anonymous", importRule, Client{IP: "10.0.0.1"}, Bucket{"import:ip:10.0.0.1", Limit{2, time.Minute, 2}}, ""},
+		{"unknown key", importRule, Client{APIKey: "guessed", IP: "10.0.0.1"}, Bucket{"import:ip:10.0.0.1", Limit{2, time.Minute, 2}}, ""},
+		{"key quota", importRule, Client{APIKey: "partner-secret", IP: "10.0.0.1"}, Bucket{"import:key:partner", Limit{20, time.Minute, 5}}, "partner"},
+		{"key without quota", defaultRule, Client{APIKey: "partner-secret", IP: "10.0.0.1"}, Bucket{"default:key:partner", Limit{10, time.Second, 20}}, "partner"},
+	} {
+		buckets, keyName := policy.Buckets(tc.rule, tc.client)
+		if len(buckets) != 1 || buckets[0] != tc.want || keyName != tc.keyName {
+			t.Errorf("%s: Buckets = %+v, %q, want [%+v], %q", tc.name, buckets, keyName, tc.want, tc.keyName)
+		}
+	}
+}
+
+func TestLoadPolicyRejectsInvalidLimits(t *testing.T) {
+	digest := sha256.Sum256([]byte("k"))
+	key := hex.EncodeToString(digest[:])
+	for name, data := range map[string]string{
+		"zero burst":     `{"default": {"rate": 1, "burst": 0}}`,
+		"bad per":        `{"default": {"rate": 1, "per": "soon", "burst": 1}}`,
+		"duplicate rule": `{"default": {"rate": 1, "burst": 1}, "routes": [{"name": "default", "paths": ["/"], "limit": {"rate": 1, "burst": 1}}]}`,
+		"bad pattern":    `{"default": {"rate": 1, "burst": 1}, "routes": [{"name": "a", "paths": ["/["], "limit": {"rate": 1, "burst": 1}}]}`,
+		"bad digest":     `{"default": {"rate": 1, "burst": 1}, "api_keys": [{"name": "a", "key_sha256": "abc"}]}`,
+		"unknown rule":   fmt.Sprintf(`{"default": {"rate": 1, "burst": 1}, "api_keys": [{"name": "a", "key_sha256": %q, "limits": {"nope": {"rate": 1, "burst": 1}}}]}`, key),
+		"reused key":     fmt.Sprintf(`{"default": {"rate": 1, "burst": 1}, "api_keys": [{"name": "a", "key_sha256": %q}, {"name": "b", "key_sha256": %q}]}`, key, key),
+	} {
+		if _, err := LoadPolicy(writePolicy(t, data)); err == nil {
+			t.Errorf("%s: policy was accepted", name)
+		}
+	}
+}
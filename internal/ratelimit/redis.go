@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Result isteğin sonucu ve en kısıtlayıcı bucket'ın durumudur
+type Result struct {
+	Allowed bool
+	// Limit en kısıtlayıcı bucket'ın kapasitesidir (burst)
+	Limit     int
+	Remaining int
+	// Reset bucket'ın yeniden dolmasına kalan süredir
+	Reset time.Duration
+	// RetryAfter reddedilen isteğin tekrar denenebileceği süredir
+	RetryAfter time.Duration
+}
+
+// Limiter bucket'lardan birer token harcar; bucket'lardan biri boşsa hiçbirinden harcamaz
+type Limiter interface {
+	Allow(ctx context.Context, buckets []Bucket) (Result, error)
+}
+
+// takeScript bucket'ları tek adımda doldurur, hepsinde token varsa birer
+// token harcar ve her bucket için {kalan, dolma ms, bekleme ms} döndürür.
+// Saat Redis'in saatidir, böylece gateway'lerin saat farkı sayaçları
+// etkilemez. Kullanılmayan bucket dolduğunda anahtar silinir.
+var takeScript = redis.NewScript(`
+local now = redis.call('TIME')
+now = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+
+local tokens = {}
+local allowed = 1
+for i = 1, #KEYS do
+  local rate = tonumber(ARGV[i * 2 - 1])
+  local burst = tonumber(ARGV[i * 2])
+  local state = redis.call('HMGET', KEYS[i], 'tokens', 'ts')
+  local level = tonumber(state[1])
+  local ts = tonumber(state[2])
+  if level == nil or ts == nil then
+    level = burst
+  else
+    level = math.min(burst, level + math.max(0, now - ts) * rate)
+  end
+  tokens[i] = level
+  if level < 1 then
+    allowed = 0
+  end
+end
+
+local result = {allowed}
+for i = 1, #KEYS do
+  local rate = tonumber(ARGV[i * 2 - 1])
+  local burst = tonumber(ARGV[i * 2])
+  if allowed == 1 then
+    tokens[i] = tokens[i] - 1
+  end
+  local full = math.ceil((burst - tokens[i]) / rate)
+  redis.call('HSET', KEYS[i], 'tokens', tostring(tokens[i]), 'ts', now)
+  redis.call('PEXPIRE', KEYS[i], full + 1000)
+
+  local retry = 0
+  if allowed == 0 and tokens[i] < 1 then
+    retry = math.ceil((1 - tokens[i]) / rate)
+  end
+  table.insert(result, math.floor(tokens[i]))
+  table.insert(result, full)
+  table.insert(result, retry)
+end
+return result
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisLimiter(client *redis.Client, prefix string) Limiter {
+	return &redisLimiter{client: client, prefix: prefix}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, buckets []Bucket) (Result, error) {
+	if len(buckets) == 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	keys := make([]string, len(buckets))
+	args := make([]interface{}, 0, len(buckets)*2)
+	for i, bucket := range buckets {
+		keys[i] = l.prefix + bucket.Key
+		args = append(args, strconv.FormatFloat(bucket.Limit.perMillisecond(), 'g', -1, 64), bucket.Limit.Burst)
+	}
+
+	values, err := takeScript.Run(ctx, l.client, keys, args...).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 1+len(buckets)*3 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	result := Result{Allowed: values[0] == 1, Remaining: math.MaxInt}
+	for i, bucket := range buckets {
+		remaining, full, retry := int(values[1+i*3]), values[2+i*3], values[3+i*3]
+		if remaining < result.Remaining {
+			result.Limit = bucket.Limit.Burst
+			result.Remaining = remaining
+			result.Reset = time.Duration(full) * time.Millisecond
+		}
+		if wait := time.Duration(retry) * time.Millisecond; wait > result.RetryAfter {
+			result.RetryAfter = wait
+		}
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	return result, nil
+}
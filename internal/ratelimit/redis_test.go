@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+type limiterFixture struct {
+	mr      *miniredis.Miniredis
+	now     time.Time
+	limiter Limiter
+}
+
+func newLimiterFixture(t *testing.T) *limiterFixture {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	// Script saati Redis'in TIME komutundan okur
+	f := &limiterFixture{mr: mr, now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), limiter: NewRedisLimiter(client, "ratelimit:test:")}
+	mr.SetTime(f.now)
+	return f
+}
+
+func (f *limiterFixture) advance(d time.Duration) {
+	f.now = f.now.Add(d)
+	f.mr.SetTime(f.now)
+}
+
+func (f *limiterFixture) allow(t *testing.T, buckets ...Bucket) Result {
+	t.Helper()
+	result, err := f.limiter.Allow(context.Background(), buckets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestRedisLimiterRefillsAndReportsRetryAfter(t *testing.T) {
+	f := newLimiterFixture(t)
+	bucket := Bucket{Key: "default:ip:10.0.0.1", Limit: Limit{Rate: 1, Per: time.Second, Burst: 2}}
+
+	first := f.allow(t, bucket)
+	if !first.Allowed || first.Limit != 2 || first.Remaining != 1 || first.Reset != time.Second {
+		t.Fatalf("first = %+v", first)
+	}
+	if second := f.allow(t, bucket); !second.Allowed || second.Remaining != 0 || second.Reset != 2*time.Second {
+		t.Fatalf("second = %+v", second)
+	}
+
+	rejected := f.allow(t, bucket)
+	if rejected.Allowed || rejected.Remaining != 0 || rejected.RetryAfter != time.Second {
+		t.Fatalf("rejected = %+v", rejected)
+	}
+	f.advance(600 * time.Millisecond)
+	if rejected := f.allow(t, bucket); rejected.Allowed || rejected.RetryAfter != 400*time.Millisecond {
+		t.Fatalf("rejected after 600ms = %+v", rejected)
+	}
+
+	f.advance(400 * time.Millisecond)
+	if refilled := f.allow(t, bucket); !refilled.Allowed || refilled.Remaining != 0 {
+		t.Fatalf("after refill = %+v", refilled)
+	}
+
+	// Bucket burst'ten fazla dolmaz
+	f.advance(time.Hour)
+	if full := f.allow(t, bucket); !full.Allowed || full.Remaining != 1 {
+		t.Fatalf("after an idle hour = %+v", full)
+	}
+}
+
+func TestRedisLimiterTakesFromAllBucketsOrNone(t *testing.T) {
+	f := newLimiterFixture(t)
+	tight := Bucket{Key: "import:ip:10.0.0.1", Limit: Limit{Rate: 1, Per: time.Minute, Burst: 1}}
+	loose := Bucket{Key: "import:key:partner", Limit: Limit{Rate: 10, Per: time.Second, Burst: 5}}
+
+	first := f.allow(t, tight, loose)
+	// Sonuç en kısıtlayıcı bucket'ın durumudur
+	if !first.Allowed || first.Limit != 1 || first.Remaining != 0 || first.Reset != time.Minute {
+		t.Fatalf("first = %+v", first)
+	}
+
+	rejected := f.allow(t, tight, loose)
+	if rejected.Allowed || rejected.RetryAfter != time.Minute {
+		t.Fatalf("rejected = %+v", rejected)
+	}
+	// Reddedilen istek dolu bucket'tan token harcamaz
+	if alone := f.allow(t, loose); !alone.Allowed || alone.Remaining != 3 {
+		t.Fatalf("loose bucket after rejection = %+v, want 3 remaining", alone)
+	}
+
+	if empty := f.allow(t); !empty.Allowed {
+		t.Fatalf("no buckets = %+v", empty)
+	}
+}
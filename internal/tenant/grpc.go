@@ -0,0 +1,38 @@
+package tenant
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor context'teki tenant'ı giden çağrının metadata'sına yazar
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor stream açan çağrılar (ör. WatchProducts) içindir
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoing(ctx context.Context) context.Context {
+	if id, ok := FromContext(ctx); ok {
+		return metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+	}
+	return ctx
+}
+
+// FromIncomingContext gelen çağrının metadata'sındaki tenant değerini
+// döndürür; doğrulama Set.Resolve ile yapılır
+func FromIncomingContext(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+// Package tenant aynı kurulumda barındırılan mağazaları (tenant) ayırt eder.
+// Gateway tenant'ı host adından ya da X-Tenant-ID başlığından çözer ve
+// servislere her zaman başlıkla iletir; servisler arası gRPC çağrılarında
+// tenant metadata ile taşınır. Servis içinde tenant context'te tutulur.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"cluster-iac/internal/problem"
+)
+
+const (
+	// HeaderName servislere iletilen tenant başlığıdır
+	HeaderName = "X-Tenant-ID"
+	// MetadataKey gRPC çağrılarında tenant'ı taşıyan metadata anahtarıdır
+	MetadataKey = "x-tenant-id"
+	// Default tenant belirtilmeyen isteklerin ve tenant öncesi verinin tenant'ıdır
+	Default = "default"
+)
+
+// Redis anahtarlarında ve kolon değerlerinde güvenle kullanılabilen id'ler
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext context'teki tenant'ı döndürür; tenant yoksa ok false'tur
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ID context'teki tenant'ı, yoksa Default'u döndürür
+func ID(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return Default
+}
+
+// Set kurulumda tanımlı tenant'lardır; Default her zaman dahildir
+type Set struct {
+	ids   []string
+	known map[string]bool
+}
+
+// ParseSet "acme,globex" biçimindeki listeyi doğrular
+func ParseSet(list string) (*Set, error) {
+	s := &Set{ids: []string{Default}, known: map[string]bool{Default: true}}
+	for _, id := range strings.Split(list, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+		if !Valid(id) {
+			return nil, fmt.Errorf("invalid tenant id %q", id)
+		}
+		if !s.known[id] {
+			s.known[id] = true
+			s.ids = append(s.ids, id)
+		}
+	}
+	return s, nil
+}
+
+// IDs Default başta olmak üzere tanımlı tenant'lardır
+func (s *Set) IDs() []string {
+	return append([]string(nil), s.ids...)
+}
+
+func (s *Set) Has(id string) bool {
+	return s.known[id]
+}
+
+// Resolve istekte gelen tenant değerini doğrular; boş değer Default'tur.
+// Biçimi bozuk id Invalid, tanımsız tenant NotFound hatası döner.
+func (s *Set) Resolve(value string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(value))
+	if id == "" {
+		return Default, nil
+	}
+	if !Valid(id) {
+		return "", problem.New(problem.Invalid, fmt.Sprintf("invalid tenant id %q", value))
+	}
+	if !s.known[id] {
+		return "", problem.New(problem.NotFound, fmt.Sprintf("tenant %q not found", id))
+	}
+	return id, nil
+}
+
+// Middleware X-Tenant-ID başlığını çözüp tenant'ı istek context'ine koyar.
+// Başlık gateway tarafından her zaman yazılır; doğrudan gelen başlıksız
+// istekler Default tenant'a düşer.
+func (s *Set) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := s.Resolve(r.Header.Get(HeaderName))
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// ParseHosts "shop.acme.com=acme,globex.example=globex" biçimindeki host
+// eşlemelerini döndürür; host'lar küçük harfe çevrilir, port yazılmaz
+func ParseHosts(list string, tenants *Set) (map[string]string, error) {
+	hosts := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		host, id, ok := strings.Cut(pair, "=")
+		host = strings.ToLower(strings.TrimSpace(host))
+		id = strings.ToLower(strings.TrimSpace(id))
+		if !ok || host == "" || !tenants.Has(id) {
+			return nil, fmt.Errorf("invalid tenant host mapping %q", pair)
+		}
+		hosts[host] = id
+	}
+	return hosts, nil
+}